@@ -4,7 +4,7 @@ import (
 	"context"
 	"reflect"
 
-	"github.com/dogmatiq/configkit/internal/typename/goreflect"
+	"github.com/dogmatiq/configkit/typename"
 	"github.com/dogmatiq/dogma"
 	"github.com/dogmatiq/enginekit/message"
 )
@@ -28,15 +28,33 @@ type RichAggregate interface {
 //
 // It panics if the handler is configured incorrectly. Use Recover() to convert
 // configuration related panic values to errors.
-func FromAggregate(h dogma.AggregateMessageHandler) RichAggregate {
-	cfg := fromAggregateUnvalidated(h)
+func FromAggregate(h dogma.AggregateMessageHandler, options ...ConfigureOption) RichAggregate {
+	cfg := fromAggregateUnvalidated(h, newConfigureOptions(options))
 	cfg.mustValidate()
 	return cfg
 }
 
-func fromAggregateUnvalidated(h dogma.AggregateMessageHandler) *richAggregate {
-	cfg := &richAggregate{handler: h}
-	h.Configure(&aggregateConfigurer{cfg})
+func fromAggregateUnvalidated(
+	h dogma.AggregateMessageHandler,
+	opts configureOptions,
+) *richAggregate {
+	cfg := &richAggregate{handler: h, module: opts.moduleName()}
+
+	opts.configure(
+		cfg.ReflectType(),
+		func() PanicEntity {
+			return PanicEntity{AggregateHandlerType, cfg.ident, cfg.TypeName()}
+		},
+		func() {
+			c := &aggregateConfigurer{config: cfg}
+			defer c.end()
+			h.Configure(c)
+		},
+	)
+
+	cfg.names = asMessageNames(cfg.types)
+	cfg.ident, cfg.metadata = opts.resolve(cfg.ident)
+
 	return cfg
 }
 
@@ -44,6 +62,9 @@ func fromAggregateUnvalidated(h dogma.AggregateMessageHandler) *richAggregate {
 type richAggregate struct {
 	ident      Identity
 	types      EntityMessages[message.Type]
+	names      EntityMessages[message.Name]
+	metadata   Metadata
+	module     string
 	isDisabled bool
 	handler    dogma.AggregateMessageHandler
 }
@@ -53,15 +74,23 @@ func (h *richAggregate) Identity() Identity {
 }
 
 func (h *richAggregate) MessageNames() EntityMessages[message.Name] {
-	return asMessageNames(h.types)
+	return h.names
 }
 
 func (h *richAggregate) MessageTypes() EntityMessages[message.Type] {
 	return h.types
 }
 
+func (h *richAggregate) Metadata() Metadata {
+	return h.metadata
+}
+
+func (h *richAggregate) moduleName() string {
+	return h.module
+}
+
 func (h *richAggregate) TypeName() string {
-	return goreflect.NameOf(h.ReflectType())
+	return typename.FromReflect(h.ReflectType())
 }
 
 func (h *richAggregate) ReflectType() reflect.Type {
@@ -93,7 +122,7 @@ func (h *richAggregate) isConfigured() bool {
 }
 
 func (h *richAggregate) mustValidate() {
-	mustHaveValidIdentity(h.Identity(), h.ReflectType())
+	mustHaveValidIdentity(h.Identity(), h.ReflectType(), inModule(h))
 	mustHaveConsumerRoute(&h.types, message.CommandKind, h.Identity(), h.ReflectType())
 	mustHaveProducerRoute(&h.types, message.EventKind, h.Identity(), h.ReflectType())
 }
@@ -101,17 +130,21 @@ func (h *richAggregate) mustValidate() {
 // aggregateConfigurer is the default implementation of
 // [dogma.AggregateConfigurer].
 type aggregateConfigurer struct {
+	configurerLifecycle
 	config *richAggregate
 }
 
 func (c *aggregateConfigurer) Identity(name, key string) {
-	configureIdentity(&c.config.ident, name, key, c.config.ReflectType())
+	c.guard(c.config.ReflectType(), "Identity")
+	configureIdentity(&c.config.ident, name, key, c.config.ReflectType(), inModule(c.config))
 }
 
 func (c *aggregateConfigurer) Routes(routes ...dogma.AggregateRoute) {
+	c.guard(c.config.ReflectType(), "Routes")
 	configureRoutes(&c.config.types, routes, c.config.ident, c.config.ReflectType())
 }
 
 func (c *aggregateConfigurer) Disable(...dogma.DisableOption) {
+	c.guard(c.config.ReflectType(), "Disable")
 	c.config.isDisabled = true
 }
@@ -13,6 +13,8 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
 var _ = Context("end-to-end tests", func() {
@@ -23,6 +25,7 @@ var _ = Context("end-to-end tests", func() {
 		cfg1, cfg2 configkit.Application
 		listener   net.Listener
 		gserver    *grpc.Server
+		conn       *grpc.ClientConn
 		client     *Client
 	)
 
@@ -85,22 +88,25 @@ var _ = Context("end-to-end tests", func() {
 			},
 		}
 
-		cfg1 = configkit.FromApplication(app1)
+		cfg1 = configkit.FromApplication(app1, configkit.WithMetadata(configkit.MetadataSet{
+			"b1101bbf-8a62-436d-9044-e6fd3d0e5385": {configkit.OwnerMetadataKey: "<team>"},
+			"938b829d-e4d7-4780-bf06-ea349453ba8f": {configkit.DataClassificationMetadataKey: "pii"},
+		}))
 		cfg2 = configkit.FromApplication(app2)
 
 		var err error
 		listener, err = net.Listen("tcp", ":")
 		Expect(err).ShouldNot(HaveOccurred())
 
+		server := NewServer(cfg1, cfg2)
+
 		gserver = grpc.NewServer()
-		configgrpc.RegisterConfigAPIServer(
-			gserver,
-			NewServer(cfg1, cfg2),
-		)
+		configgrpc.RegisterConfigAPIServer(gserver, server)
+		RegisterMetadataAPIServer(gserver, server)
 
 		go gserver.Serve(listener)
 
-		conn, err := grpc.Dial(
+		conn, err = grpc.Dial(
 			listener.Addr().String(),
 			grpc.WithInsecure(),
 		)
@@ -159,10 +165,89 @@ var _ = Context("end-to-end tests", func() {
 			}
 		})
 
+		It("returns the metadata of the applications and handlers", func() {
+			configs, err := client.ListApplications(ctx)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			for _, cfg := range configs {
+				if cfg.Identity() == cfg1.Identity() {
+					Expect(configkit.CollectMetadata(cfg)).To(Equal(configkit.CollectMetadata(cfg1)))
+					return
+				}
+			}
+
+			Fail("application not found in response")
+		})
+
 		It("returns an error if the gRPC call fails", func() {
 			gserver.Stop()
 			_, err := client.ListApplications(ctx)
 			Expect(err).Should(HaveOccurred())
 		})
 	})
+
+	Describe("GetMetadata gRPC method", func() {
+		It("serves the metadata to clients that do not use the configkit client", func() {
+			res := &structpb.Struct{}
+			err := conn.Invoke(ctx, GetMetadataMethod, &emptypb.Empty{}, res)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(res.AsMap()).To(Equal(map[string]any{
+				"b1101bbf-8a62-436d-9044-e6fd3d0e5385": map[string]any{
+					configkit.OwnerMetadataKey: "<team>",
+				},
+				"938b829d-e4d7-4780-bf06-ea349453ba8f": map[string]any{
+					configkit.DataClassificationMetadataKey: "pii",
+				},
+			}))
+		})
+	})
+
+	Describe("func ListApplicationsIfModified()", func() {
+		It("returns the application configurations if the client has no fingerprint", func() {
+			configs, latest, modified, err := client.ListApplicationsIfModified(ctx, nil)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(modified).To(BeTrue())
+			Expect(configs).To(HaveLen(2))
+			Expect(latest).NotTo(BeEmpty())
+		})
+
+		It("returns the metadata of the applications and handlers", func() {
+			configs, _, _, err := client.ListApplicationsIfModified(ctx, nil)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			for _, cfg := range configs {
+				if cfg.Identity() == cfg1.Identity() {
+					Expect(configkit.Fingerprint(cfg)).To(Equal(configkit.Fingerprint(cfg1)))
+					return
+				}
+			}
+
+			Fail("application not found in response")
+		})
+
+		It("does not return the application configurations if the fingerprint is unchanged", func() {
+			_, latest, _, err := client.ListApplicationsIfModified(ctx, nil)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			configs, again, modified, err := client.ListApplicationsIfModified(ctx, latest)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(modified).To(BeFalse())
+			Expect(configs).To(BeEmpty())
+			Expect(again).To(Equal(latest))
+		})
+
+		It("returns the application configurations if the fingerprint has changed", func() {
+			configs, latest, modified, err := client.ListApplicationsIfModified(ctx, []byte("<stale>"))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(modified).To(BeTrue())
+			Expect(configs).To(HaveLen(2))
+			Expect(latest).NotTo(Equal([]byte("<stale>")))
+		})
+
+		It("returns an error if the gRPC call fails", func() {
+			gserver.Stop()
+			_, _, _, err := client.ListApplicationsIfModified(ctx, nil)
+			Expect(err).Should(HaveOccurred())
+		})
+	})
 })
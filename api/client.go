@@ -1,11 +1,20 @@
 package api
 
 import (
+	"bytes"
 	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
 
 	"github.com/dogmatiq/configkit"
 	"github.com/dogmatiq/enginekit/grpc/configgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
 // Client wraps a [configgrpc.ConfigAPIClient] to unmarshal the server's
@@ -13,12 +22,19 @@ import (
 // [configkit.Application] and [configkit.Handler] interfaces.
 type Client struct {
 	Client configgrpc.ConfigAPIClient
+
+	// Conn, if non-nil, is the connection used to call [GetMetadataMethod] in
+	// order to attach metadata to the configurations. If it is nil, or the
+	// server does not implement the [MetadataAPIServiceName] service, the
+	// configurations have no metadata.
+	Conn grpc.ClientConnInterface
 }
 
 // NewClient returns a new configuration client for the given connection.
 func NewClient(conn grpc.ClientConnInterface) *Client {
 	return &Client{
 		configgrpc.NewConfigAPIClient(conn),
+		conn,
 	}
 }
 
@@ -27,15 +43,143 @@ func NewClient(conn grpc.ClientConnInterface) *Client {
 func (c *Client) ListApplications(
 	ctx context.Context,
 ) ([]configkit.Application, error) {
-	req := &configgrpc.ListApplicationsRequest{}
-	res, err := c.Client.ListApplications(ctx, req)
+	configs, _, _, err := c.ListApplicationsIfModified(ctx, nil)
+	return configs, err
+}
+
+// ListApplicationsIfModified returns the configurations of the applications
+// hosted by the server, unless they have the given fingerprint.
+//
+// fingerprint is the fingerprint returned by a prior call, or nil if the client
+// does not yet have a configuration. If the configuration on the server has
+// the same fingerprint, modified is false and configs is nil.
+//
+// latest is the fingerprint of the server's current configuration. It is nil
+// if the server does not support fingerprints, in which case the
+// configurations are always returned.
+func (c *Client) ListApplicationsIfModified(
+	ctx context.Context,
+	fingerprint []byte,
+) (configs []configkit.Application, latest []byte, modified bool, err error) {
+	if fingerprint != nil {
+		ctx = metadata.AppendToOutgoingContext(
+			ctx,
+			IfNoneMatchHeader,
+			hex.EncodeToString(fingerprint),
+		)
+	}
+
+	// The configuration may change between the call to ListApplications()
+	// and the call to GetMetadata(), in which case the metadata does not
+	// belong to the listed applications and both calls are retried.
+	for range maxListAttempts {
+		var header metadata.MD
+		req := &configgrpc.ListApplicationsRequest{}
+		res, err := c.Client.ListApplications(ctx, req, grpc.Header(&header))
+		if err != nil {
+			return nil, nil, false, err
+		}
+
+		latest, err = fingerprintFromHeader(header)
+		if err != nil {
+			return nil, nil, false, err
+		}
+
+		if latest != nil && bytes.Equal(latest, fingerprint) {
+			return nil, latest, false, nil
+		}
+
+		md, ok, err := c.metadata(ctx, latest)
+		if err != nil {
+			return nil, nil, false, err
+		} else if !ok {
+			continue
+		}
+
+		configs, err = unmarshalApplications(res, md)
+		if err != nil {
+			return nil, nil, false, err
+		}
+
+		return configs, latest, true, nil
+	}
+
+	return nil, nil, false, errors.New("the configuration changed while it was being listed")
+}
+
+// maxListAttempts is the maximum number of times that
+// [Client.ListApplicationsIfModified] lists the applications in order to
+// obtain metadata that belongs to those applications.
+const maxListAttempts = 3
+
+// metadata returns the metadata of the applications hosted by the server.
+//
+// latest is the fingerprint of the listed applications. ok is false if the
+// metadata belongs to a configuration with a different fingerprint.
+func (c *Client) metadata(
+	ctx context.Context,
+	latest []byte,
+) (_ configkit.MetadataSet, ok bool, _ error) {
+	if c.Conn == nil {
+		return nil, true, nil
+	}
+
+	var header metadata.MD
+	res := &structpb.Struct{}
+	if err := c.Conn.Invoke(
+		ctx,
+		GetMetadataMethod,
+		&emptypb.Empty{},
+		res,
+		grpc.Header(&header),
+	); status.Code(err) == codes.Unimplemented {
+		return nil, true, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+
+	fp, err := fingerprintFromHeader(header)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !bytes.Equal(fp, latest) {
+		return nil, false, nil
+	}
+
+	md, err := unmarshalMetadata(res)
 	if err != nil {
-		return nil, err
+		return nil, false, fmt.Errorf("invalid configuration metadata: %w", err)
+	}
+
+	return md, true, nil
+}
+
+// fingerprintFromHeader returns the fingerprint in the [FingerprintHeader]
+// gRPC header, or nil if it is not present.
+func fingerprintFromHeader(header metadata.MD) ([]byte, error) {
+	values := header.Get(FingerprintHeader)
+	if len(values) == 0 {
+		return nil, nil
 	}
 
+	fp, err := hex.DecodeString(values[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration fingerprint: %w", err)
+	}
+
+	return fp, nil
+}
+
+// unmarshalApplications unmarshals the applications in res, attaching the
+// metadata in md.
+func unmarshalApplications(
+	res *configgrpc.ListApplicationsResponse,
+	md configkit.MetadataSet,
+) ([]configkit.Application, error) {
 	var configs []configkit.Application
 	for _, in := range res.GetApplications() {
-		out, err := configkit.FromProto(in)
+		out, err := configkit.FromProto(in, configkit.WithMetadata(md))
 		if err != nil {
 			return nil, err
 		}
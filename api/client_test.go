@@ -2,15 +2,23 @@ package api_test
 
 import (
 	"context"
+	"fmt"
 	"net"
+	"sync/atomic"
 	"time"
 
+	"github.com/dogmatiq/configkit"
 	. "github.com/dogmatiq/configkit/api"
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
 	"github.com/dogmatiq/enginekit/grpc/configgrpc"
 	"github.com/dogmatiq/enginekit/protobuf/configpb"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
 type invalidServer struct{}
@@ -26,16 +34,74 @@ func (s *invalidServer) ListApplications(
 	}, nil
 }
 
+type invalidMetadataServer struct{}
+
+func (s *invalidMetadataServer) ListApplications(
+	context.Context,
+	*configgrpc.ListApplicationsRequest,
+) (*configgrpc.ListApplicationsResponse, error) {
+	return &configgrpc.ListApplicationsResponse{}, nil
+}
+
+func (s *invalidMetadataServer) GetMetadata(
+	context.Context,
+	*emptypb.Empty,
+) (*structpb.Struct, error) {
+	return structpb.NewStruct(map[string]any{
+		"<not a key>": map[string]any{},
+	})
+}
+
+// changingServer is a server whose configuration changes each time its
+// metadata is requested, until it has changed the given number of times.
+type changingServer struct {
+	changes atomic.Int32
+}
+
+func (s *changingServer) ListApplications(
+	ctx context.Context,
+	_ *configgrpc.ListApplicationsRequest,
+) (*configgrpc.ListApplicationsResponse, error) {
+	fp := fmt.Sprintf("%02x", s.changes.Load())
+	if err := grpc.SetHeader(ctx, metadata.Pairs(FingerprintHeader, fp)); err != nil {
+		return nil, err
+	}
+
+	return &configgrpc.ListApplicationsResponse{}, nil
+}
+
+func (s *changingServer) GetMetadata(
+	ctx context.Context,
+	_ *emptypb.Empty,
+) (*structpb.Struct, error) {
+	n := s.changes.Load()
+	if n > 0 {
+		n = s.changes.Add(-1)
+	}
+
+	fp := fmt.Sprintf("%02x", n)
+	if err := grpc.SetHeader(ctx, metadata.Pairs(FingerprintHeader, fp)); err != nil {
+		return nil, err
+	}
+
+	return &structpb.Struct{}, nil
+}
+
 var _ = Describe("type Client", func() {
 	var (
 		ctx      context.Context
 		cancel   func()
+		server   configgrpc.ConfigAPIServer
 		listener net.Listener
 		gserver  *grpc.Server
 		client   *Client
 	)
 
 	BeforeEach(func() {
+		server = &invalidServer{}
+	})
+
+	JustBeforeEach(func() {
 		ctx, cancel = context.WithTimeout(context.Background(), 1*time.Second)
 
 		var err error
@@ -43,7 +109,10 @@ var _ = Describe("type Client", func() {
 		Expect(err).ShouldNot(HaveOccurred())
 
 		gserver = grpc.NewServer()
-		configgrpc.RegisterConfigAPIServer(gserver, &invalidServer{})
+		configgrpc.RegisterConfigAPIServer(gserver, server)
+		if s, ok := server.(MetadataAPIServer); ok {
+			RegisterMetadataAPIServer(gserver, s)
+		}
 
 		go gserver.Serve(listener)
 
@@ -73,5 +142,78 @@ var _ = Describe("type Client", func() {
 			_, err := client.ListApplications(ctx)
 			Expect(err).Should(HaveOccurred())
 		})
+
+		When("the server returns invalid metadata", func() {
+			BeforeEach(func() {
+				server = &invalidMetadataServer{}
+			})
+
+			It("returns an error", func() {
+				_, err := client.ListApplications(ctx)
+				Expect(err).To(MatchError(HavePrefix("invalid configuration metadata: ")))
+			})
+		})
+
+		When("the server does not implement the metadata API", func() {
+			BeforeEach(func() {
+				app := configkit.FromApplication(
+					&ApplicationStub{
+						ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+							c.Identity("<app>", "b1101bbf-8a62-436d-9044-e6fd3d0e5385")
+						},
+					},
+					configkit.WithMetadata(configkit.MetadataSet{
+						"b1101bbf-8a62-436d-9044-e6fd3d0e5385": {configkit.OwnerMetadataKey: "<team>"},
+					}),
+				)
+
+				server = struct{ configgrpc.ConfigAPIServer }{NewServer(app)}
+			})
+
+			It("returns the applications without metadata", func() {
+				configs, err := client.ListApplications(ctx)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(configs).To(HaveLen(1))
+				Expect(configkit.CollectMetadata(configs[0])).To(BeEmpty())
+			})
+		})
+	})
+
+	Describe("func ListApplicationsIfModified()", func() {
+		When("the server returns invalid metadata", func() {
+			BeforeEach(func() {
+				server = &invalidMetadataServer{}
+			})
+
+			It("returns an error", func() {
+				_, _, _, err := client.ListApplicationsIfModified(ctx, nil)
+				Expect(err).To(MatchError(HavePrefix("invalid configuration metadata: ")))
+			})
+		})
+
+		When("the configuration changes between listing the applications and requesting their metadata", func() {
+			var changing *changingServer
+
+			BeforeEach(func() {
+				changing = &changingServer{}
+				server = changing
+			})
+
+			It("lists the applications again", func() {
+				changing.changes.Store(2)
+
+				_, latest, modified, err := client.ListApplicationsIfModified(ctx, nil)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(modified).To(BeTrue())
+				Expect(latest).To(Equal([]byte{0}))
+			})
+
+			It("returns an error if the configuration does not stop changing", func() {
+				changing.changes.Store(3)
+
+				_, _, _, err := client.ListApplicationsIfModified(ctx, nil)
+				Expect(err).To(MatchError("the configuration changed while it was being listed"))
+			})
+		})
 	})
 })
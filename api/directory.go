@@ -0,0 +1,272 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/dogmatiq/configkit"
+	"github.com/dogmatiq/configkit/snapshot"
+	"github.com/dogmatiq/enginekit/protobuf/configpb"
+	"google.golang.org/protobuf/proto"
+)
+
+// LoadDirectory loads the configurations of the applications in the files
+// within dir.
+//
+// Files with a ".json" extension must contain a snapshot, as written by
+// [snapshot.Write]. Files with a ".pb" or ".binpb" extension must contain a
+// single application in the binary protocol buffers format, as produced by
+// [configkit.ToProto], which does not include metadata. All other files, and any sub-directories, are ignored.
+// Symbolic links are followed, so that Kubernetes ConfigMap volumes can be
+// loaded directly.
+//
+// It returns an error if any of the files can not be loaded, or if more than
+// one file contains an application with the same key.
+func LoadDirectory(dir string) ([]configkit.Application, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var apps []configkit.Application
+	sources := map[string]string{}
+
+	for _, entry := range entries {
+		if !isConfigFile(entry.Name()) {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+
+		if _, ok, err := statRegularFile(path); err != nil {
+			return nil, err
+		} else if !ok {
+			continue
+		}
+
+		loaded, err := loadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, app := range loaded {
+			key := app.Identity().Key
+
+			if p, ok := sources[key]; ok {
+				return nil, fmt.Errorf(
+					"%s: application %s is also defined in %s",
+					path,
+					app.Identity(),
+					p,
+				)
+			}
+
+			sources[key] = path
+			apps = append(apps, app)
+		}
+	}
+
+	return apps, nil
+}
+
+// isConfigFile returns true if name has one of the extensions of the files
+// that [LoadDirectory] loads.
+func isConfigFile(name string) bool {
+	switch filepath.Ext(name) {
+	case ".json", ".pb", ".binpb":
+		return true
+	default:
+		return false
+	}
+}
+
+// loadFile loads the applications in the file at the given path, based on its
+// extension.
+func loadFile(path string) ([]configkit.Application, error) {
+	switch filepath.Ext(path) {
+	case ".json":
+		s, err := snapshot.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return s.Applications, nil
+
+	case ".pb", ".binpb":
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		apb := &configpb.Application{}
+		if err := proto.Unmarshal(data, apb); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		app, err := configkit.FromProto(apb)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		return []configkit.Application{app}, nil
+
+	default:
+		return nil, nil
+	}
+}
+
+// NewDirectoryServer returns an API server that serves the configurations of
+// the applications in the files within dir.
+//
+// See [LoadDirectory] for a description of the supported files. Use a
+// [DirectoryWatcher] to reload the configurations when the files change.
+func NewDirectoryServer(dir string) (*Server, error) {
+	apps, err := LoadDirectory(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{}
+	if err := s.Update(apps...); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// DefaultPollInterval is the default interval at which a [DirectoryWatcher]
+// checks for changes.
+const DefaultPollInterval = 1 * time.Second
+
+// DirectoryWatcher updates the applications served by a [Server] when the
+// files in a directory change.
+type DirectoryWatcher struct {
+	// Server is the server to update.
+	Server *Server
+
+	// Dir is the directory that contains the files, as per [LoadDirectory].
+	Dir string
+
+	// PollInterval is the interval at which the directory is checked for
+	// changes. If it is non-positive, [DefaultPollInterval] is used.
+	PollInterval time.Duration
+
+	// OnError, if non-nil, is called when the directory can not be loaded.
+	// The server continues to serve the previous configurations until the
+	// files change again and the directory is loaded successfully.
+	OnError func(error)
+}
+
+// Run loads the directory and updates the server, then continues to do so
+// each time the files in the directory change, until ctx is canceled.
+func (w *DirectoryWatcher) Run(ctx context.Context) error {
+	interval := w.PollInterval
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var prev []fileInfo
+
+	for {
+		next, err := w.listing()
+
+		if err != nil {
+			w.handleError(err)
+		} else if prev == nil || !slices.Equal(prev, next) {
+			prev = next
+
+			if err := w.reload(); err != nil {
+				w.handleError(err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// reload loads the directory and updates the server.
+func (w *DirectoryWatcher) reload() error {
+	apps, err := LoadDirectory(w.Dir)
+	if err != nil {
+		return err
+	}
+
+	return w.Server.Update(apps...)
+}
+
+func (w *DirectoryWatcher) handleError(err error) {
+	if w.OnError != nil {
+		w.OnError(err)
+	}
+}
+
+// fileInfo is the information about a file that is used to detect changes.
+type fileInfo struct {
+	Name    string
+	Size    int64
+	ModTime int64
+}
+
+// listing returns information about each file in the directory that is loaded
+// by [LoadDirectory], in order of their names.
+//
+// Other files are excluded, so that changes to unrelated files, such as those
+// created by text editors, do not cause the directory to be reloaded.
+func (w *DirectoryWatcher) listing() ([]fileInfo, error) {
+	entries, err := os.ReadDir(w.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []fileInfo{}
+
+	for _, entry := range entries {
+		if !isConfigFile(entry.Name()) {
+			continue
+		}
+
+		info, ok, err := statRegularFile(filepath.Join(w.Dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		} else if !ok {
+			continue
+		}
+
+		files = append(files, fileInfo{
+			entry.Name(),
+			info.Size(),
+			info.ModTime().UnixNano(),
+		})
+	}
+
+	return files, nil
+}
+
+// statRegularFile returns information about the file at the given path,
+// following symbolic links, such as those used by Kubernetes to mount
+// ConfigMap volumes.
+//
+// ok is false if the path does not refer to a regular file, including if it
+// has been removed since the directory was read.
+func statRegularFile(path string) (_ fs.FileInfo, ok bool, _ error) {
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+
+	return info, info.Mode().IsRegular(), nil
+}
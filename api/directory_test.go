@@ -0,0 +1,327 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/dogmatiq/configkit"
+	. "github.com/dogmatiq/configkit/api"
+	"github.com/dogmatiq/configkit/snapshot"
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	"github.com/dogmatiq/enginekit/grpc/configgrpc"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"google.golang.org/protobuf/proto"
+)
+
+var _ = Describe("directory-backed servers", func() {
+	var (
+		dir        string
+		app1, app2 configkit.Application
+	)
+
+	writeSnapshot := func(name string, apps ...configkit.Application) {
+		var buf bytes.Buffer
+		err := snapshot.Write(&buf, snapshot.New(apps...))
+		Expect(err).ShouldNot(HaveOccurred())
+
+		err = os.WriteFile(filepath.Join(dir, name), buf.Bytes(), 0o600)
+		Expect(err).ShouldNot(HaveOccurred())
+	}
+
+	writeProto := func(name string, app configkit.Application) {
+		apb, err := configkit.ToProto(app)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		data, err := proto.Marshal(apb)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		err = os.WriteFile(filepath.Join(dir, name), data, 0o600)
+		Expect(err).ShouldNot(HaveOccurred())
+	}
+
+	// writeConfigMap writes a snapshot of the given applications to a file
+	// named "apps.json" using the same layout of symbolic links that
+	// Kubernetes uses to mount ConfigMap volumes, replacing any existing
+	// version of the file.
+	writeConfigMap := func(version string, apps ...configkit.Application) {
+		err := os.Mkdir(filepath.Join(dir, version), 0o700)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		writeSnapshot(filepath.Join(version, "apps.json"), apps...)
+
+		err = os.Symlink(version, filepath.Join(dir, "..data_tmp"))
+		Expect(err).ShouldNot(HaveOccurred())
+
+		err = os.Rename(filepath.Join(dir, "..data_tmp"), filepath.Join(dir, "..data"))
+		Expect(err).ShouldNot(HaveOccurred())
+
+		err = os.Symlink(filepath.Join("..data", "apps.json"), filepath.Join(dir, "apps.json"))
+		if !os.IsExist(err) {
+			Expect(err).ShouldNot(HaveOccurred())
+		}
+	}
+
+	listKeys := func(s *Server) []string {
+		res, err := s.ListApplications(
+			context.Background(),
+			&configgrpc.ListApplicationsRequest{},
+		)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		var keys []string
+		for _, app := range res.GetApplications() {
+			out, err := configkit.FromProto(app)
+			Expect(err).ShouldNot(HaveOccurred())
+			keys = append(keys, out.Identity().Key)
+		}
+
+		return keys
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "configkit-api-")
+		Expect(err).ShouldNot(HaveOccurred())
+
+		app1 = configkit.FromApplication(&ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app-1>", "b1101bbf-8a62-436d-9044-e6fd3d0e5385")
+				c.Routes(
+					dogma.ViaAggregate(&AggregateMessageHandlerStub{
+						ConfigureFunc: func(c dogma.AggregateConfigurer) {
+							c.Identity("<aggregate>", "938b829d-e4d7-4780-bf06-ea349453ba8f")
+							c.Routes(
+								dogma.HandlesCommand[*CommandStub[TypeA]](),
+								dogma.RecordsEvent[*EventStub[TypeA]](),
+							)
+						},
+					}),
+				)
+			},
+		})
+
+		app2 = configkit.FromApplication(&ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app-2>", "7d3927ce-d879-40a4-bd67-0fafc79d3c36")
+			},
+		})
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	Describe("func LoadDirectory()", func() {
+		It("loads applications from snapshots and protocol buffers files", func() {
+			writeSnapshot("app-1.json", app1)
+			writeProto("app-2.pb", app2)
+
+			err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("ignored"), 0o600)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			apps, err := LoadDirectory(dir)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(apps).To(HaveLen(2))
+			Expect(configkit.IsApplicationEqual(apps[0], app1)).To(BeTrue())
+			Expect(configkit.IsApplicationEqual(apps[1], app2)).To(BeTrue())
+		})
+
+		It("follows symbolic links", func() {
+			writeConfigMap("..v1", app1, app2)
+
+			apps, err := LoadDirectory(dir)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(apps).To(HaveLen(2))
+		})
+
+		It("returns an error if more than one file defines the same application", func() {
+			writeSnapshot("a.json", app1)
+			writeProto("b.pb", app1)
+
+			_, err := LoadDirectory(dir)
+			Expect(err).To(MatchError(
+				filepath.Join(dir, "b.pb") +
+					": application <app-1>/b1101bbf-8a62-436d-9044-e6fd3d0e5385 is also defined in " +
+					filepath.Join(dir, "a.json"),
+			))
+		})
+
+		It("returns an error if a file is invalid", func() {
+			err := os.WriteFile(filepath.Join(dir, "app.pb"), []byte("<invalid>"), 0o600)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			_, err = LoadDirectory(dir)
+			Expect(err).To(MatchError(HavePrefix(filepath.Join(dir, "app.pb") + ": ")))
+		})
+
+		It("returns an error if the directory does not exist", func() {
+			_, err := LoadDirectory(filepath.Join(dir, "missing"))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("func NewDirectoryServer()", func() {
+		It("serves the applications in the directory", func() {
+			writeSnapshot("apps.json", app1, app2)
+
+			s, err := NewDirectoryServer(dir)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(listKeys(s)).To(ConsistOf(
+				app1.Identity().Key,
+				app2.Identity().Key,
+			))
+		})
+
+		It("returns an error if the directory can not be loaded", func() {
+			_, err := NewDirectoryServer(filepath.Join(dir, "missing"))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("type DirectoryWatcher", func() {
+		var (
+			ctx    context.Context
+			cancel context.CancelFunc
+			server *Server
+			result chan error
+			mutex  sync.Mutex
+			errs   []error
+		)
+
+		BeforeEach(func() {
+			ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+
+			writeSnapshot("app-1.json", app1)
+
+			var err error
+			server, err = NewDirectoryServer(dir)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			errs = nil
+			w := &DirectoryWatcher{
+				Server:       server,
+				Dir:          dir,
+				PollInterval: 5 * time.Millisecond,
+				OnError: func(err error) {
+					mutex.Lock()
+					defer mutex.Unlock()
+					errs = append(errs, err)
+				},
+			}
+
+			r := make(chan error, 1)
+			result = r
+
+			go func() {
+				r <- w.Run(ctx)
+			}()
+		})
+
+		AfterEach(func() {
+			cancel()
+			Eventually(result).Should(Receive())
+		})
+
+		It("reloads the applications when a file is added", func() {
+			writeProto("app-2.pb", app2)
+
+			Eventually(func() []string {
+				return listKeys(server)
+			}).Should(ConsistOf(
+				app1.Identity().Key,
+				app2.Identity().Key,
+			))
+		})
+
+		It("reloads the applications when a file is removed", func() {
+			err := os.Remove(filepath.Join(dir, "app-1.json"))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Eventually(func() []string {
+				return listKeys(server)
+			}).Should(BeEmpty())
+		})
+
+		It("reloads the applications when a symbolic link is updated", func() {
+			writeConfigMap("..v1", app2)
+
+			Eventually(func() []string {
+				return listKeys(server)
+			}).Should(ConsistOf(
+				app1.Identity().Key,
+				app2.Identity().Key,
+			))
+
+			writeConfigMap("..v2")
+
+			Eventually(func() []string {
+				return listKeys(server)
+			}).Should(ConsistOf(
+				app1.Identity().Key,
+			))
+		})
+
+		It("does not reload the applications when an unrelated file changes", func() {
+			writeProto("app-2.pb", app2)
+
+			Eventually(func() []string {
+				return listKeys(server)
+			}).Should(ConsistOf(
+				app1.Identity().Key,
+				app2.Identity().Key,
+			))
+
+			// Replace the content of app-1.json without changing its size or
+			// modification time, such that the directory can no longer be
+			// loaded, but the watcher does not notice the change.
+			path := filepath.Join(dir, "app-1.json")
+
+			info, err := os.Stat(path)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			err = os.WriteFile(path, bytes.Repeat([]byte(" "), int(info.Size())), 0o600)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			err = os.Chtimes(path, info.ModTime(), info.ModTime())
+			Expect(err).ShouldNot(HaveOccurred())
+
+			err = os.WriteFile(filepath.Join(dir, ".DS_Store"), []byte("<unrelated>"), 0o600)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Consistently(func() []error {
+				mutex.Lock()
+				defer mutex.Unlock()
+				return errs
+			}, 100*time.Millisecond).Should(BeEmpty())
+		})
+
+		It("continues to serve the previous applications if the directory can not be loaded", func() {
+			err := os.WriteFile(filepath.Join(dir, "app-2.pb"), []byte("<invalid>"), 0o600)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Eventually(func() int {
+				mutex.Lock()
+				defer mutex.Unlock()
+				return len(errs)
+			}).Should(BeNumerically(">", 0))
+
+			Expect(listKeys(server)).To(ConsistOf(app1.Identity().Key))
+		})
+
+		It("returns when the context is canceled", func() {
+			cancel()
+
+			var err error
+			Eventually(result).Should(Receive(&err))
+			Expect(err).To(Equal(context.Canceled))
+
+			result <- err // allow AfterEach to receive the result
+		})
+	})
+})
@@ -1,2 +1,7 @@
-// Package api exposes application configurations via a gRPC API.
+// Package api exposes application configurations via a gRPC API, and via a
+// JSON API over plain HTTP.
+//
+// The configurations may be those of the applications hosted by an engine, or
+// they may be loaded from files on disk, allowing the API to be served without
+// a running engine, such as during local development and testing.
 package api
@@ -0,0 +1,286 @@
+package api
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dogmatiq/configkit"
+	"github.com/dogmatiq/enginekit/message"
+)
+
+// NewHTTPHandler returns an HTTP handler that serves the same application
+// configurations as s, encoded as JSON.
+//
+// It serves the following endpoints:
+//
+//	GET /applications                                  list all applications
+//	GET /applications/{app}                            get a single application
+//	GET /applications/{app}/handlers/{handler}         get a single handler
+//	GET /messages                                      list all messages
+//
+// Applications and handlers are identified by their keys. Each response
+// includes an ETag header, and conditional requests that use the
+// If-None-Match header are supported.
+func NewHTTPHandler(s *Server) http.Handler {
+	h := &httpHandler{s}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /applications", h.listApplications)
+	mux.HandleFunc("GET /applications/{app}", h.getApplication)
+	mux.HandleFunc("GET /applications/{app}/handlers/{handler}", h.getHandler)
+	mux.HandleFunc("GET /messages", h.listMessages)
+
+	return mux
+}
+
+// httpHandler implements the endpoints served by [NewHTTPHandler].
+type httpHandler struct {
+	server *Server
+}
+
+func (h *httpHandler) listApplications(w http.ResponseWriter, r *http.Request) {
+	st := h.server.load()
+
+	res := jsonApplicationList{
+		Applications: []jsonApplication{},
+	}
+
+	for _, app := range sortedApplications(st.apps) {
+		res.Applications = append(res.Applications, marshalJSONApplication(app))
+	}
+
+	writeJSON(w, r, st.fingerprint, res)
+}
+
+func (h *httpHandler) getApplication(w http.ResponseWriter, r *http.Request) {
+	app, ok := h.application(w, r)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, r, configkit.Fingerprint(app), marshalJSONApplication(app))
+}
+
+func (h *httpHandler) getHandler(w http.ResponseWriter, r *http.Request) {
+	app, ok := h.application(w, r)
+	if !ok {
+		return
+	}
+
+	handler, ok := app.Handlers().ByKey(r.PathValue("handler"))
+	if !ok {
+		writeJSONError(w, http.StatusNotFound, "handler not found")
+		return
+	}
+
+	writeJSON(w, r, configkit.HandlerFingerprint(handler), marshalJSONHandler(handler))
+}
+
+func (h *httpHandler) listMessages(w http.ResponseWriter, r *http.Request) {
+	st := h.server.load()
+
+	res := jsonMessageList{
+		Messages: []jsonMessage{},
+	}
+
+	catalog := configkit.NewMessageCatalog(st.apps...)
+
+	for n, u := range catalog.Sorted() {
+		m := jsonMessage{
+			Name:      string(n),
+			Kind:      u.Kind.String(),
+			Producers: []jsonApplicationHandler{},
+			Consumers: []jsonApplicationHandler{},
+		}
+
+		for _, x := range u.Producers {
+			m.Producers = append(m.Producers, marshalJSONApplicationHandler(x))
+		}
+
+		for _, x := range u.Consumers {
+			m.Consumers = append(m.Consumers, marshalJSONApplicationHandler(x))
+		}
+
+		res.Messages = append(res.Messages, m)
+	}
+
+	writeJSON(w, r, st.fingerprint, res)
+}
+
+// application returns the application identified by the request's path. If
+// there is no such application it writes an error response and returns false.
+func (h *httpHandler) application(
+	w http.ResponseWriter,
+	r *http.Request,
+) (configkit.Application, bool) {
+	key := r.PathValue("app")
+
+	for _, app := range h.server.load().apps {
+		if app.Identity().Key == key {
+			return app, true
+		}
+	}
+
+	writeJSONError(w, http.StatusNotFound, "application not found")
+	return nil, false
+}
+
+// writeJSON writes v to w as JSON.
+//
+// fingerprint is the fingerprint of the configuration that v describes. It is
+// used as the response's ETag, allowing clients to avoid re-downloading
+// unchanged configurations.
+func writeJSON(w http.ResponseWriter, r *http.Request, fingerprint []byte, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", `"`+hex.EncodeToString(fingerprint)+`"`)
+
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(data))
+}
+
+// writeJSONError writes an error response to w.
+func writeJSONError(w http.ResponseWriter, code int, message string) {
+	data, _ := json.Marshal(jsonError{message})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	w.Write(data)
+}
+
+// sortedApplications returns a copy of apps, ordered by name, then by key.
+func sortedApplications(apps []configkit.Application) []configkit.Application {
+	apps = slices.Clone(apps)
+
+	slices.SortFunc(apps, func(a, b configkit.Application) int {
+		x, y := a.Identity(), b.Identity()
+		if c := strings.Compare(x.Name, y.Name); c != 0 {
+			return c
+		}
+		return strings.Compare(x.Key, y.Key)
+	})
+
+	return apps
+}
+
+type jsonError struct {
+	Error string `json:"error"`
+}
+
+type jsonApplicationList struct {
+	Applications []jsonApplication `json:"applications"`
+}
+
+type jsonApplication struct {
+	Name     string            `json:"name"`
+	Key      string            `json:"key"`
+	GoType   string            `json:"go_type"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+	Handlers []jsonHandler     `json:"handlers"`
+}
+
+type jsonHandler struct {
+	Name     string             `json:"name"`
+	Key      string             `json:"key"`
+	Type     string             `json:"type"`
+	GoType   string             `json:"go_type"`
+	Disabled bool               `json:"disabled"`
+	Metadata map[string]string  `json:"metadata,omitempty"`
+	Messages []jsonMessageUsage `json:"messages"`
+}
+
+type jsonMessageUsage struct {
+	Name     string `json:"name"`
+	Kind     string `json:"kind"`
+	Produced bool   `json:"produced"`
+	Consumed bool   `json:"consumed"`
+}
+
+type jsonMessageList struct {
+	Messages []jsonMessage `json:"messages"`
+}
+
+type jsonMessage struct {
+	Name      string                   `json:"name"`
+	Kind      string                   `json:"kind"`
+	Producers []jsonApplicationHandler `json:"producers"`
+	Consumers []jsonApplicationHandler `json:"consumers"`
+}
+
+type jsonApplicationHandler struct {
+	Application string `json:"application"`
+	Key         string `json:"key"`
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+}
+
+func marshalJSONApplication(app configkit.Application) jsonApplication {
+	id := app.Identity()
+
+	out := jsonApplication{
+		Name:     id.Name,
+		Key:      id.Key,
+		GoType:   app.TypeName(),
+		Metadata: app.Metadata(),
+		Handlers: []jsonHandler{},
+	}
+
+	for h := range app.Handlers().Sorted() {
+		out.Handlers = append(out.Handlers, marshalJSONHandler(h))
+	}
+
+	return out
+}
+
+func marshalJSONHandler(h configkit.Handler) jsonHandler {
+	id := h.Identity()
+
+	out := jsonHandler{
+		Name:     id.Name,
+		Key:      id.Key,
+		Type:     h.HandlerType().String(),
+		GoType:   h.TypeName(),
+		Disabled: h.IsDisabled(),
+		Metadata: h.Metadata(),
+		Messages: []jsonMessageUsage{},
+	}
+
+	names := h.MessageNames()
+	sorted := make([]message.Name, 0, len(names))
+	for n := range names {
+		sorted = append(sorted, n)
+	}
+	slices.Sort(sorted)
+
+	for _, n := range sorted {
+		em := names[n]
+		out.Messages = append(out.Messages, jsonMessageUsage{
+			Name:     string(n),
+			Kind:     em.Kind.String(),
+			Produced: em.IsProduced,
+			Consumed: em.IsConsumed,
+		})
+	}
+
+	return out
+}
+
+func marshalJSONApplicationHandler(x configkit.ApplicationHandler) jsonApplicationHandler {
+	id := x.Handler.Identity()
+
+	return jsonApplicationHandler{
+		Application: x.Application.Key,
+		Key:         id.Key,
+		Name:        id.Name,
+		Type:        x.Handler.HandlerType().String(),
+	}
+}
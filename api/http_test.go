@@ -0,0 +1,319 @@
+package api_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/dogmatiq/configkit"
+	. "github.com/dogmatiq/configkit/api"
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("func NewHTTPHandler()", func() {
+	var (
+		handler    http.Handler
+		app1, app2 configkit.Application
+	)
+
+	BeforeEach(func() {
+		app1 = configkit.FromApplication(&ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app-1>", "b1101bbf-8a62-436d-9044-e6fd3d0e5385")
+				c.Routes(
+					dogma.ViaAggregate(&AggregateMessageHandlerStub{
+						ConfigureFunc: func(c dogma.AggregateConfigurer) {
+							c.Identity("<aggregate>", "938b829d-e4d7-4780-bf06-ea349453ba8f")
+							c.Routes(
+								dogma.HandlesCommand[*CommandStub[TypeA]](),
+								dogma.RecordsEvent[*EventStub[TypeA]](),
+							)
+						},
+					}),
+				)
+			},
+		})
+
+		app2 = configkit.FromApplication(&ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app-2>", "7d3927ce-d879-40a4-bd67-0fafc79d3c36")
+				c.Routes(
+					dogma.ViaProjection(&ProjectionMessageHandlerStub{
+						ConfigureFunc: func(c dogma.ProjectionConfigurer) {
+							c.Identity("<projection>", "280a58bd-f154-46d7-863b-23ce70e49d2a")
+							c.Routes(
+								dogma.HandlesEvent[*EventStub[TypeA]](),
+							)
+							c.Disable()
+						},
+					}),
+				)
+			},
+		})
+
+		handler = NewHTTPHandler(NewServer(app2, app1))
+	})
+
+	get := func(path string, header ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		for i := 0; i < len(header); i += 2 {
+			req.Header.Set(header[i], header[i+1])
+		}
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		return w
+	}
+
+	Describe("GET /applications", func() {
+		It("returns the applications ordered by name", func() {
+			w := get("/applications")
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(w.Header().Get("Content-Type")).To(Equal("application/json"))
+			Expect(w.Body.String()).To(MatchJSON(`{
+				"applications": [
+					{
+						"name": "<app-1>",
+						"key": "b1101bbf-8a62-436d-9044-e6fd3d0e5385",
+						"go_type": "*github.com/dogmatiq/enginekit/enginetest/stubs.ApplicationStub",
+						"handlers": [
+							{
+								"name": "<aggregate>",
+								"key": "938b829d-e4d7-4780-bf06-ea349453ba8f",
+								"type": "aggregate",
+								"go_type": "*github.com/dogmatiq/enginekit/enginetest/stubs.AggregateMessageHandlerStub",
+								"disabled": false,
+								"messages": [
+									{
+										"name": "*github.com/dogmatiq/enginekit/enginetest/stubs.CommandStub[github.com/dogmatiq/enginekit/enginetest/stubs.TypeA]",
+										"kind": "command",
+										"produced": false,
+										"consumed": true
+									},
+									{
+										"name": "*github.com/dogmatiq/enginekit/enginetest/stubs.EventStub[github.com/dogmatiq/enginekit/enginetest/stubs.TypeA]",
+										"kind": "event",
+										"produced": true,
+										"consumed": false
+									}
+								]
+							}
+						]
+					},
+					{
+						"name": "<app-2>",
+						"key": "7d3927ce-d879-40a4-bd67-0fafc79d3c36",
+						"go_type": "*github.com/dogmatiq/enginekit/enginetest/stubs.ApplicationStub",
+						"handlers": [
+							{
+								"name": "<projection>",
+								"key": "280a58bd-f154-46d7-863b-23ce70e49d2a",
+								"type": "projection",
+								"go_type": "*github.com/dogmatiq/enginekit/enginetest/stubs.ProjectionMessageHandlerStub",
+								"disabled": true,
+								"messages": [
+									{
+										"name": "*github.com/dogmatiq/enginekit/enginetest/stubs.EventStub[github.com/dogmatiq/enginekit/enginetest/stubs.TypeA]",
+										"kind": "event",
+										"produced": false,
+										"consumed": true
+									}
+								]
+							}
+						]
+					}
+				]
+			}`))
+		})
+
+		It("responds with 304 Not Modified if the ETag matches", func() {
+			w := get("/applications")
+			etag := w.Header().Get("ETag")
+			Expect(etag).NotTo(BeEmpty())
+
+			w = get("/applications", "If-None-Match", etag)
+			Expect(w.Code).To(Equal(http.StatusNotModified))
+			Expect(w.Body.Len()).To(BeZero())
+		})
+
+		It("responds with 405 Method Not Allowed for other methods", func() {
+			req := httptest.NewRequest(http.MethodPost, "/applications", nil)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusMethodNotAllowed))
+		})
+	})
+
+	Describe("GET /applications/{app}", func() {
+		It("returns the application with the given key", func() {
+			w := get("/applications/7d3927ce-d879-40a4-bd67-0fafc79d3c36")
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+
+			var res struct {
+				Name     string `json:"name"`
+				Handlers []any  `json:"handlers"`
+			}
+			err := json.Unmarshal(w.Body.Bytes(), &res)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(res.Name).To(Equal("<app-2>"))
+			Expect(res.Handlers).To(HaveLen(1))
+		})
+
+		It("uses a different ETag for each application", func() {
+			a := get("/applications/7d3927ce-d879-40a4-bd67-0fafc79d3c36")
+			b := get("/applications/b1101bbf-8a62-436d-9044-e6fd3d0e5385")
+
+			Expect(a.Header().Get("ETag")).NotTo(Equal(b.Header().Get("ETag")))
+		})
+
+		It("uses the application's fingerprint as the ETag", func() {
+			w := get("/applications/7d3927ce-d879-40a4-bd67-0fafc79d3c36")
+
+			Expect(w.Header().Get("ETag")).To(Equal(
+				`"` + hex.EncodeToString(configkit.Fingerprint(app2)) + `"`,
+			))
+		})
+
+		It("responds with 404 Not Found if there is no such application", func() {
+			w := get("/applications/00000000-0000-0000-0000-000000000000")
+
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+			Expect(w.Body.String()).To(MatchJSON(`{"error": "application not found"}`))
+		})
+	})
+
+	Describe("GET /applications/{app}/handlers/{handler}", func() {
+		It("returns the handler with the given key", func() {
+			w := get("/applications/7d3927ce-d879-40a4-bd67-0fafc79d3c36/handlers/280a58bd-f154-46d7-863b-23ce70e49d2a")
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(w.Body.String()).To(MatchJSON(`{
+				"name": "<projection>",
+				"key": "280a58bd-f154-46d7-863b-23ce70e49d2a",
+				"type": "projection",
+				"go_type": "*github.com/dogmatiq/enginekit/enginetest/stubs.ProjectionMessageHandlerStub",
+				"disabled": true,
+				"messages": [
+					{
+						"name": "*github.com/dogmatiq/enginekit/enginetest/stubs.EventStub[github.com/dogmatiq/enginekit/enginetest/stubs.TypeA]",
+						"kind": "event",
+						"produced": false,
+						"consumed": true
+					}
+				]
+			}`))
+		})
+
+		It("includes the handler's metadata", func() {
+			app := configkit.FromApplication(
+				&ApplicationStub{
+					ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+						c.Identity("<app>", "59a82a24-a181-41e8-9b93-17a6ce86956e")
+						c.Routes(
+							dogma.ViaProjection(&ProjectionMessageHandlerStub{
+								ConfigureFunc: func(c dogma.ProjectionConfigurer) {
+									c.Identity("<projection>", "70fdf7fa-8d5c-4a49-a5e7-2f9a6c6b1b39")
+									c.Routes(
+										dogma.HandlesEvent[*EventStub[TypeA]](),
+									)
+								},
+							}),
+						)
+					},
+				},
+				configkit.WithMetadata(configkit.MetadataSet{
+					"70fdf7fa-8d5c-4a49-a5e7-2f9a6c6b1b39": {configkit.OwnerMetadataKey: "<team>"},
+				}),
+			)
+			handler = NewHTTPHandler(NewServer(app))
+
+			w := get("/applications/59a82a24-a181-41e8-9b93-17a6ce86956e/handlers/70fdf7fa-8d5c-4a49-a5e7-2f9a6c6b1b39")
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+
+			var res struct {
+				Metadata map[string]string `json:"metadata"`
+			}
+			err := json.Unmarshal(w.Body.Bytes(), &res)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(res.Metadata).To(Equal(map[string]string{"owner": "<team>"}))
+		})
+
+		It("uses the handler's fingerprint as the ETag", func() {
+			w := get("/applications/7d3927ce-d879-40a4-bd67-0fafc79d3c36/handlers/280a58bd-f154-46d7-863b-23ce70e49d2a")
+
+			h, ok := app2.Handlers().ByKey("280a58bd-f154-46d7-863b-23ce70e49d2a")
+			Expect(ok).To(BeTrue())
+			Expect(w.Header().Get("ETag")).To(Equal(
+				`"` + hex.EncodeToString(configkit.HandlerFingerprint(h)) + `"`,
+			))
+		})
+
+		It("responds with 404 Not Found if the handler is in a different application", func() {
+			w := get("/applications/b1101bbf-8a62-436d-9044-e6fd3d0e5385/handlers/280a58bd-f154-46d7-863b-23ce70e49d2a")
+
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+			Expect(w.Body.String()).To(MatchJSON(`{"error": "handler not found"}`))
+		})
+
+		It("responds with 404 Not Found if there is no such application", func() {
+			w := get("/applications/00000000-0000-0000-0000-000000000000/handlers/280a58bd-f154-46d7-863b-23ce70e49d2a")
+
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+			Expect(w.Body.String()).To(MatchJSON(`{"error": "application not found"}`))
+		})
+	})
+
+	Describe("GET /messages", func() {
+		It("returns the messages used by all applications", func() {
+			w := get("/messages")
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(w.Body.String()).To(MatchJSON(`{
+				"messages": [
+					{
+						"name": "*github.com/dogmatiq/enginekit/enginetest/stubs.CommandStub[github.com/dogmatiq/enginekit/enginetest/stubs.TypeA]",
+						"kind": "command",
+						"producers": [],
+						"consumers": [
+							{
+								"application": "b1101bbf-8a62-436d-9044-e6fd3d0e5385",
+								"key": "938b829d-e4d7-4780-bf06-ea349453ba8f",
+								"name": "<aggregate>",
+								"type": "aggregate"
+							}
+						]
+					},
+					{
+						"name": "*github.com/dogmatiq/enginekit/enginetest/stubs.EventStub[github.com/dogmatiq/enginekit/enginetest/stubs.TypeA]",
+						"kind": "event",
+						"producers": [
+							{
+								"application": "b1101bbf-8a62-436d-9044-e6fd3d0e5385",
+								"key": "938b829d-e4d7-4780-bf06-ea349453ba8f",
+								"name": "<aggregate>",
+								"type": "aggregate"
+							}
+						],
+						"consumers": [
+							{
+								"application": "7d3927ce-d879-40a4-bd67-0fafc79d3c36",
+								"key": "280a58bd-f154-46d7-863b-23ce70e49d2a",
+								"name": "<projection>",
+								"type": "projection"
+							}
+						]
+					}
+				]
+			}`))
+		})
+	})
+})
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/dogmatiq/configkit"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	// MetadataAPIServiceName is the fully-qualified name of the gRPC service
+	// that serves the metadata of the applications served by a [Server].
+	//
+	// The protocol buffers representation of an application, which is defined
+	// by enginekit, has no field for metadata, so it is served separately by
+	// this service. Clients that do not use [Client] can call its
+	// [GetMetadataMethod] method directly.
+	MetadataAPIServiceName = "dogma.configkit.v1.MetadataAPI"
+
+	// GetMetadataMethod is the full name of the gRPC method that returns the
+	// metadata of the applications served by a [Server].
+	//
+	// The request is an empty message, as per [emptypb.Empty]. The response is
+	// a [structpb.Struct] that maps the identity key of each application and
+	// handler to an object containing its metadata, which is the same structure
+	// that is read by [configkit.ReadMetadata].
+	//
+	// The fingerprint of the configuration is sent in the [FingerprintHeader]
+	// gRPC header, such that clients can check that the metadata belongs to
+	// the applications returned by a prior call to ListApplications().
+	GetMetadataMethod = "/" + MetadataAPIServiceName + "/GetMetadata"
+)
+
+// MetadataAPIServer is the server API for the [MetadataAPIServiceName]
+// service.
+type MetadataAPIServer interface {
+	GetMetadata(context.Context, *emptypb.Empty) (*structpb.Struct, error)
+}
+
+var _ MetadataAPIServer = (*Server)(nil)
+
+// RegisterMetadataAPIServer registers s as the implementation of the
+// [MetadataAPIServiceName] service.
+//
+// It is typically called with the same [Server] that is registered using
+// configgrpc.RegisterConfigAPIServer().
+func RegisterMetadataAPIServer(r grpc.ServiceRegistrar, s MetadataAPIServer) {
+	r.RegisterService(&metadataAPIServiceDesc, s)
+}
+
+// metadataAPIServiceDesc describes the [MetadataAPIServiceName] service.
+var metadataAPIServiceDesc = grpc.ServiceDesc{
+	ServiceName: MetadataAPIServiceName,
+	HandlerType: (*MetadataAPIServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetMetadata",
+			Handler: func(
+				srv any,
+				ctx context.Context,
+				dec func(any) error,
+				interceptor grpc.UnaryServerInterceptor,
+			) (any, error) {
+				req := &emptypb.Empty{}
+				if err := dec(req); err != nil {
+					return nil, err
+				}
+
+				s := srv.(MetadataAPIServer)
+				if interceptor == nil {
+					return s.GetMetadata(ctx, req)
+				}
+
+				return interceptor(
+					ctx,
+					req,
+					&grpc.UnaryServerInfo{
+						Server:     srv,
+						FullMethod: GetMetadataMethod,
+					},
+					func(ctx context.Context, req any) (any, error) {
+						return s.GetMetadata(ctx, req.(*emptypb.Empty))
+					},
+				)
+			},
+		},
+	},
+}
+
+// marshalMetadata returns the [structpb.Struct] representation of s.
+func marshalMetadata(s configkit.MetadataSet) *structpb.Struct {
+	out := &structpb.Struct{
+		Fields: map[string]*structpb.Value{},
+	}
+
+	for k, m := range s {
+		fields := map[string]*structpb.Value{}
+		for n, v := range m {
+			fields[n] = structpb.NewStringValue(v)
+		}
+
+		out.Fields[k] = structpb.NewStructValue(
+			&structpb.Struct{Fields: fields},
+		)
+	}
+
+	return out
+}
+
+// unmarshalMetadata returns the [configkit.MetadataSet] represented by s.
+func unmarshalMetadata(s *structpb.Struct) (configkit.MetadataSet, error) {
+	data, err := protojson.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return configkit.ReadMetadata(bytes.NewReader(data))
+}
@@ -2,14 +2,39 @@ package api
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"maps"
+	"slices"
+	"strings"
+	"sync/atomic"
 
 	"github.com/dogmatiq/configkit"
 	"github.com/dogmatiq/enginekit/grpc/configgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	// FingerprintHeader is the name of the gRPC header that contains the
+	// hex-encoded fingerprint of the configuration served by a [Server].
+	FingerprintHeader = "configkit-fingerprint"
+
+	// IfNoneMatchHeader is the name of the gRPC metadata key that a client
+	// uses to send the fingerprint of the configuration it already has.
+	IfNoneMatchHeader = "configkit-if-none-match"
 )
 
 // Server is an implementation of configspec.ConfigAPIServer.
+//
+// It also implements [MetadataAPIServer], which serves the metadata of the
+// applications. Use [RegisterMetadataAPIServer] to register it.
+//
+// The zero value is a server that serves no applications.
 type Server struct {
-	response configgrpc.ListApplicationsResponse
+	state atomic.Pointer[state]
 }
 
 var _ configgrpc.ConfigAPIServer = (*Server)(nil)
@@ -19,25 +44,143 @@ var _ configgrpc.ConfigAPIServer = (*Server)(nil)
 func NewServer(apps ...configkit.Application) *Server {
 	s := &Server{}
 
-	for _, in := range apps {
+	if err := s.Update(apps...); err != nil {
+		panic(err)
+	}
+
+	return s
+}
+
+// Update replaces the applications served by s.
+//
+// Calls to [Server.ListApplications] that are already in progress are
+// unaffected. It returns an error if the configuration of any of the
+// applications can not be marshaled, in which case the served applications
+// are unchanged.
+func (s *Server) Update(apps ...configkit.Application) error {
+	st, err := newState(apps)
+	if err != nil {
+		return err
+	}
+
+	s.state.Store(st)
+	return nil
+}
+
+// ListApplications returns the full configuration of all applications.
+//
+// The fingerprint of the configuration is sent to the client in the
+// [FingerprintHeader] gRPC header. If the client sends the same fingerprint in
+// the [IfNoneMatchHeader] gRPC metadata, the response contains no
+// applications.
+func (s *Server) ListApplications(
+	ctx context.Context,
+	_ *configgrpc.ListApplicationsRequest,
+) (*configgrpc.ListApplicationsResponse, error) {
+	st := s.load()
+	fp := hex.EncodeToString(st.fingerprint)
+
+	// SetHeader only fails if ctx is not associated with a gRPC stream, such
+	// as when the server is called directly.
+	_ = grpc.SetHeader(ctx, metadata.Pairs(FingerprintHeader, fp))
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	if slices.Contains(md.Get(IfNoneMatchHeader), fp) {
+		return &configgrpc.ListApplicationsResponse{}, nil
+	}
+
+	return &st.response, nil
+}
+
+// GetMetadata returns the metadata of all applications and their handlers.
+//
+// The fingerprint of the configuration is sent to the client in the
+// [FingerprintHeader] gRPC header.
+func (s *Server) GetMetadata(
+	ctx context.Context,
+	_ *emptypb.Empty,
+) (*structpb.Struct, error) {
+	st := s.load()
+	fp := hex.EncodeToString(st.fingerprint)
+
+	// SetHeader only fails if ctx is not associated with a gRPC stream, such
+	// as when the server is called directly.
+	_ = grpc.SetHeader(ctx, metadata.Pairs(FingerprintHeader, fp))
+
+	return st.metadata, nil
+}
+
+// load returns the current state of s.
+func (s *Server) load() *state {
+	if st := s.state.Load(); st != nil {
+		return st
+	}
+	return emptyState
+}
+
+// emptyState is the state of a [Server] that serves no applications.
+var emptyState = &state{
+	fingerprint: fingerprint(nil),
+	metadata:    marshalMetadata(nil),
+}
+
+// state is the set of applications served by a [Server], along with their
+// various representations.
+type state struct {
+	apps     []configkit.Application
+	response configgrpc.ListApplicationsResponse
+
+	// fingerprint is the combined fingerprint of all applications.
+	fingerprint []byte
+
+	// metadata is the metadata of all applications, as returned by
+	// [Server.GetMetadata].
+	metadata *structpb.Struct
+}
+
+// newState returns the state that serves the given applications.
+func newState(apps []configkit.Application) (*state, error) {
+	s := &state{
+		apps: slices.Clone(apps),
+	}
+
+	md := configkit.MetadataSet{}
+
+	for _, in := range s.apps {
 		out, err := configkit.ToProto(in)
 		if err != nil {
-			panic(err)
+			return nil, err
 		}
 
 		s.response.Applications = append(
 			s.response.Applications,
 			out,
 		)
+
+		maps.Copy(md, configkit.CollectMetadata(in))
 	}
 
-	return s
+	s.fingerprint = fingerprint(s.apps)
+	s.metadata = marshalMetadata(md)
+
+	return s, nil
 }
 
-// ListApplications returns the full configuration of all applications.
-func (s *Server) ListApplications(
-	context.Context,
-	*configgrpc.ListApplicationsRequest,
-) (*configgrpc.ListApplicationsResponse, error) {
-	return &s.response, nil
+// fingerprint returns a fingerprint of a set of applications.
+//
+// It is a hash of the fingerprints of each application, in the order of their
+// keys, such that the order in which the applications are given does not
+// matter.
+func fingerprint(apps []configkit.Application) []byte {
+	apps = slices.Clone(apps)
+	slices.SortFunc(apps, func(a, b configkit.Application) int {
+		return strings.Compare(a.Identity().Key, b.Identity().Key)
+	})
+
+	h := sha256.New()
+	for _, app := range apps {
+		h.Write(configkit.Fingerprint(app))
+	}
+
+	return h.Sum(nil)
 }
@@ -1,9 +1,18 @@
 package api_test
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/dogmatiq/configkit"
 	. "github.com/dogmatiq/configkit/api"
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	"github.com/dogmatiq/enginekit/grpc/configgrpc"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"google.golang.org/protobuf/types/known/emptypb"
 )
 
 var _ = Describe("func NewServer()", func() {
@@ -13,3 +22,117 @@ var _ = Describe("func NewServer()", func() {
 		}).To(Panic())
 	})
 })
+
+var _ = Describe("type Server", func() {
+	It("serves no applications if it is the zero value", func() {
+		s := &Server{}
+
+		res, err := s.ListApplications(
+			context.Background(),
+			&configgrpc.ListApplicationsRequest{},
+		)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(res.GetApplications()).To(BeEmpty())
+
+		md, err := s.GetMetadata(context.Background(), &emptypb.Empty{})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(md.GetFields()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("func (*Server) GetMetadata()", func() {
+	It("returns the metadata of the applications and their handlers", func() {
+		app := configkit.FromApplication(
+			&ApplicationStub{
+				ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+					c.Identity("<app>", "b1101bbf-8a62-436d-9044-e6fd3d0e5385")
+					c.Routes(
+						dogma.ViaIntegration(&IntegrationMessageHandlerStub{
+							ConfigureFunc: func(c dogma.IntegrationConfigurer) {
+								c.Identity("<integration>", "e6f0ad02-d301-4f46-a03d-4f9d0d20f5cf")
+								c.Routes(
+									dogma.HandlesCommand[*CommandStub[TypeA]](),
+								)
+							},
+						}),
+					)
+				},
+			},
+			configkit.WithMetadata(configkit.MetadataSet{
+				"b1101bbf-8a62-436d-9044-e6fd3d0e5385": {configkit.OwnerMetadataKey: "<team>"},
+				"e6f0ad02-d301-4f46-a03d-4f9d0d20f5cf": {configkit.DataClassificationMetadataKey: "pii"},
+			}),
+		)
+
+		s := NewServer(app)
+
+		md, err := s.GetMetadata(context.Background(), &emptypb.Empty{})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(md.AsMap()).To(Equal(map[string]any{
+			"b1101bbf-8a62-436d-9044-e6fd3d0e5385": map[string]any{
+				configkit.OwnerMetadataKey: "<team>",
+			},
+			"e6f0ad02-d301-4f46-a03d-4f9d0d20f5cf": map[string]any{
+				configkit.DataClassificationMetadataKey: "pii",
+			},
+		}))
+	})
+})
+
+var _ = Describe("func (*Server) Update()", func() {
+	It("replaces the applications served by the server", func() {
+		app1 := configkit.FromApplication(&ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app-1>", "b1101bbf-8a62-436d-9044-e6fd3d0e5385")
+			},
+		})
+
+		app2 := configkit.FromApplication(&ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app-2>", "7d3927ce-d879-40a4-bd67-0fafc79d3c36")
+			},
+		})
+
+		s := NewServer(app1)
+
+		err := s.Update(app2)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		res, err := s.ListApplications(
+			context.Background(),
+			&configgrpc.ListApplicationsRequest{},
+		)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(res.GetApplications()).To(HaveLen(1))
+		Expect(res.GetApplications()[0].GetIdentity().GetName()).To(Equal("<app-2>"))
+	})
+
+	It("is unaffected by subsequent changes to the caller's slice", func() {
+		app1 := configkit.FromApplication(&ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app-1>", "b1101bbf-8a62-436d-9044-e6fd3d0e5385")
+			},
+		})
+
+		app2 := configkit.FromApplication(&ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app-2>", "7d3927ce-d879-40a4-bd67-0fafc79d3c36")
+			},
+		})
+
+		apps := []configkit.Application{app1}
+
+		s := &Server{}
+		err := s.Update(apps...)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		apps[0] = app2
+
+		rec := httptest.NewRecorder()
+		NewHTTPHandler(s).ServeHTTP(
+			rec,
+			httptest.NewRequest(http.MethodGet, "/applications/b1101bbf-8a62-436d-9044-e6fd3d0e5385", nil),
+		)
+		Expect(rec.Code).To(Equal(http.StatusOK))
+	})
+})
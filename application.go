@@ -4,8 +4,8 @@ import (
 	"context"
 	"reflect"
 
-	"github.com/dogmatiq/configkit/internal/typename/goreflect"
 	"github.com/dogmatiq/configkit/internal/validation"
+	"github.com/dogmatiq/configkit/typename"
 	"github.com/dogmatiq/dogma"
 	"github.com/dogmatiq/enginekit/message"
 )
@@ -38,13 +38,36 @@ type RichApplication interface {
 //
 // It panics if the application is configured incorrectly. Use Recover() to
 // convert configuration related panic values to errors.
-func FromApplication(a dogma.Application) RichApplication {
+//
+// The options are also applied to each of the application's handlers.
+//
+// The returned configuration is immutable and safe for concurrent use.
+func FromApplication(a dogma.Application, options ...ConfigureOption) RichApplication {
 	cfg := &richApplication{app: a}
-	a.Configure(&applicationConfigurer{cfg})
+	opts := newConfigureOptions(options)
+
+	opts.configure(
+		cfg.ReflectType(),
+		func() PanicEntity {
+			return PanicEntity{"", cfg.ident, cfg.TypeName()}
+		},
+		func() {
+			c := &applicationConfigurer{
+				config:  cfg,
+				options: opts.nested(),
+			}
+			defer c.end()
+			a.Configure(c)
+		},
+	)
+
+	cfg.names = asMessageNames(cfg.types)
+	cfg.ident, cfg.metadata = opts.resolve(cfg.ident)
 
 	mustHaveValidIdentity(
 		cfg.Identity(),
 		cfg.ReflectType(),
+		"",
 	)
 
 	return cfg
@@ -58,6 +81,7 @@ func FromApplication(a dogma.Application) RichApplication {
 //  2. produce and consume the same message types
 //  3. are implemented using the same Go types
 //  4. contain equivalent handlers
+//  5. have the same metadata
 //
 // Point 3. refers to the type used to implement the dogma.Application interface
 // (not the type used to implement the configkit.Application interface).
@@ -65,6 +89,7 @@ func IsApplicationEqual(a, b Application) bool {
 	return a.Identity() == b.Identity() &&
 		a.TypeName() == b.TypeName() &&
 		a.MessageNames().IsEqual(b.MessageNames()) &&
+		a.Metadata().IsEqual(b.Metadata()) &&
 		a.Handlers().IsEqual(b.Handlers())
 }
 
@@ -72,7 +97,9 @@ func IsApplicationEqual(a, b Application) bool {
 type richApplication struct {
 	ident    Identity
 	types    EntityMessages[message.Type]
-	handlers RichHandlerSet
+	names    EntityMessages[message.Name]
+	metadata Metadata
+	handlers richHandlerIndex
 	app      dogma.Application
 }
 
@@ -81,15 +108,19 @@ func (a *richApplication) Identity() Identity {
 }
 
 func (a *richApplication) MessageNames() EntityMessages[message.Name] {
-	return asMessageNames(a.types)
+	return a.names
 }
 
 func (a *richApplication) MessageTypes() EntityMessages[message.Type] {
 	return a.types
 }
 
+func (a *richApplication) Metadata() Metadata {
+	return a.metadata
+}
+
 func (a *richApplication) TypeName() string {
-	return goreflect.NameOf(a.ReflectType())
+	return typename.FromReflect(a.ReflectType())
 }
 
 func (a *richApplication) ReflectType() reflect.Type {
@@ -105,11 +136,19 @@ func (a *richApplication) AcceptRichVisitor(ctx context.Context, v RichVisitor)
 }
 
 func (a *richApplication) Handlers() HandlerSet {
-	return a.handlers.asHandlerSet()
+	return HandlerSet(a.handlers.plain.handlers)
 }
 
 func (a *richApplication) RichHandlers() RichHandlerSet {
-	return a.handlers
+	return RichHandlerSet(a.handlers.rich.handlers)
+}
+
+func (a *richApplication) handlerIndex() *HandlerIndex[Handler] {
+	return &a.handlers.plain
+}
+
+func (a *richApplication) richHandlerIndex() *HandlerIndex[RichHandler] {
+	return &a.handlers.rich
 }
 
 func (a *richApplication) Application() dogma.Application {
@@ -119,35 +158,40 @@ func (a *richApplication) Application() dogma.Application {
 // applicationConfigurer is the default implementation of
 // [dogma.ApplicationConfigurer].
 type applicationConfigurer struct {
-	config *richApplication
+	configurerLifecycle
+	config  *richApplication
+	options configureOptions
 }
 
 func (c *applicationConfigurer) Identity(name, key string) {
-	if h, ok := c.config.handlers.ByKey(key); ok {
+	c.guard(c.config.ReflectType(), "Identity")
+	if h, ok := c.config.handlers.rich.ByKey(key); ok {
 		validation.Panicf(
-			`%s can not use the application key "%s", because it is already used by %s`,
+			`%s can not use the application key "%s", because it is already used by %s%s`,
 			c.config.ReflectType(),
 			key,
 			h.ReflectType(),
+			inModule(h),
 		)
 	}
 
-	configureIdentity(&c.config.ident, name, key, c.config.ReflectType())
+	configureIdentity(&c.config.ident, name, key, c.config.ReflectType(), "")
 }
 
 func (c *applicationConfigurer) Routes(routes ...dogma.HandlerRoute) {
+	c.guard(c.config.ReflectType(), "Routes")
 	for _, r := range routes {
 		var h validatableHandler
 
 		switch r := r.(type) {
 		case dogma.AggregateHandlerRoute:
-			h = fromAggregateUnvalidated(r.Handler())
+			h = fromAggregateUnvalidated(unwrapModule(c, r.Handler()))
 		case dogma.ProcessHandlerRoute:
-			h = fromProcessUnvalidated(r.Handler())
+			h = fromProcessUnvalidated(unwrapModule(c, r.Handler()))
 		case dogma.IntegrationHandlerRoute:
-			h = fromIntegrationUnvalidated(r.Handler())
+			h = fromIntegrationUnvalidated(unwrapModule(c, r.Handler()))
 		case dogma.ProjectionHandlerRoute:
-			h = fromProjectionUnvalidated(r.Handler())
+			h = fromProjectionUnvalidated(unwrapModule(c, r.Handler()))
 		default:
 			validation.Panicf("unsupported route type: %T", r)
 		}
@@ -179,12 +223,11 @@ func (c *applicationConfigurer) registerIfConfigured(
 	c.guardAgainstConflictingIdentities(h)
 	c.guardAgainstConflictingRoutes(h)
 
-	if c.config.handlers == nil {
-		c.config.handlers = RichHandlerSet{}
+	if c.config.types == nil {
 		c.config.types = EntityMessages[message.Type]{}
 	}
 
-	c.config.handlers.Add(h)
+	c.config.handlers.add(h)
 	c.config.types.merge(h.MessageTypes())
 }
 
@@ -196,28 +239,33 @@ func (c *applicationConfigurer) guardAgainstConflictingIdentities(h RichHandler)
 
 	if handlerIdent.Key == appIdent.Key {
 		validation.Panicf(
-			`%s can not use the handler key "%s", because it is already used by %s`,
+			`%s%s can not use the handler key "%s", because it is already used by %s`,
 			h.ReflectType(),
+			inModule(h),
 			handlerIdent.Key,
 			c.config.ReflectType(),
 		)
 	}
 
-	if x, ok := c.config.handlers.ByName(handlerIdent.Name); ok {
+	if x, ok := c.config.handlers.rich.ByName(handlerIdent.Name); ok {
 		validation.Panicf(
-			`%s can not use the handler name "%s", because it is already used by %s`,
+			`%s%s can not use the handler name "%s", because it is already used by %s%s`,
 			h.ReflectType(),
+			inModule(h),
 			handlerIdent.Name,
 			x.ReflectType(),
+			inModule(x),
 		)
 	}
 
-	if x, ok := c.config.handlers.ByKey(handlerIdent.Key); ok {
+	if x, ok := c.config.handlers.rich.ByKey(handlerIdent.Key); ok {
 		validation.Panicf(
-			`%s can not use the handler key "%s", because it is already used by %s`,
+			`%s%s can not use the handler key "%s", because it is already used by %s%s`,
 			h.ReflectType(),
+			inModule(h),
 			handlerIdent.Key,
 			x.ReflectType(),
+			inModule(x),
 		)
 	}
 }
@@ -227,27 +275,31 @@ func (c *applicationConfigurer) guardAgainstConflictingIdentities(h RichHandler)
 func (c *applicationConfigurer) guardAgainstConflictingRoutes(h RichHandler) {
 	for mt, em := range h.MessageTypes() {
 		if em.Kind == message.CommandKind && em.IsConsumed {
-			for _, x := range c.config.handlers.ConsumersOf(mt) {
+			for x := range c.config.handlers.rich.ConsumersOf(mt.Name()) {
 				validation.Panicf(
-					`%s (%s) can not handle %s commands because they are already configured to be handled by %s (%s)`,
+					`%s (%s)%s can not handle %s commands because they are already configured to be handled by %s (%s)%s`,
 					h.ReflectType(),
 					h.Identity().Name,
+					inModule(h),
 					mt,
 					x.ReflectType(),
 					x.Identity().Name,
+					inModule(x),
 				)
 			}
 		}
 
 		if em.Kind == message.EventKind && em.IsProduced {
-			for _, x := range c.config.handlers.ProducersOf(mt) {
+			for x := range c.config.handlers.rich.ProducersOf(mt.Name()) {
 				validation.Panicf(
-					`%s (%s) can not record %s events because they are already configured to be recorded by %s (%s)`,
+					`%s (%s)%s can not record %s events because they are already configured to be recorded by %s (%s)%s`,
 					h.ReflectType(),
 					h.Identity().Name,
+					inModule(h),
 					mt,
 					x.ReflectType(),
 					x.Identity().Name,
+					inModule(x),
 				)
 			}
 		}
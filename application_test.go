@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"reflect"
+	"sync"
 
 	. "github.com/dogmatiq/configkit"
 	"github.com/dogmatiq/dogma"
@@ -143,6 +144,14 @@ var _ = Describe("func FromApplication()", func() {
 					},
 				))
 			})
+
+			It("does not copy the message names on each call", func() {
+				Expect(
+					reflect.ValueOf(cfg.MessageNames()).Pointer(),
+				).To(Equal(
+					reflect.ValueOf(cfg.MessageNames()).Pointer(),
+				))
+			})
 		})
 
 		Describe("func MessageTypes()", func() {
@@ -183,6 +192,14 @@ var _ = Describe("func FromApplication()", func() {
 					},
 				))
 			})
+
+			It("does not copy the message types on each call", func() {
+				Expect(
+					reflect.ValueOf(cfg.MessageTypes()).Pointer(),
+				).To(Equal(
+					reflect.ValueOf(cfg.MessageTypes()).Pointer(),
+				))
+			})
 		})
 
 		Describe("func TypeName()", func() {
@@ -236,6 +253,14 @@ var _ = Describe("func FromApplication()", func() {
 					),
 				))
 			})
+
+			It("does not copy the handler set on each call", func() {
+				Expect(
+					reflect.ValueOf(cfg.Handlers()).Pointer(),
+				).To(Equal(
+					reflect.ValueOf(cfg.Handlers()).Pointer(),
+				))
+			})
 		})
 
 		Describe("func RichHandlers()", func() {
@@ -249,6 +274,14 @@ var _ = Describe("func FromApplication()", func() {
 					),
 				))
 			})
+
+			It("does not copy the handler set on each call", func() {
+				Expect(
+					reflect.ValueOf(cfg.RichHandlers()).Pointer(),
+				).To(Equal(
+					reflect.ValueOf(cfg.RichHandlers()).Pointer(),
+				))
+			})
 		})
 
 		Describe("func Application()", func() {
@@ -257,6 +290,44 @@ var _ = Describe("func FromApplication()", func() {
 			})
 		})
 
+		It("is safe for concurrent use", func() {
+			// This test is only meaningful when run with the race detector
+			// enabled, in which case any mutation of the configuration's
+			// internal state by a reader causes the test to fail. Readers
+			// modify clones of the accessors' results, never the results
+			// themselves.
+			var g sync.WaitGroup
+
+			for range 10 {
+				g.Add(1)
+				go func() {
+					defer GinkgoRecover()
+					defer g.Done()
+
+					types := cfg.MessageTypes().Clone()
+					types.Update(
+						message.TypeOf(CommandA1),
+						func(_ message.Type, em *EntityMessage) {
+							em.IsProduced = true
+						},
+					)
+
+					for id, h := range cfg.RichHandlers() {
+						names := h.MessageNames().Clone()
+						clear(names)
+
+						handlers := cfg.Handlers().Clone()
+						delete(handlers, id)
+					}
+
+					Expect(cfg.MessageNames()).To(HaveLen(7))
+					Expect(cfg.Handlers()).To(HaveLen(4))
+				}()
+			}
+
+			g.Wait()
+		})
+
 		It("does not panic when the app name is shared with handler", func() {
 			aggregate.ConfigureFunc = func(c dogma.AggregateConfigurer) {
 				c.Identity("<app>", aggregateKey)
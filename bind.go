@@ -0,0 +1,283 @@
+package configkit
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/enginekit/message"
+)
+
+// Bind returns a rich configuration of app that uses the Go types in r to
+// resolve the type names within the configuration.
+//
+// It is typically used to obtain a [RichApplication] from a configuration that
+// was produced by [FromProto], so that it can be used with a [RichVisitor], for
+// example.
+//
+// It returns an [UnresolvedTypesError] if the types of the application, its
+// handlers or their messages are not in the registry. Hence the ReflectType()
+// and MessageTypes() methods of the returned configurations are always
+// complete.
+//
+// The configuration is not backed by Dogma application or handler values, so
+// the Application() and Handler() methods of the returned configurations
+// always return nil. If app is already a [RichApplication], it is returned
+// unchanged.
+func Bind(app Application, r *TypeRegistry) (RichApplication, error) {
+	if r == nil {
+		return nil, fmt.Errorf(
+			"can not bind the configuration of the %s application, the type registry is nil",
+			app.Identity(),
+		)
+	}
+
+	if rich, ok := app.(RichApplication); ok {
+		return rich, nil
+	}
+
+	b := &binder{registry: r}
+
+	cfg := &boundApplication{
+		config: app,
+		rtype:  b.lookup(app.TypeName()),
+		types:  b.lookupMessages(app.MessageNames()),
+	}
+
+	for _, h := range app.Handlers() {
+		cfg.handlers.add(b.bindHandler(h))
+	}
+
+	if names := b.unresolved(); len(names) != 0 {
+		return nil, UnresolvedTypesError{app.Identity(), names}
+	}
+
+	return cfg, nil
+}
+
+// UnresolvedTypesError indicates that [Bind] could not bind a configuration
+// because some of the types within it are not in the [TypeRegistry].
+type UnresolvedTypesError struct {
+	// Application is the identity of the application.
+	Application Identity
+
+	// Names are the fully-qualified names of the types that are not in the
+	// registry, in lexical order.
+	Names []string
+}
+
+func (e UnresolvedTypesError) Error() string {
+	return fmt.Sprintf(
+		"can not bind the configuration of the %s application, the registry does not contain the following types: %s",
+		e.Application,
+		strings.Join(e.Names, ", "),
+	)
+}
+
+// binder resolves type names using a [TypeRegistry], keeping track of the
+// names that could not be resolved.
+type binder struct {
+	registry *TypeRegistry
+	missing  map[string]struct{}
+}
+
+// lookup returns the Go type with the given name, or nil if it is not in the
+// registry.
+func (b *binder) lookup(name string) reflect.Type {
+	if t, ok := b.registry.Lookup(name); ok {
+		return t
+	}
+
+	b.miss(name)
+	return nil
+}
+
+// lookupMessages returns the message types of the messages in names that are
+// in the registry.
+func (b *binder) lookupMessages(names EntityMessages[message.Name]) EntityMessages[message.Type] {
+	types := EntityMessages[message.Type]{}
+
+	for n, em := range names {
+		if t, ok := b.registry.LookupMessage(n); ok {
+			types[t] = em
+		} else {
+			b.miss(string(n))
+		}
+	}
+
+	return types
+}
+
+func (b *binder) bindHandler(h Handler) RichHandler {
+	t := b.lookup(h.TypeName())
+	types := b.lookupMessages(h.MessageNames())
+
+	h.HandlerType().MustValidate()
+
+	switch h.HandlerType() {
+	case AggregateHandlerType:
+		return &boundHandler[dogma.AggregateMessageHandler]{h, t, types}
+	case ProcessHandlerType:
+		return &boundHandler[dogma.ProcessMessageHandler]{h, t, types}
+	case IntegrationHandlerType:
+		return &boundHandler[dogma.IntegrationMessageHandler]{h, t, types}
+	default: // ProjectionHandlerType
+		return &boundHandler[dogma.ProjectionMessageHandler]{h, t, types}
+	}
+}
+
+func (b *binder) miss(name string) {
+	if b.missing == nil {
+		b.missing = map[string]struct{}{}
+	}
+	b.missing[name] = struct{}{}
+}
+
+func (b *binder) unresolved() []string {
+	var names []string
+	for n := range b.missing {
+		names = append(names, n)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// boundApplication is an implementation of [RichApplication] that is produced
+// by binding an [Application] to the Go types in a [TypeRegistry].
+type boundApplication struct {
+	config   Application
+	rtype    reflect.Type
+	types    EntityMessages[message.Type]
+	handlers richHandlerIndex
+}
+
+func (a *boundApplication) Identity() Identity {
+	return a.config.Identity()
+}
+
+func (a *boundApplication) MessageNames() EntityMessages[message.Name] {
+	return a.config.MessageNames()
+}
+
+func (a *boundApplication) MessageTypes() EntityMessages[message.Type] {
+	return a.types
+}
+
+func (a *boundApplication) Metadata() Metadata {
+	return a.config.Metadata()
+}
+
+func (a *boundApplication) TypeName() string {
+	return a.config.TypeName()
+}
+
+func (a *boundApplication) ReflectType() reflect.Type {
+	return a.rtype
+}
+
+func (a *boundApplication) AcceptVisitor(ctx context.Context, v Visitor) error {
+	return v.VisitApplication(ctx, a)
+}
+
+func (a *boundApplication) AcceptRichVisitor(ctx context.Context, v RichVisitor) error {
+	return v.VisitRichApplication(ctx, a)
+}
+
+func (a *boundApplication) Handlers() HandlerSet {
+	return HandlerSet(a.handlers.plain.handlers)
+}
+
+func (a *boundApplication) RichHandlers() RichHandlerSet {
+	return RichHandlerSet(a.handlers.rich.handlers)
+}
+
+func (a *boundApplication) handlerIndex() *HandlerIndex[Handler] {
+	return &a.handlers.plain
+}
+
+func (a *boundApplication) richHandlerIndex() *HandlerIndex[RichHandler] {
+	return &a.handlers.rich
+}
+
+func (a *boundApplication) Application() dogma.Application {
+	return nil
+}
+
+// boundHandler is an implementation of [RichHandler] that is produced by
+// binding a [Handler] to the Go types in a [TypeRegistry].
+//
+// T is the Dogma interface that is implemented by handlers of this type, such
+// as [dogma.AggregateMessageHandler].
+type boundHandler[T any] struct {
+	config Handler
+	rtype  reflect.Type
+	types  EntityMessages[message.Type]
+}
+
+func (h *boundHandler[T]) Identity() Identity {
+	return h.config.Identity()
+}
+
+func (h *boundHandler[T]) MessageNames() EntityMessages[message.Name] {
+	return h.config.MessageNames()
+}
+
+func (h *boundHandler[T]) MessageTypes() EntityMessages[message.Type] {
+	return h.types
+}
+
+func (h *boundHandler[T]) Metadata() Metadata {
+	return h.config.Metadata()
+}
+
+func (h *boundHandler[T]) TypeName() string {
+	return h.config.TypeName()
+}
+
+func (h *boundHandler[T]) ReflectType() reflect.Type {
+	return h.rtype
+}
+
+func (h *boundHandler[T]) HandlerType() HandlerType {
+	return h.config.HandlerType()
+}
+
+func (h *boundHandler[T]) IsDisabled() bool {
+	return h.config.IsDisabled()
+}
+
+func (h *boundHandler[T]) Handler() T {
+	var zero T
+	return zero
+}
+
+func (h *boundHandler[T]) AcceptVisitor(ctx context.Context, v Visitor) error {
+	switch h.HandlerType() {
+	case AggregateHandlerType:
+		return v.VisitAggregate(ctx, h)
+	case ProcessHandlerType:
+		return v.VisitProcess(ctx, h)
+	case IntegrationHandlerType:
+		return v.VisitIntegration(ctx, h)
+	default: // ProjectionHandlerType
+		return v.VisitProjection(ctx, h)
+	}
+}
+
+func (h *boundHandler[T]) AcceptRichVisitor(ctx context.Context, v RichVisitor) error {
+	switch x := any(h).(type) {
+	case RichAggregate:
+		return v.VisitRichAggregate(ctx, x)
+	case RichProcess:
+		return v.VisitRichProcess(ctx, x)
+	case RichIntegration:
+		return v.VisitRichIntegration(ctx, x)
+	default:
+		return v.VisitRichProjection(ctx, x.(RichProjection))
+	}
+}
@@ -0,0 +1,149 @@
+package configkit_test
+
+import (
+	"context"
+	"reflect"
+
+	. "github.com/dogmatiq/configkit"
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	"github.com/dogmatiq/enginekit/message"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("func Bind()", func() {
+	var (
+		rich        RichApplication
+		unmarshaled Application
+		registry    *TypeRegistry
+	)
+
+	BeforeEach(func() {
+		rich = FromApplication(&ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app>", appKey)
+				c.Routes(
+					dogma.ViaAggregate(&AggregateMessageHandlerStub{
+						ConfigureFunc: func(c dogma.AggregateConfigurer) {
+							c.Identity("<aggregate>", aggregateKey)
+							c.Routes(
+								dogma.HandlesCommand[*CommandStub[TypeA]](),
+								dogma.RecordsEvent[*EventStub[TypeA]](),
+							)
+						},
+					}),
+					dogma.ViaProjection(&ProjectionMessageHandlerStub{
+						ConfigureFunc: func(c dogma.ProjectionConfigurer) {
+							c.Identity("<projection>", projectionKey)
+							c.Routes(
+								dogma.HandlesEvent[*EventStub[TypeA]](),
+							)
+						},
+					}),
+				)
+			},
+		})
+
+		marshaled, err := ToProto(rich)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		unmarshaled, err = FromProto(marshaled)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		registry = &TypeRegistry{}
+	})
+
+	When("all of the types are in the registry", func() {
+		BeforeEach(func() {
+			registry.AddApplication(rich)
+		})
+
+		It("returns an equivalent rich configuration", func() {
+			bound, err := Bind(unmarshaled, registry)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Expect(IsApplicationEqual(bound, rich)).To(BeTrue())
+			Expect(bound.ReflectType()).To(Equal(rich.ReflectType()))
+			Expect(bound.MessageTypes()).To(Equal(rich.MessageTypes()))
+			Expect(bound.RichHandlers()).To(HaveLen(2))
+
+			for id, h := range rich.RichHandlers() {
+				x, ok := bound.RichHandlers().ByIdentity(id)
+				Expect(ok).To(BeTrue())
+				Expect(IsHandlerEqual(x, h)).To(BeTrue())
+				Expect(x.ReflectType()).To(Equal(h.ReflectType()))
+				Expect(x.MessageTypes()).To(Equal(h.MessageTypes()))
+			}
+		})
+
+		It("returns a configuration that can be visited by a rich visitor", func() {
+			bound, err := Bind(unmarshaled, registry)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			var visited []string
+			v := &richVisitorStub{
+				VisitRichAggregateFunc: func(_ context.Context, c RichAggregate) error {
+					visited = append(visited, c.Identity().Name)
+					Expect(c.Handler()).To(BeNil())
+					return nil
+				},
+				VisitRichProjectionFunc: func(_ context.Context, c RichProjection) error {
+					visited = append(visited, c.Identity().Name)
+					Expect(c.Handler()).To(BeNil())
+					return nil
+				},
+			}
+
+			err = bound.AcceptRichVisitor(context.Background(), v)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(visited).To(ConsistOf("<aggregate>", "<projection>"))
+		})
+
+		It("returns a configuration without an underlying application", func() {
+			bound, err := Bind(unmarshaled, registry)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(bound.Application()).To(BeNil())
+		})
+	})
+
+	When("some of the types are not in the registry", func() {
+		BeforeEach(func() {
+			registry.Add(
+				reflect.TypeOf(rich.Application()),
+				reflect.TypeFor[*CommandStub[TypeA]](),
+			)
+		})
+
+		It("returns an error that lists the unresolved types", func() {
+			_, err := Bind(unmarshaled, registry)
+			Expect(err).To(Equal(UnresolvedTypesError{
+				Application: rich.Identity(),
+				Names: []string{
+					"*github.com/dogmatiq/enginekit/enginetest/stubs.AggregateMessageHandlerStub",
+					string(message.NameOf(EventA1)),
+					"*github.com/dogmatiq/enginekit/enginetest/stubs.ProjectionMessageHandlerStub",
+				},
+			}))
+			Expect(err).To(MatchError(
+				"can not bind the configuration of the <app>/" + appKey + " application, the registry does not contain the following types: " +
+					"*github.com/dogmatiq/enginekit/enginetest/stubs.AggregateMessageHandlerStub, " +
+					string(message.NameOf(EventA1)) + ", " +
+					"*github.com/dogmatiq/enginekit/enginetest/stubs.ProjectionMessageHandlerStub",
+			))
+		})
+	})
+
+	It("returns rich configurations unchanged", func() {
+		bound, err := Bind(rich, registry)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(bound).To(BeIdenticalTo(rich))
+	})
+
+	It("returns an error if the registry is nil", func() {
+		_, err := Bind(unmarshaled, nil)
+		Expect(err).To(MatchError(
+			"can not bind the configuration of the <app>/" + appKey + " application, the type registry is nil",
+		))
+	})
+})
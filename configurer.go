@@ -1 +1,206 @@
 package configkit
+
+import (
+	"context"
+	"reflect"
+	"runtime/debug"
+	"sync/atomic"
+
+	"github.com/dogmatiq/configkit/internal/validation"
+)
+
+// ConfigureOption is an option that changes the behavior of [FromApplication]
+// and the other From*() functions that call an entity's Configure() method.
+type ConfigureOption func(*configureOptions)
+
+// WithPanicContext is a [ConfigureOption] that wraps any panic that occurs
+// within an entity's Configure() method in a [PanicError], unless the panic
+// value is a configuration [Error].
+//
+// The [PanicError] describes the entity that panicked, and the application
+// that contains it, making the cause of the panic easier to diagnose. Use
+// [Recover] to convert the panic value to an error.
+func WithPanicContext() ConfigureOption {
+	return func(o *configureOptions) {
+		o.wrapPanics = true
+	}
+}
+
+// WithContext is a [ConfigureOption] that abandons configuration if ctx is
+// canceled, or its deadline is exceeded, before the entity's Configure()
+// method returns.
+//
+// If configuration is abandoned, the From*() function panics with a
+// [ContextError]. Use [Recover] to convert the panic value to an error. The
+// Configure() method continues to run in a separate goroutine, and the
+// configuration that it produces is discarded.
+func WithContext(ctx context.Context) ConfigureOption {
+	return func(o *configureOptions) {
+		o.ctx = ctx
+	}
+}
+
+// WithMetadata is a [ConfigureOption] that attaches metadata to the entities
+// that are configured, according to their identity keys.
+//
+// It may be used multiple times, in which case the metadata from each set is
+// merged, with later sets taking precedence.
+func WithMetadata(s MetadataSet) ConfigureOption {
+	return func(o *configureOptions) {
+		if o.metadata == nil {
+			o.metadata = MetadataSet{}
+		}
+
+		for k, m := range s {
+			o.metadata.add(k, m)
+		}
+	}
+}
+
+// configureOptions is the set of options that control how entities are
+// configured.
+type configureOptions struct {
+	wrapPanics bool
+	ctx        context.Context
+	metadata   MetadataSet
+
+	// module is the module that contains the entity, if any, and appKey is
+	// the key of the application that the module is included in.
+	module *Module
+	appKey string
+}
+
+// nested returns the options to use when configuring an entity within the
+// entity that is configured with o, such as a handler within an application.
+//
+// The context is removed, as the outer-most entity is already configured
+// within a goroutine that is abandoned if the context is done.
+func (o configureOptions) nested() configureOptions {
+	o.ctx = nil
+	return o
+}
+
+// resolve returns the identity and metadata of an entity that declares the
+// identity id within its Configure() method.
+//
+// If the entity is part of a module, its key is replaced with the key that it
+// has within the application.
+func (o configureOptions) resolve(id Identity) (Identity, Metadata) {
+	if o.module != nil && !id.IsZero() {
+		id.Key = o.module.HandlerKey(o.appKey, id.Key)
+	}
+
+	return id, o.metadata.Get(id).Clone()
+}
+
+// moduleName returns the name of the module that contains the entity, or an
+// empty string if it is not part of a module.
+func (o configureOptions) moduleName() string {
+	if o.module == nil {
+		return ""
+	}
+	return o.module.name
+}
+
+// newConfigureOptions returns the options described by the given
+// [ConfigureOption] values.
+func newConfigureOptions(options []ConfigureOption) configureOptions {
+	var opts configureOptions
+	for _, o := range options {
+		o(&opts)
+	}
+	return opts
+}
+
+// configure calls fn, which calls the Configure() method of the entity
+// described by describe.
+//
+// t is the type that implements the entity. Unlike the information returned by
+// describe, it is safe to use while fn is running in another goroutine.
+func (o configureOptions) configure(
+	t reflect.Type,
+	describe func() PanicEntity,
+	fn func(),
+) {
+	if o.ctx == nil {
+		if o.wrapPanics {
+			defer wrapPanic(describe)
+		}
+
+		fn()
+		return
+	}
+
+	if err := o.ctx.Err(); err != nil {
+		panic(&ContextError{t, err})
+	}
+
+	// Buffered so that an abandoned goroutine does not block forever.
+	result := make(chan any, 1)
+
+	go func() {
+		defer func() {
+			result <- recover()
+		}()
+
+		o.nested().configure(t, describe, fn)
+	}()
+
+	select {
+	case v := <-result:
+		if v != nil {
+			panic(v)
+		}
+	case <-o.ctx.Done():
+		panic(&ContextError{t, o.ctx.Err()})
+	}
+}
+
+// wrapPanic recovers from a panic and re-panics with a [PanicError] that
+// includes the entity described by describe.
+//
+// It must be called directly by a defer statement.
+func wrapPanic(describe func() PanicEntity) {
+	switch v := recover().(type) {
+	case nil:
+		return
+	case validation.Error:
+		panic(v)
+	case *PanicError:
+		v.Path = append([]PanicEntity{describe()}, v.Path...)
+		panic(v)
+	default:
+		panic(&PanicError{
+			Path:  []PanicEntity{describe()},
+			Value: v,
+			Stack: debug.Stack(),
+		})
+	}
+}
+
+// configurerLifecycle tracks whether the Configure() method that a configurer
+// was passed to has returned.
+//
+// It is embedded in each configurer implementation.
+type configurerLifecycle struct {
+	done atomic.Bool
+}
+
+// guard panics if the Configure() method has already returned.
+//
+// t is the type that implements the entity, and method is the name of the
+// configurer method that was called.
+func (l *configurerLifecycle) guard(t reflect.Type, method string) {
+	if l.done.Load() {
+		validation.Panicf(
+			"%s called %s() on its configurer after its Configure() method returned, configurers must not be retained",
+			t,
+			method,
+		)
+	}
+}
+
+// end marks the Configure() method as having returned.
+func (l *configurerLifecycle) end() {
+	l.done.Store(true)
+}
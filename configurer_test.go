@@ -0,0 +1,364 @@
+package configkit_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/dogmatiq/configkit"
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("func WithPanicContext()", func() {
+	var (
+		aggregate *AggregateMessageHandlerStub
+		app       *ApplicationStub
+		cause     error
+	)
+
+	BeforeEach(func() {
+		cause = errors.New("<cause>")
+
+		aggregate = &AggregateMessageHandlerStub{
+			ConfigureFunc: func(c dogma.AggregateConfigurer) {
+				c.Identity("<aggregate>", "14769f7f-87fe-48dd-916e-5bcab6ba6aca")
+				panic(cause)
+			},
+		}
+
+		app = &ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app>", "59a82a24-a181-41e8-9b93-17a6ce86956e")
+				c.Routes(
+					dogma.ViaAggregate(aggregate),
+				)
+			},
+		}
+	})
+
+	It("wraps panics that occur within a handler's Configure() method", func() {
+		var err error
+
+		func() {
+			defer Recover(&err)
+			FromApplication(app, WithPanicContext())
+		}()
+
+		var pe *PanicError
+		Expect(errors.As(err, &pe)).To(BeTrue())
+
+		Expect(pe.Path).To(Equal([]PanicEntity{
+			{
+				Identity: MustNewIdentity("<app>", "59a82a24-a181-41e8-9b93-17a6ce86956e"),
+				TypeName: "*github.com/dogmatiq/enginekit/enginetest/stubs.ApplicationStub",
+			},
+			{
+				HandlerType: AggregateHandlerType,
+				Identity:    MustNewIdentity("<aggregate>", "14769f7f-87fe-48dd-916e-5bcab6ba6aca"),
+				TypeName:    "*github.com/dogmatiq/enginekit/enginetest/stubs.AggregateMessageHandlerStub",
+			},
+		}))
+		Expect(pe.TypeName()).To(Equal("*github.com/dogmatiq/enginekit/enginetest/stubs.AggregateMessageHandlerStub"))
+		Expect(pe.Value).To(BeIdenticalTo(cause))
+		Expect(pe.Stack).To(ContainSubstring("configurer_test.go"))
+		Expect(err).To(MatchError(cause))
+		Expect(err).To(MatchError(
+			"panic in Configure() method" +
+				" of aggregate <aggregate>/14769f7f-87fe-48dd-916e-5bcab6ba6aca (*github.com/dogmatiq/enginekit/enginetest/stubs.AggregateMessageHandlerStub)" +
+				" within application <app>/59a82a24-a181-41e8-9b93-17a6ce86956e (*github.com/dogmatiq/enginekit/enginetest/stubs.ApplicationStub)" +
+				": <cause>",
+		))
+	})
+
+	It("describes entities that panic before their identity is configured", func() {
+		var err error
+
+		func() {
+			defer Recover(&err)
+			FromProjection(
+				&ProjectionMessageHandlerStub{
+					ConfigureFunc: func(dogma.ProjectionConfigurer) {
+						panic("<value>")
+					},
+				},
+				WithPanicContext(),
+			)
+		}()
+
+		Expect(err).To(MatchError(
+			"panic in Configure() method of projection (*github.com/dogmatiq/enginekit/enginetest/stubs.ProjectionMessageHandlerStub): <value>",
+		))
+	})
+
+	It("does not wrap configuration errors", func() {
+		aggregate.ConfigureFunc = func(c dogma.AggregateConfigurer) {
+			c.Identity("", "14769f7f-87fe-48dd-916e-5bcab6ba6aca")
+		}
+
+		var err error
+
+		func() {
+			defer Recover(&err)
+			FromApplication(app, WithPanicContext())
+		}()
+
+		Expect(err).To(BeAssignableToTypeOf(Error("")))
+	})
+
+	It("does not wrap panics when the option is not used", func() {
+		Expect(func() {
+			FromApplication(app)
+		}).To(PanicWith(cause))
+	})
+})
+
+var _ = Describe("func WithContext()", func() {
+	It("returns the configuration if Configure() returns before the context is done", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		cfg := FromApplication(
+			&ApplicationStub{
+				ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+					c.Identity("<app>", "59a82a24-a181-41e8-9b93-17a6ce86956e")
+				},
+			},
+			WithContext(ctx),
+		)
+
+		Expect(cfg.Identity()).To(Equal(MustNewIdentity("<app>", "59a82a24-a181-41e8-9b93-17a6ce86956e")))
+	})
+
+	It("panics with a ContextError if Configure() does not return before the context is done", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		release := make(chan struct{})
+		defer close(release)
+
+		app := &ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app>", "59a82a24-a181-41e8-9b93-17a6ce86956e")
+				c.Routes(
+					dogma.ViaIntegration(&IntegrationMessageHandlerStub{
+						ConfigureFunc: func(dogma.IntegrationConfigurer) {
+							<-release
+						},
+					}),
+				)
+			},
+		}
+
+		var err error
+
+		func() {
+			defer Recover(&err)
+			FromApplication(app, WithContext(ctx))
+		}()
+
+		Expect(err).To(MatchError(context.DeadlineExceeded))
+		Expect(err).To(MatchError(
+			"the Configure() method of *stubs.ApplicationStub did not return before the context was done: context deadline exceeded",
+		))
+	})
+
+	It("panics with a ContextError if the context is already done", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		var err error
+
+		func() {
+			defer Recover(&err)
+			FromProcess(&ProcessMessageHandlerStub{}, WithContext(ctx))
+		}()
+
+		var ce *ContextError
+		Expect(errors.As(err, &ce)).To(BeTrue())
+		Expect(ce.Cause).To(Equal(context.Canceled))
+	})
+
+	It("propagates panics that occur within Configure()", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		var err error
+
+		func() {
+			defer Recover(&err)
+			FromAggregate(
+				&AggregateMessageHandlerStub{
+					ConfigureFunc: func(c dogma.AggregateConfigurer) {
+						c.Identity("", "14769f7f-87fe-48dd-916e-5bcab6ba6aca")
+					},
+				},
+				WithContext(ctx),
+			)
+		}()
+
+		Expect(err).To(BeAssignableToTypeOf(Error("")))
+	})
+})
+
+var _ = Describe("func WithMetadata()", func() {
+	var app *ApplicationStub
+
+	BeforeEach(func() {
+		app = &ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app>", appKey)
+				c.Routes(
+					dogma.ViaProjection(&ProjectionMessageHandlerStub{
+						ConfigureFunc: func(c dogma.ProjectionConfigurer) {
+							c.Identity("<projection>", projectionKey)
+							c.Routes(
+								dogma.HandlesEvent[*EventStub[TypeA]](),
+							)
+						},
+					}),
+				)
+			},
+		}
+	})
+
+	It("attaches metadata to the application and its handlers", func() {
+		cfg := FromApplication(
+			app,
+			WithMetadata(MetadataSet{
+				appKey:        {OwnerMetadataKey: "<team>"},
+				projectionKey: {BoundedContextMetadataKey: "<context>"},
+			}),
+		)
+
+		Expect(cfg.Metadata()).To(Equal(Metadata{OwnerMetadataKey: "<team>"}))
+
+		h, ok := cfg.RichHandlers().ByKey(projectionKey)
+		Expect(ok).To(BeTrue())
+		Expect(h.Metadata()).To(Equal(Metadata{BoundedContextMetadataKey: "<context>"}))
+	})
+
+	It("merges the metadata when the option is used more than once", func() {
+		cfg := FromApplication(
+			app,
+			WithMetadata(MetadataSet{
+				appKey: {OwnerMetadataKey: "<team>", OnCallMetadataKey: "<rotation>"},
+			}),
+			WithMetadata(MetadataSet{
+				appKey: {OwnerMetadataKey: "<other team>"},
+			}),
+		)
+
+		Expect(cfg.Metadata()).To(Equal(Metadata{
+			OwnerMetadataKey:  "<other team>",
+			OnCallMetadataKey: "<rotation>",
+		}))
+	})
+
+	It("does not retain a reference to the metadata set", func() {
+		set := MetadataSet{
+			appKey: {OwnerMetadataKey: "<team>"},
+		}
+
+		cfg := FromApplication(app, WithMetadata(set))
+		set[appKey][OwnerMetadataKey] = "<other team>"
+
+		Expect(cfg.Metadata()).To(Equal(Metadata{OwnerMetadataKey: "<team>"}))
+	})
+
+	It("attaches no metadata when the option is not used", func() {
+		cfg := FromApplication(app)
+		Expect(cfg.Metadata()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("configurer lifecycle", func() {
+	It("panics if the application's configurer is used after Configure() returns", func() {
+		var retained dogma.ApplicationConfigurer
+
+		FromApplication(&ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app>", "59a82a24-a181-41e8-9b93-17a6ce86956e")
+				retained = c
+			},
+		})
+
+		Expect(func() {
+			retained.Routes()
+		}).To(PanicWith(Error(
+			"*stubs.ApplicationStub called Routes() on its configurer after its Configure() method returned, configurers must not be retained",
+		)))
+	})
+
+	DescribeTable(
+		"panics if a handler's configurer is used after Configure() returns",
+		func(configure func() func()) {
+			call := configure()
+			Expect(call).To(Panic())
+
+			var err error
+			func() {
+				defer Recover(&err)
+				call()
+			}()
+			Expect(err).To(MatchError(ContainSubstring("after its Configure() method returned")))
+		},
+		Entry("aggregate", func() func() {
+			var retained dogma.AggregateConfigurer
+			FromAggregate(&AggregateMessageHandlerStub{
+				ConfigureFunc: func(c dogma.AggregateConfigurer) {
+					c.Identity("<aggregate>", "14769f7f-87fe-48dd-916e-5bcab6ba6aca")
+					c.Routes(
+						dogma.HandlesCommand[*CommandStub[TypeA]](),
+						dogma.RecordsEvent[*EventStub[TypeA]](),
+					)
+					retained = c
+				},
+			})
+			return func() { retained.Disable() }
+		}),
+		Entry("process", func() func() {
+			var retained dogma.ProcessConfigurer
+			FromProcess(&ProcessMessageHandlerStub{
+				ConfigureFunc: func(c dogma.ProcessConfigurer) {
+					c.Identity("<process>", "bea52cf4-e403-4b18-819d-88ade7836308")
+					c.Routes(
+						dogma.HandlesEvent[*EventStub[TypeA]](),
+						dogma.ExecutesCommand[*CommandStub[TypeA]](),
+					)
+					retained = c
+				},
+			})
+			return func() { retained.Routes(dogma.HandlesEvent[*EventStub[TypeB]]()) }
+		}),
+		Entry("integration", func() func() {
+			var retained dogma.IntegrationConfigurer
+			FromIntegration(&IntegrationMessageHandlerStub{
+				ConfigureFunc: func(c dogma.IntegrationConfigurer) {
+					c.Identity("<integration>", "e28f056e-e5a0-4ee7-aaf1-1d1fe02fb6e3")
+					c.Routes(
+						dogma.HandlesCommand[*CommandStub[TypeA]](),
+					)
+					retained = c
+				},
+			})
+			return func() { retained.Identity("<other>", "e28f056e-e5a0-4ee7-aaf1-1d1fe02fb6e3") }
+		}),
+		Entry("projection", func() func() {
+			var retained dogma.ProjectionConfigurer
+			FromProjection(&ProjectionMessageHandlerStub{
+				ConfigureFunc: func(c dogma.ProjectionConfigurer) {
+					c.Identity("<projection>", "70fdf7fa-8d5c-4a49-a5e7-2f9a6c6b1b39")
+					c.Routes(
+						dogma.HandlesEvent[*EventStub[TypeA]](),
+					)
+					retained = c
+				},
+			})
+			return func() { retained.Disable() }
+		}),
+	)
+})
@@ -0,0 +1,105 @@
+package configkit
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dogmatiq/dogma"
+)
+
+// DefaultDeterminismRuns is the number of times that [CheckDeterminism]
+// configures an application if the number of runs is not specified.
+const DefaultDeterminismRuns = 3
+
+// NonDeterministicError indicates that an application, or one of its
+// handlers, produced a different configuration each time it was configured.
+type NonDeterministicError struct {
+	// Application is the identity of the application, as configured by the
+	// first run.
+	Application Identity
+
+	// Run is the 1-based number of the first run that produced a
+	// configuration that differs from that of the first run.
+	Run int
+
+	// Changes is the list of differences between the configuration produced
+	// by the first run and the configuration produced by the run in Run.
+	Changes []Change
+}
+
+func (e NonDeterministicError) Error() string {
+	var w strings.Builder
+
+	fmt.Fprintf(
+		&w,
+		"the configuration of the %s application is non-deterministic, run #%d differs from run #1:",
+		e.Application,
+		e.Run,
+	)
+
+	for _, c := range e.Changes {
+		w.WriteString("\n  - ")
+		w.WriteString(c.String())
+	}
+
+	return w.String()
+}
+
+// CheckDeterminism configures app several times and returns an error if any of
+// the resulting configurations differ.
+//
+// Each run calls the Configure() method of the application and of each of its
+// handlers, using the same configurers as [FromApplication]. runs is the total
+// number of runs, which must be at least 2. If it is zero,
+// [DefaultDeterminismRuns] is used. It returns an error if runs is 1 or
+// negative, without configuring the application.
+//
+// If the configurations differ, the error is a [NonDeterministicError] that
+// describes the identities, routes and disabled states that changed. If any of
+// the runs produces an invalid configuration, the error wraps the [Error] from
+// that run.
+func CheckDeterminism(app dogma.Application, runs int) error {
+	if runs == 0 {
+		runs = DefaultDeterminismRuns
+	} else if runs < 2 {
+		return fmt.Errorf(
+			"can not check for determinism using %d run(s), at least 2 runs are required",
+			runs,
+		)
+	}
+
+	first, err := configureRun(app, 1)
+	if err != nil {
+		return err
+	}
+
+	for run := 2; run <= runs; run++ {
+		cfg, err := configureRun(app, run)
+		if err != nil {
+			return err
+		}
+
+		if !IsApplicationEqual(first, cfg) {
+			return NonDeterministicError{
+				Application: first.Identity(),
+				Run:         run,
+				Changes:     Diff(first, cfg),
+			}
+		}
+	}
+
+	return nil
+}
+
+// configureRun returns the configuration of app, or an error that identifies
+// the run if the configuration is invalid.
+func configureRun(app dogma.Application, run int) (cfg Application, err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("run #%d: %w", run, err)
+		}
+	}()
+	defer Recover(&err)
+
+	return FromApplication(app), nil
+}
@@ -0,0 +1,165 @@
+package configkit_test
+
+import (
+	"errors"
+
+	. "github.com/dogmatiq/configkit"
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	"github.com/dogmatiq/enginekit/message"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("func CheckDeterminism()", func() {
+	var (
+		run        int
+		aggregate  *AggregateMessageHandlerStub
+		projection *ProjectionMessageHandlerStub
+		app        *ApplicationStub
+	)
+
+	BeforeEach(func() {
+		run = 0
+
+		aggregate = &AggregateMessageHandlerStub{
+			ConfigureFunc: func(c dogma.AggregateConfigurer) {
+				c.Identity("<aggregate>", "14769f7f-87fe-48dd-916e-5bcab6ba6aca")
+				c.Routes(
+					dogma.HandlesCommand[*CommandStub[TypeA]](),
+					dogma.RecordsEvent[*EventStub[TypeA]](),
+				)
+			},
+		}
+
+		projection = &ProjectionMessageHandlerStub{
+			ConfigureFunc: func(c dogma.ProjectionConfigurer) {
+				c.Identity("<projection>", "70fdf7fa-8d5c-4a49-a5e7-2f9a6c6b1b39")
+				c.Routes(
+					dogma.HandlesEvent[*EventStub[TypeA]](),
+				)
+			},
+		}
+
+		app = &ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				run++
+				c.Identity("<app>", "59a82a24-a181-41e8-9b93-17a6ce86956e")
+				c.Routes(
+					dogma.ViaAggregate(aggregate),
+					dogma.ViaProjection(projection),
+				)
+			},
+		}
+	})
+
+	It("returns nil if the configuration is the same on every run", func() {
+		err := CheckDeterminism(app, 5)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(run).To(Equal(5))
+	})
+
+	It("configures the application the default number of times if runs is zero", func() {
+		err := CheckDeterminism(app, 0)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(run).To(Equal(DefaultDeterminismRuns))
+	})
+
+	DescribeTable(
+		"it returns an error if fewer than 2 runs are requested",
+		func(runs int, expect string) {
+			err := CheckDeterminism(app, runs)
+			Expect(err).To(MatchError(expect))
+			Expect(run).To(Equal(0))
+		},
+		Entry("one run", 1, "can not check for determinism using 1 run(s), at least 2 runs are required"),
+		Entry("negative runs", -1, "can not check for determinism using -1 run(s), at least 2 runs are required"),
+	)
+
+	It("reports changes to a handler's disabled state", func() {
+		projection.ConfigureFunc = func(c dogma.ProjectionConfigurer) {
+			c.Identity("<projection>", "70fdf7fa-8d5c-4a49-a5e7-2f9a6c6b1b39")
+			c.Routes(
+				dogma.HandlesEvent[*EventStub[TypeA]](),
+			)
+			if run == 3 {
+				c.Disable()
+			}
+		}
+
+		err := CheckDeterminism(app, 3)
+
+		var nd NonDeterministicError
+		Expect(errors.As(err, &nd)).To(BeTrue())
+		Expect(nd).To(Equal(NonDeterministicError{
+			Application: MustNewIdentity("<app>", "59a82a24-a181-41e8-9b93-17a6ce86956e"),
+			Run:         3,
+			Changes: []Change{
+				{
+					Type:    HandlerDisabled,
+					Handler: MustNewIdentity("<projection>", "70fdf7fa-8d5c-4a49-a5e7-2f9a6c6b1b39"),
+				},
+			},
+		}))
+	})
+
+	It("reports changes to a handler's routes", func() {
+		aggregate.ConfigureFunc = func(c dogma.AggregateConfigurer) {
+			c.Identity("<aggregate>", "14769f7f-87fe-48dd-916e-5bcab6ba6aca")
+			c.Routes(
+				dogma.HandlesCommand[*CommandStub[TypeA]](),
+				dogma.RecordsEvent[*EventStub[TypeA]](),
+			)
+			if run%2 == 0 {
+				c.Routes(dogma.RecordsEvent[*EventStub[TypeB]]())
+			}
+		}
+
+		err := CheckDeterminism(app, 3)
+		Expect(err).To(MatchError(
+			"the configuration of the <app>/59a82a24-a181-41e8-9b93-17a6ce86956e application is non-deterministic, run #2 differs from run #1:" +
+				"\n  - route-added <aggregate>/14769f7f-87fe-48dd-916e-5bcab6ba6aca: produces event " + string(message.NameOf(&EventStub[TypeB]{})),
+		))
+	})
+
+	It("reports changes to the application's identity", func() {
+		app.ConfigureFunc = func(c dogma.ApplicationConfigurer) {
+			run++
+			if run == 1 {
+				c.Identity("<app>", "59a82a24-a181-41e8-9b93-17a6ce86956e")
+			} else {
+				c.Identity("<app>", "7d3927ce-d879-40a4-bd67-0fafc79d3c36")
+			}
+		}
+
+		err := CheckDeterminism(app, 2)
+
+		var nd NonDeterministicError
+		Expect(errors.As(err, &nd)).To(BeTrue())
+		Expect(nd.Changes).To(Equal([]Change{
+			{
+				Type:   ApplicationRekeyed,
+				Before: "59a82a24-a181-41e8-9b93-17a6ce86956e",
+				After:  "7d3927ce-d879-40a4-bd67-0fafc79d3c36",
+			},
+		}))
+	})
+
+	It("returns an error if any of the runs produces an invalid configuration", func() {
+		app.ConfigureFunc = func(c dogma.ApplicationConfigurer) {
+			run++
+			if run == 2 {
+				c.Identity("", "59a82a24-a181-41e8-9b93-17a6ce86956e")
+			} else {
+				c.Identity("<app>", "59a82a24-a181-41e8-9b93-17a6ce86956e")
+			}
+		}
+
+		err := CheckDeterminism(app, 3)
+		Expect(err).To(MatchError(HavePrefix("run #2: ")))
+
+		var cfgErr Error
+		Expect(errors.As(err, &cfgErr)).To(BeTrue())
+	})
+})
@@ -0,0 +1,322 @@
+package configkit
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/dogmatiq/enginekit/message"
+)
+
+// ChangeType is an enumeration of the types of changes between two
+// configurations of the same application.
+type ChangeType string
+
+const (
+	// ApplicationRenamed indicates that the name of the application changed.
+	ApplicationRenamed ChangeType = "application-renamed"
+
+	// ApplicationRekeyed indicates that the key of the application changed.
+	ApplicationRekeyed ChangeType = "application-rekeyed"
+
+	// ApplicationTypeChanged indicates that the Go type used to implement the
+	// application changed.
+	ApplicationTypeChanged ChangeType = "application-type-changed"
+
+	// HandlerAdded indicates that a handler was added to the application.
+	HandlerAdded ChangeType = "handler-added"
+
+	// HandlerRemoved indicates that a handler was removed from the
+	// application.
+	HandlerRemoved ChangeType = "handler-removed"
+
+	// HandlerRenamed indicates that the name of a handler changed.
+	HandlerRenamed ChangeType = "handler-renamed"
+
+	// HandlerTypeChanged indicates that the Go type used to implement a
+	// handler changed.
+	HandlerTypeChanged ChangeType = "handler-type-changed"
+
+	// HandlerEnabled indicates that a handler that was disabled is now
+	// enabled.
+	HandlerEnabled ChangeType = "handler-enabled"
+
+	// HandlerDisabled indicates that a handler that was enabled is now
+	// disabled.
+	HandlerDisabled ChangeType = "handler-disabled"
+
+	// MetadataChanged indicates that the metadata of the application or a
+	// handler changed.
+	MetadataChanged ChangeType = "metadata-changed"
+
+	// RouteAdded indicates that a handler now produces or consumes a message
+	// that it did not previously.
+	RouteAdded ChangeType = "route-added"
+
+	// RouteRemoved indicates that a handler no longer produces or consumes a
+	// message that it did previously.
+	RouteRemoved ChangeType = "route-removed"
+)
+
+// Change describes a single difference between two configurations of the same
+// application.
+type Change struct {
+	// Type is the type of the change.
+	Type ChangeType
+
+	// Handler is the identity of the handler that changed. It is the identity
+	// after the change, except for [HandlerRemoved] changes. It is the zero
+	// value for changes to the application itself.
+	Handler Identity
+
+	// Route is the route that was added or removed. It is only populated for
+	// [RouteAdded] and [RouteRemoved] changes.
+	Route Route
+
+	// Before and After are the values before and after the change. They are
+	// only populated for changes to names, keys, Go types and metadata.
+	// Metadata is formatted using [Metadata.String].
+	Before, After string
+}
+
+// Route describes a handler's use of a single message.
+type Route struct {
+	// Message is the name of the message.
+	Message message.Name
+
+	// Kind is the kind of the message.
+	Kind message.Kind
+
+	// IsProduced is true if the handler produces the message, or false if it
+	// consumes the message.
+	IsProduced bool
+}
+
+// String returns a human-readable description of the route.
+func (r Route) String() string {
+	verb := "consumes"
+	if r.IsProduced {
+		verb = "produces"
+	}
+
+	return fmt.Sprintf("%s %s %s", verb, r.Kind, r.Message)
+}
+
+// String returns a human-readable description of the change.
+func (c Change) String() string {
+	switch c.Type {
+	case ApplicationRenamed, ApplicationRekeyed, ApplicationTypeChanged:
+		return fmt.Sprintf("%s: %q -> %q", c.Type, c.Before, c.After)
+	case HandlerRenamed, HandlerTypeChanged:
+		return fmt.Sprintf("%s %s: %q -> %q", c.Type, c.Handler, c.Before, c.After)
+	case RouteAdded, RouteRemoved:
+		return fmt.Sprintf("%s %s: %s", c.Type, c.Handler, c.Route)
+	case MetadataChanged:
+		if c.Handler.IsZero() {
+			return fmt.Sprintf("%s: {%s} -> {%s}", c.Type, c.Before, c.After)
+		}
+		return fmt.Sprintf("%s %s: {%s} -> {%s}", c.Type, c.Handler, c.Before, c.After)
+	default:
+		return fmt.Sprintf("%s %s", c.Type, c.Handler)
+	}
+}
+
+// Diff returns the changes required to turn the configuration in before into
+// the configuration in after. They are typically configurations of the same
+// application, that is, with the same identity key.
+//
+// Handlers are matched by their identity keys, such that a change to a
+// handler's name is reported as a [HandlerRenamed] change. A handler whose key
+// is reused by a handler of a different [HandlerType] is reported as having
+// been removed and added.
+//
+// The changes are ordered such that changes to the application come first,
+// followed by changes to each handler in order of their keys. It returns no
+// changes if [IsApplicationEqual] reports that before and after are equal.
+func Diff(before, after Application) []Change {
+	var changes []Change
+
+	b, a := before.Identity(), after.Identity()
+
+	if b.Name != a.Name {
+		changes = append(changes, Change{
+			Type:   ApplicationRenamed,
+			Before: b.Name,
+			After:  a.Name,
+		})
+	}
+
+	if b.Key != a.Key {
+		changes = append(changes, Change{
+			Type:   ApplicationRekeyed,
+			Before: b.Key,
+			After:  a.Key,
+		})
+	}
+
+	if before.TypeName() != after.TypeName() {
+		changes = append(changes, Change{
+			Type:   ApplicationTypeChanged,
+			Before: before.TypeName(),
+			After:  after.TypeName(),
+		})
+	}
+
+	if c, ok := diffMetadata(Identity{}, before, after); ok {
+		changes = append(changes, c)
+	}
+
+	prev := handlersByKey(before.Handlers())
+	next := handlersByKey(after.Handlers())
+
+	var keys []string
+	for k := range prev {
+		keys = append(keys, k)
+	}
+	for k := range next {
+		if _, ok := prev[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+
+	for _, k := range keys {
+		changes = append(changes, diffHandler(prev[k], next[k])...)
+	}
+
+	return changes
+}
+
+// handlersByKey returns a map of handler key to handler.
+func handlersByKey(s HandlerSet) map[string]Handler {
+	m := make(map[string]Handler, len(s))
+	for id, h := range s {
+		m[id.Key] = h
+	}
+	return m
+}
+
+// diffHandler returns the changes required to turn before into after. Either
+// handler may be nil.
+func diffHandler(before, after Handler) []Change {
+	if before != nil && after != nil && before.HandlerType() != after.HandlerType() {
+		return append(diffHandler(before, nil), diffHandler(nil, after)...)
+	}
+
+	if before == nil {
+		return []Change{{Type: HandlerAdded, Handler: after.Identity()}}
+	}
+
+	if after == nil {
+		return []Change{{Type: HandlerRemoved, Handler: before.Identity()}}
+	}
+
+	var changes []Change
+	id := after.Identity()
+
+	if n := before.Identity().Name; n != id.Name {
+		changes = append(changes, Change{
+			Type:    HandlerRenamed,
+			Handler: id,
+			Before:  n,
+			After:   id.Name,
+		})
+	}
+
+	if before.TypeName() != after.TypeName() {
+		changes = append(changes, Change{
+			Type:    HandlerTypeChanged,
+			Handler: id,
+			Before:  before.TypeName(),
+			After:   after.TypeName(),
+		})
+	}
+
+	if before.IsDisabled() != after.IsDisabled() {
+		t := HandlerEnabled
+		if after.IsDisabled() {
+			t = HandlerDisabled
+		}
+
+		changes = append(changes, Change{
+			Type:    t,
+			Handler: id,
+		})
+	}
+
+	if c, ok := diffMetadata(id, before, after); ok {
+		changes = append(changes, c)
+	}
+
+	prev := routes(before.MessageNames())
+	next := routes(after.MessageNames())
+
+	for _, r := range prev {
+		if !slices.Contains(next, r) {
+			changes = append(changes, Change{
+				Type:    RouteRemoved,
+				Handler: id,
+				Route:   r,
+			})
+		}
+	}
+
+	for _, r := range next {
+		if !slices.Contains(prev, r) {
+			changes = append(changes, Change{
+				Type:    RouteAdded,
+				Handler: id,
+				Route:   r,
+			})
+		}
+	}
+
+	return changes
+}
+
+// diffMetadata returns a [MetadataChanged] change if the metadata of before
+// and after differs.
+func diffMetadata(id Identity, before, after Entity) (Change, bool) {
+	b, a := before.Metadata(), after.Metadata()
+
+	if b.IsEqual(a) {
+		return Change{}, false
+	}
+
+	return Change{
+		Type:    MetadataChanged,
+		Handler: id,
+		Before:  b.String(),
+		After:   a.String(),
+	}, true
+}
+
+// routes returns the routes described by m, ordered by message name, with
+// consumed messages before produced messages.
+func routes(m EntityMessages[message.Name]) []Route {
+	var result []Route
+
+	for n, em := range m {
+		if em.IsConsumed {
+			result = append(result, Route{n, em.Kind, false})
+		}
+		if em.IsProduced {
+			result = append(result, Route{n, em.Kind, true})
+		}
+	}
+
+	slices.SortFunc(result, func(a, b Route) int {
+		if c := strings.Compare(string(a.Message), string(b.Message)); c != 0 {
+			return c
+		}
+		if a.IsProduced == b.IsProduced {
+			return 0
+		}
+		if a.IsProduced {
+			return 1
+		}
+		return -1
+	})
+
+	return result
+}
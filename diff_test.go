@@ -0,0 +1,259 @@
+package configkit_test
+
+import (
+	. "github.com/dogmatiq/configkit"
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	"github.com/dogmatiq/enginekit/message"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("func Diff()", func() {
+	var (
+		aggregate  *AggregateMessageHandlerStub
+		projection *ProjectionMessageHandlerStub
+		app        *ApplicationStub
+	)
+
+	BeforeEach(func() {
+		aggregate = &AggregateMessageHandlerStub{
+			ConfigureFunc: func(c dogma.AggregateConfigurer) {
+				c.Identity("<aggregate>", "14769f7f-87fe-48dd-916e-5bcab6ba6aca")
+				c.Routes(
+					dogma.HandlesCommand[*CommandStub[TypeA]](),
+					dogma.RecordsEvent[*EventStub[TypeA]](),
+				)
+			},
+		}
+
+		projection = &ProjectionMessageHandlerStub{
+			ConfigureFunc: func(c dogma.ProjectionConfigurer) {
+				c.Identity("<projection>", "70fdf7fa-8d5c-4a49-a5e7-2f9a6c6b1b39")
+				c.Routes(
+					dogma.HandlesEvent[*EventStub[TypeA]](),
+				)
+			},
+		}
+
+		app = &ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app>", "59a82a24-a181-41e8-9b93-17a6ce86956e")
+				c.Routes(
+					dogma.ViaAggregate(aggregate),
+					dogma.ViaProjection(projection),
+				)
+			},
+		}
+	})
+
+	It("returns no changes if the configurations are equal", func() {
+		before := FromApplication(app)
+		after := FromApplication(app)
+
+		Expect(Diff(before, after)).To(BeEmpty())
+	})
+
+	It("reports changes to the application's name", func() {
+		before := FromApplication(app)
+
+		app.ConfigureFunc = func(c dogma.ApplicationConfigurer) {
+			c.Identity("<renamed>", "59a82a24-a181-41e8-9b93-17a6ce86956e")
+			c.Routes(
+				dogma.ViaAggregate(aggregate),
+				dogma.ViaProjection(projection),
+			)
+		}
+
+		Expect(Diff(before, FromApplication(app))).To(Equal([]Change{
+			{
+				Type:   ApplicationRenamed,
+				Before: "<app>",
+				After:  "<renamed>",
+			},
+		}))
+	})
+
+	It("reports handlers that are added and removed", func() {
+		before := FromApplication(app)
+
+		app.ConfigureFunc = func(c dogma.ApplicationConfigurer) {
+			c.Identity("<app>", "59a82a24-a181-41e8-9b93-17a6ce86956e")
+			c.Routes(
+				dogma.ViaAggregate(aggregate),
+				dogma.ViaIntegration(&IntegrationMessageHandlerStub{
+					ConfigureFunc: func(c dogma.IntegrationConfigurer) {
+						c.Identity("<integration>", "e28f056e-e5a0-4ee7-aaf1-1d1fe02fb6e3")
+						c.Routes(
+							dogma.HandlesCommand[*CommandStub[TypeB]](),
+						)
+					},
+				}),
+			)
+		}
+
+		Expect(Diff(before, FromApplication(app))).To(Equal([]Change{
+			{
+				Type:    HandlerRemoved,
+				Handler: MustNewIdentity("<projection>", "70fdf7fa-8d5c-4a49-a5e7-2f9a6c6b1b39"),
+			},
+			{
+				Type:    HandlerAdded,
+				Handler: MustNewIdentity("<integration>", "e28f056e-e5a0-4ee7-aaf1-1d1fe02fb6e3"),
+			},
+		}))
+	})
+
+	It("reports changes to a handler's name, disabled state and routes", func() {
+		before := FromApplication(app)
+
+		aggregate.ConfigureFunc = func(c dogma.AggregateConfigurer) {
+			c.Identity("<renamed>", "14769f7f-87fe-48dd-916e-5bcab6ba6aca")
+			c.Routes(
+				dogma.HandlesCommand[*CommandStub[TypeA]](),
+				dogma.RecordsEvent[*EventStub[TypeB]](),
+			)
+			c.Disable()
+		}
+
+		id := MustNewIdentity("<renamed>", "14769f7f-87fe-48dd-916e-5bcab6ba6aca")
+
+		Expect(Diff(before, FromApplication(app))).To(Equal([]Change{
+			{
+				Type:    HandlerRenamed,
+				Handler: id,
+				Before:  "<aggregate>",
+				After:   "<renamed>",
+			},
+			{
+				Type:    HandlerDisabled,
+				Handler: id,
+			},
+			{
+				Type:    RouteRemoved,
+				Handler: id,
+				Route: Route{
+					Message:    message.NameOf(&EventStub[TypeA]{}),
+					Kind:       message.EventKind,
+					IsProduced: true,
+				},
+			},
+			{
+				Type:    RouteAdded,
+				Handler: id,
+				Route: Route{
+					Message:    message.NameOf(&EventStub[TypeB]{}),
+					Kind:       message.EventKind,
+					IsProduced: true,
+				},
+			},
+		}))
+	})
+
+	It("reports handlers that are enabled", func() {
+		projection.ConfigureFunc = func(c dogma.ProjectionConfigurer) {
+			c.Identity("<projection>", "70fdf7fa-8d5c-4a49-a5e7-2f9a6c6b1b39")
+			c.Routes(
+				dogma.HandlesEvent[*EventStub[TypeA]](),
+			)
+			c.Disable()
+		}
+
+		before := FromApplication(app)
+
+		projection.ConfigureFunc = func(c dogma.ProjectionConfigurer) {
+			c.Identity("<projection>", "70fdf7fa-8d5c-4a49-a5e7-2f9a6c6b1b39")
+			c.Routes(
+				dogma.HandlesEvent[*EventStub[TypeA]](),
+			)
+		}
+
+		Expect(Diff(before, FromApplication(app))).To(Equal([]Change{
+			{
+				Type:    HandlerEnabled,
+				Handler: MustNewIdentity("<projection>", "70fdf7fa-8d5c-4a49-a5e7-2f9a6c6b1b39"),
+			},
+		}))
+	})
+
+	It("reports changes to metadata", func() {
+		before := FromApplication(app)
+		after := FromApplication(app, WithMetadata(MetadataSet{
+			"59a82a24-a181-41e8-9b93-17a6ce86956e": {OwnerMetadataKey: "payments"},
+			"14769f7f-87fe-48dd-916e-5bcab6ba6aca": {OwnerMetadataKey: "orders", OnCallMetadataKey: "orders-oncall"},
+		}))
+
+		Expect(Diff(before, after)).To(Equal([]Change{
+			{
+				Type:  MetadataChanged,
+				After: `owner="payments"`,
+			},
+			{
+				Type:    MetadataChanged,
+				Handler: MustNewIdentity("<aggregate>", "14769f7f-87fe-48dd-916e-5bcab6ba6aca"),
+				After:   `on-call="orders-oncall", owner="orders"`,
+			},
+		}))
+	})
+
+	It("reports a handler whose key is reused by a different handler type as removed and added", func() {
+		before := FromApplication(app)
+
+		app.ConfigureFunc = func(c dogma.ApplicationConfigurer) {
+			c.Identity("<app>", "59a82a24-a181-41e8-9b93-17a6ce86956e")
+			c.Routes(
+				dogma.ViaAggregate(aggregate),
+				dogma.ViaIntegration(&IntegrationMessageHandlerStub{
+					ConfigureFunc: func(c dogma.IntegrationConfigurer) {
+						c.Identity("<projection>", "70fdf7fa-8d5c-4a49-a5e7-2f9a6c6b1b39")
+						c.Routes(
+							dogma.HandlesCommand[*CommandStub[TypeB]](),
+						)
+					},
+				}),
+			)
+		}
+
+		id := MustNewIdentity("<projection>", "70fdf7fa-8d5c-4a49-a5e7-2f9a6c6b1b39")
+
+		Expect(Diff(before, FromApplication(app))).To(Equal([]Change{
+			{Type: HandlerRemoved, Handler: id},
+			{Type: HandlerAdded, Handler: id},
+		}))
+	})
+})
+
+var _ = Describe("type Change", func() {
+	Describe("func String()", func() {
+		It("describes the change", func() {
+			id := MustNewIdentity("<handler>", "14769f7f-87fe-48dd-916e-5bcab6ba6aca")
+
+			Expect(Change{
+				Type:   ApplicationRenamed,
+				Before: "<a>",
+				After:  "<b>",
+			}.String()).To(Equal(`application-renamed: "<a>" -> "<b>"`))
+
+			Expect(Change{
+				Type:    HandlerDisabled,
+				Handler: id,
+			}.String()).To(Equal("handler-disabled <handler>/14769f7f-87fe-48dd-916e-5bcab6ba6aca"))
+
+			Expect(Change{
+				Type:    RouteAdded,
+				Handler: id,
+				Route: Route{
+					Message: "pkg.Command",
+					Kind:    message.CommandKind,
+				},
+			}.String()).To(Equal("route-added <handler>/14769f7f-87fe-48dd-916e-5bcab6ba6aca: consumes command pkg.Command"))
+
+			Expect(Change{
+				Type:    MetadataChanged,
+				Handler: id,
+				Before:  `owner="a"`,
+				After:   `owner="b"`,
+			}.String()).To(Equal(`metadata-changed <handler>/14769f7f-87fe-48dd-916e-5bcab6ba6aca: {owner="a"} -> {owner="b"}`))
+		})
+	})
+})
@@ -0,0 +1,130 @@
+package configkit
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/dogmatiq/enginekit/message"
+)
+
+// DispatchTable maps each message type used by an application to the handlers
+// that consume it.
+//
+// It is immutable and safe for concurrent use. Lookups do not acquire any locks
+// and do not allocate memory, except when returning an error. As such, the
+// slices returned by lookups are shared and must not be modified.
+//
+// Disabled handlers are not included in the table.
+type DispatchTable struct {
+	app      Identity
+	disabled map[message.Type]RichHandler
+	commands map[message.Type]RichHandler
+	events   map[message.Type][]RichHandler
+	timeouts map[message.Type][]RichHandler
+}
+
+// NewDispatchTable returns a dispatch table for the given application.
+func NewDispatchTable(app RichApplication) *DispatchTable {
+	t := &DispatchTable{
+		app:      app.Identity(),
+		disabled: map[message.Type]RichHandler{},
+		commands: map[message.Type]RichHandler{},
+		events:   map[message.Type][]RichHandler{},
+		timeouts: map[message.Type][]RichHandler{},
+	}
+
+	for h := range app.RichHandlers().Sorted() {
+		for mt, kind := range h.MessageTypes().Consumed() {
+			if h.IsDisabled() {
+				if kind == message.CommandKind {
+					t.disabled[mt] = h
+				}
+				continue
+			}
+
+			switch kind {
+			case message.CommandKind:
+				t.commands[mt] = h
+			case message.EventKind:
+				t.events[mt] = append(t.events[mt], h)
+			case message.TimeoutKind:
+				t.timeouts[mt] = append(t.timeouts[mt], h)
+			}
+		}
+	}
+
+	// Clip the slices so that appending to a result of a lookup can not
+	// modify the table.
+	for mt, handlers := range t.events {
+		t.events[mt] = slices.Clip(handlers)
+	}
+	for mt, handlers := range t.timeouts {
+		t.timeouts[mt] = slices.Clip(handlers)
+	}
+
+	return t
+}
+
+// CommandHandler returns the handler that handles commands of type mt.
+//
+// It returns an [UnhandledCommandError] if no enabled handler handles commands
+// of that type.
+func (t *DispatchTable) CommandHandler(mt message.Type) (RichHandler, error) {
+	if h, ok := t.commands[mt]; ok {
+		return h, nil
+	}
+
+	return nil, &UnhandledCommandError{
+		Application:     t.app,
+		MessageType:     mt,
+		DisabledHandler: t.disabled[mt],
+	}
+}
+
+// EventConsumers returns the handlers that consume events of type mt, ordered
+// by handler type, then by name.
+//
+// The returned slice is shared by all callers and must not be modified.
+func (t *DispatchTable) EventConsumers(mt message.Type) []RichHandler {
+	return t.events[mt]
+}
+
+// TimeoutOwners returns the handlers that schedule and handle timeouts of type
+// mt, ordered by handler type, then by name.
+//
+// The returned slice is shared by all callers and must not be modified.
+func (t *DispatchTable) TimeoutOwners(mt message.Type) []RichHandler {
+	return t.timeouts[mt]
+}
+
+// UnhandledCommandError is returned by [DispatchTable.CommandHandler] when
+// there is no enabled handler for a command type.
+type UnhandledCommandError struct {
+	// Application is the identity of the application.
+	Application Identity
+
+	// MessageType is the type of the command.
+	MessageType message.Type
+
+	// DisabledHandler is the handler that handles the command, if it is
+	// disabled. It is nil if no handler is configured to handle the command.
+	DisabledHandler RichHandler
+}
+
+func (e *UnhandledCommandError) Error() string {
+	if e.DisabledHandler != nil {
+		return fmt.Sprintf(
+			"the %s application can not handle %s commands, the %s %s handler is disabled",
+			e.Application,
+			e.MessageType,
+			e.DisabledHandler.Identity(),
+			e.DisabledHandler.HandlerType(),
+		)
+	}
+
+	return fmt.Sprintf(
+		"the %s application does not have a handler for %s commands",
+		e.Application,
+		e.MessageType,
+	)
+}
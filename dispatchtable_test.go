@@ -0,0 +1,150 @@
+package configkit_test
+
+import (
+	"testing"
+
+	. "github.com/dogmatiq/configkit"
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	"github.com/dogmatiq/enginekit/message"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("type DispatchTable", func() {
+	var (
+		app   RichApplication
+		table *DispatchTable
+	)
+
+	BeforeEach(func() {
+		app = FromApplication(&ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app>", appKey)
+				c.Routes(
+					dogma.ViaAggregate(&AggregateMessageHandlerStub{
+						ConfigureFunc: func(c dogma.AggregateConfigurer) {
+							c.Identity("<aggregate>", aggregateKey)
+							c.Routes(
+								dogma.HandlesCommand[*CommandStub[TypeA]](),
+								dogma.RecordsEvent[*EventStub[TypeA]](),
+							)
+						},
+					}),
+					dogma.ViaProcess(&ProcessMessageHandlerStub{
+						ConfigureFunc: func(c dogma.ProcessConfigurer) {
+							c.Identity("<process>", processKey)
+							c.Routes(
+								dogma.HandlesEvent[*EventStub[TypeA]](),
+								dogma.ExecutesCommand[*CommandStub[TypeB]](),
+								dogma.SchedulesTimeout[*TimeoutStub[TypeA]](),
+							)
+						},
+					}),
+					dogma.ViaIntegration(&IntegrationMessageHandlerStub{
+						ConfigureFunc: func(c dogma.IntegrationConfigurer) {
+							c.Identity("<integration>", integrationKey)
+							c.Routes(
+								dogma.HandlesCommand[*CommandStub[TypeB]](),
+								dogma.HandlesCommand[*CommandStub[TypeC]](),
+							)
+							c.Disable()
+						},
+					}),
+					dogma.ViaProjection(&ProjectionMessageHandlerStub{
+						ConfigureFunc: func(c dogma.ProjectionConfigurer) {
+							c.Identity("<projection>", projectionKey)
+							c.Routes(
+								dogma.HandlesEvent[*EventStub[TypeA]](),
+							)
+						},
+					}),
+				)
+			},
+		})
+
+		table = NewDispatchTable(app)
+	})
+
+	Describe("func CommandHandler()", func() {
+		It("returns the handler that handles the command", func() {
+			h, err := table.CommandHandler(message.TypeOf(CommandA1))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(h.Identity().Name).To(Equal("<aggregate>"))
+		})
+
+		It("returns an error if no handler handles the command", func() {
+			_, err := table.CommandHandler(message.TypeOf(CommandD1))
+			Expect(err).To(MatchError(
+				"the <app>/" + appKey + " application does not have a handler for *stubs.CommandStub[TypeD] commands",
+			))
+
+			var e *UnhandledCommandError
+			Expect(err).To(BeAssignableToTypeOf(e))
+		})
+
+		It("returns an error if the handler that handles the command is disabled", func() {
+			_, err := table.CommandHandler(message.TypeOf(CommandB1))
+			Expect(err).To(MatchError(
+				"the <app>/" + appKey + " application can not handle *stubs.CommandStub[TypeB] commands, the <integration>/" + integrationKey + " integration handler is disabled",
+			))
+		})
+
+		It("does not allocate memory", func() {
+			mt := message.TypeOf(CommandA1)
+
+			allocs := testing.AllocsPerRun(100, func() {
+				table.CommandHandler(mt)
+			})
+			Expect(allocs).To(BeZero())
+		})
+	})
+
+	Describe("func EventConsumers()", func() {
+		It("returns the handlers that consume the event, ordered by handler type", func() {
+			var names []string
+			for _, h := range table.EventConsumers(message.TypeOf(EventA1)) {
+				names = append(names, h.Identity().Name)
+			}
+
+			Expect(names).To(Equal([]string{"<process>", "<projection>"}))
+		})
+
+		It("returns an empty slice if no handlers consume the event", func() {
+			Expect(table.EventConsumers(message.TypeOf(EventB1))).To(BeEmpty())
+		})
+
+		It("does not allocate memory", func() {
+			mt := message.TypeOf(EventA1)
+
+			allocs := testing.AllocsPerRun(100, func() {
+				table.EventConsumers(mt)
+			})
+			Expect(allocs).To(BeZero())
+		})
+	})
+
+	Describe("func TimeoutOwners()", func() {
+		It("returns the handlers that own the timeout", func() {
+			handlers := table.TimeoutOwners(message.TypeOf(TimeoutA1))
+			Expect(handlers).To(HaveLen(1))
+			Expect(handlers[0].Identity().Name).To(Equal("<process>"))
+		})
+
+		It("returns an empty slice if no handlers own the timeout", func() {
+			Expect(table.TimeoutOwners(message.TypeOf(TimeoutB1))).To(BeEmpty())
+		})
+	})
+})
+
+func BenchmarkDispatchTable_EventConsumers(b *testing.B) {
+	table := NewDispatchTable(FromApplication(largeApplication(1000)))
+	mt := message.TypeOf(EventA1)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for b.Loop() {
+		table.EventConsumers(mt)
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 	"iter"
+	"maps"
 	"reflect"
 	"slices"
+	"strings"
 
 	"github.com/dogmatiq/enginekit/message"
 )
@@ -14,7 +16,10 @@ import (
 // such as an application or handler.
 //
 // Each implementation of this interface represents the configuration described
-// by a call to the entity's Configure() method.
+// by a call to the entity's Configure() method. Configurations are immutable
+// and safe for concurrent use. Methods that return maps or sets return values
+// that are shared by all callers and must not be modified. Use their Clone()
+// methods to obtain a copy that can be modified.
 type Entity interface {
 	// Identity returns the identity of the entity.
 	Identity() Identity
@@ -25,6 +30,9 @@ type Entity interface {
 	// MessageNames returns information about the messages used by the entity.
 	MessageNames() EntityMessages[message.Name]
 
+	// Metadata returns the metadata attached to the entity.
+	Metadata() Metadata
+
 	// AcceptVisitor calls the appropriate method on v for this entity type.
 	AcceptVisitor(ctx context.Context, v Visitor) error
 }
@@ -69,6 +77,11 @@ func (m EntityMessages[K]) IsEqual(n EntityMessages[K]) bool {
 	return true
 }
 
+// Clone returns a copy of m that can be modified without affecting m.
+func (m EntityMessages[K]) Clone() EntityMessages[K] {
+	return maps.Clone(m)
+}
+
 // Produced returns an iterator that yields the messages that are produced by
 // the entity.
 func (m EntityMessages[K]) Produced(filter ...message.Kind) iter.Seq2[K, message.Kind] {
@@ -101,6 +114,24 @@ func (m EntityMessages[K]) Consumed(filter ...message.Kind) iter.Seq2[K, message
 	}
 }
 
+// SortedProduced returns an iterator that yields the messages that are produced
+// by the entity, in a deterministic order.
+//
+// Messages are ordered by name, except that timeout messages are always
+// yielded after all other messages.
+func (m EntityMessages[K]) SortedProduced(filter ...message.Kind) iter.Seq2[K, message.Kind] {
+	return sortedMessages(m.Produced(filter...))
+}
+
+// SortedConsumed returns an iterator that yields the messages that are consumed
+// by the entity, in a deterministic order.
+//
+// Messages are ordered by name, except that timeout messages are always
+// yielded after all other messages.
+func (m EntityMessages[K]) SortedConsumed(filter ...message.Kind) iter.Seq2[K, message.Kind] {
+	return sortedMessages(m.Consumed(filter...))
+}
+
 // Update updates the message with the given key by calling fn.
 //
 // If, after calling fn, the [EntityMessage] is neither produced nor consumed,
@@ -139,6 +170,57 @@ func (m EntityMessages[K]) merge(n EntityMessages[K]) {
 	}
 }
 
+// sortedMessages returns an iterator that yields the messages from seq, sorted
+// by name, with timeout messages last.
+func sortedMessages[K comparable](seq iter.Seq2[K, message.Kind]) iter.Seq2[K, message.Kind] {
+	type entry struct {
+		Key  K
+		Name string
+		Kind message.Kind
+	}
+
+	return func(yield func(K, message.Kind) bool) {
+		var entries []entry
+		for k, kind := range seq {
+			entries = append(entries, entry{k, messageKeyName(k), kind})
+		}
+
+		slices.SortFunc(
+			entries,
+			func(a, b entry) int {
+				if a.Kind == message.TimeoutKind && b.Kind != message.TimeoutKind {
+					return +1
+				}
+
+				if a.Kind != message.TimeoutKind && b.Kind == message.TimeoutKind {
+					return -1
+				}
+
+				return strings.Compare(a.Name, b.Name)
+			},
+		)
+
+		for _, e := range entries {
+			if !yield(e.Key, e.Kind) {
+				return
+			}
+		}
+	}
+}
+
+// messageKeyName returns the name of the message identified by k, which is
+// typically either a [message.Name] or a [message.Type].
+func messageKeyName[K comparable](k K) string {
+	switch k := any(k).(type) {
+	case message.Name:
+		return string(k)
+	case message.Type:
+		return string(k.Name())
+	default:
+		return fmt.Sprint(k)
+	}
+}
+
 func asMessageNames(types EntityMessages[message.Type]) EntityMessages[message.Name] {
 	names := make(EntityMessages[message.Name], len(types))
 
@@ -120,4 +120,100 @@ var _ = Describe("type EntityMessages", func() {
 			),
 		)
 	})
+
+	Describe("func Clone()", func() {
+		It("returns a copy that can be modified independently", func() {
+			m := EntityMessages[message.Name]{
+				message.NameOf(CommandA1): {
+					Kind:       message.CommandKind,
+					IsConsumed: true,
+				},
+			}
+
+			c := m.Clone()
+			Expect(c).To(Equal(m))
+
+			delete(c, message.NameOf(CommandA1))
+			Expect(m).To(HaveKey(message.NameOf(CommandA1)))
+		})
+	})
+
+	Describe("func SortedProduced()", func() {
+		It("yields the produced messages ordered by name, with timeouts last", func() {
+			m := EntityMessages[message.Type]{
+				message.TypeOf(TimeoutA1): {Kind: message.TimeoutKind, IsProduced: true, IsConsumed: true},
+				message.TypeOf(EventB1):   {Kind: message.EventKind, IsProduced: true},
+				message.TypeOf(CommandA1): {Kind: message.CommandKind, IsProduced: true},
+				message.TypeOf(EventA1):   {Kind: message.EventKind, IsProduced: true},
+				message.TypeOf(CommandB1): {Kind: message.CommandKind, IsConsumed: true},
+			}
+
+			var types []message.Type
+			for t := range m.SortedProduced() {
+				types = append(types, t)
+			}
+
+			Expect(types).To(Equal([]message.Type{
+				message.TypeOf(CommandA1),
+				message.TypeOf(EventA1),
+				message.TypeOf(EventB1),
+				message.TypeOf(TimeoutA1),
+			}))
+		})
+
+		It("yields only messages of the given kinds", func() {
+			m := EntityMessages[message.Name]{
+				message.NameOf(EventA1):   {Kind: message.EventKind, IsProduced: true},
+				message.NameOf(CommandA1): {Kind: message.CommandKind, IsProduced: true},
+			}
+
+			var names []message.Name
+			for n := range m.SortedProduced(message.EventKind) {
+				names = append(names, n)
+			}
+
+			Expect(names).To(Equal([]message.Name{
+				message.NameOf(EventA1),
+			}))
+		})
+	})
+
+	Describe("func SortedConsumed()", func() {
+		It("yields the consumed messages ordered by name, with timeouts last", func() {
+			m := EntityMessages[message.Name]{
+				message.NameOf(TimeoutA1): {Kind: message.TimeoutKind, IsProduced: true, IsConsumed: true},
+				message.NameOf(EventB1):   {Kind: message.EventKind, IsConsumed: true},
+				message.NameOf(EventA1):   {Kind: message.EventKind, IsConsumed: true},
+				message.NameOf(CommandA1): {Kind: message.CommandKind, IsProduced: true},
+			}
+
+			var names []message.Name
+			for n := range m.SortedConsumed() {
+				names = append(names, n)
+			}
+
+			Expect(names).To(Equal([]message.Name{
+				message.NameOf(EventA1),
+				message.NameOf(EventB1),
+				message.NameOf(TimeoutA1),
+			}))
+		})
+
+		It("stops iterating when the yield function returns false", func() {
+			m := EntityMessages[message.Name]{
+				message.NameOf(EventB1): {Kind: message.EventKind, IsConsumed: true},
+				message.NameOf(EventA1): {Kind: message.EventKind, IsConsumed: true},
+			}
+
+			var names []message.Name
+			for n := range m.SortedConsumed() {
+				names = append(names, n)
+				break
+			}
+
+			Expect(names).To(Equal([]message.Name{
+				message.NameOf(EventA1),
+			}))
+		})
+	})
 })
@@ -1,6 +1,10 @@
 package configkit
 
 import (
+	"fmt"
+	"reflect"
+	"strings"
+
 	"github.com/dogmatiq/configkit/internal/validation"
 )
 
@@ -9,8 +13,8 @@ type Error = validation.Error
 
 // Recover recovers from a configuration related panic.
 //
-// It is intended to be used in a defer statement. If the panic value is a
-// Error, it is assigned to *err.
+// It is intended to be used in a defer statement. If the panic value is an
+// [Error], a [*PanicError] or a [*ContextError], it is assigned to *err.
 func Recover(err *error) {
 	if err == nil {
 		panic("err must be a non-nil pointer")
@@ -19,9 +23,119 @@ func Recover(err *error) {
 	switch v := recover().(type) {
 	case Error:
 		*err = v
+	case *PanicError:
+		*err = v
+	case *ContextError:
+		*err = v
 	case nil:
 		return
 	default:
 		panic(v)
 	}
 }
+
+// PanicError describes a panic that occurred within an entity's Configure()
+// method, other than a panic caused by a configuration [Error].
+//
+// Panics are only wrapped in a PanicError when the [WithPanicContext] option
+// is used.
+type PanicError struct {
+	// Path describes the entities that were being configured when the panic
+	// occurred, from the outer-most entity, typically an application, to the
+	// entity whose Configure() method panicked.
+	Path []PanicEntity
+
+	// Value is the original panic value.
+	Value any
+
+	// Stack is the stack trace of the goroutine at the time of the panic, as
+	// per [debug.Stack].
+	Stack []byte
+}
+
+// PanicEntity describes an entity that was being configured when a panic
+// occurred.
+type PanicEntity struct {
+	// HandlerType is the type of the handler, or an empty string if the
+	// entity is an application.
+	HandlerType HandlerType
+
+	// Identity is the entity's identity, if it was configured before the panic
+	// occurred.
+	Identity Identity
+
+	// TypeName is the fully-qualified name of the Go type that implements the
+	// entity.
+	TypeName string
+}
+
+func (e PanicEntity) String() string {
+	kind := "application"
+	if e.HandlerType != "" {
+		kind = e.HandlerType.String()
+	}
+
+	if e.Identity.IsZero() {
+		return fmt.Sprintf("%s (%s)", kind, e.TypeName)
+	}
+
+	return fmt.Sprintf("%s %s (%s)", kind, e.Identity, e.TypeName)
+}
+
+// TypeName returns the fully-qualified name of the Go type that implements the
+// entity whose Configure() method panicked.
+func (e *PanicError) TypeName() string {
+	if len(e.Path) == 0 {
+		return ""
+	}
+	return e.Path[len(e.Path)-1].TypeName
+}
+
+func (e *PanicError) Error() string {
+	var w strings.Builder
+
+	w.WriteString("panic in Configure() method")
+
+	for i := len(e.Path) - 1; i >= 0; i-- {
+		if i == len(e.Path)-1 {
+			w.WriteString(" of ")
+		} else {
+			w.WriteString(" within ")
+		}
+		w.WriteString(e.Path[i].String())
+	}
+
+	fmt.Fprintf(&w, ": %v", e.Value)
+
+	return w.String()
+}
+
+// Unwrap returns the original panic value if it is an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// ContextError indicates that an entity's configuration was abandoned because
+// its Configure() method did not return before the context passed to
+// [WithContext] was done.
+type ContextError struct {
+	// Type is the type that implements the entity.
+	Type reflect.Type
+
+	// Cause is the context's error.
+	Cause error
+}
+
+func (e *ContextError) Error() string {
+	return fmt.Sprintf(
+		"the Configure() method of %s did not return before the context was done: %s",
+		e.Type,
+		e.Cause,
+	)
+}
+
+// Unwrap returns the context's error.
+func (e *ContextError) Unwrap() error {
+	return e.Cause
+}
@@ -27,6 +27,17 @@ var _ = Describe("func Recover()", func() {
 		Expect(err).To(Equal(Error("<value>")))
 	})
 
+	It("recovers from wrapped panics", func() {
+		pe := &PanicError{Value: "<value>"}
+
+		err := func() (err error) {
+			defer Recover(&err)
+			panic(pe)
+		}()
+
+		Expect(err).To(BeIdenticalTo(pe))
+	})
+
 	It("does not recover from unrelated panics", func() {
 		var value interface{}
 
@@ -0,0 +1,196 @@
+// Package explorer generates self-contained HTML reports that allow users to
+// explore the configuration of one or more applications.
+//
+// The report is a single HTML file that embeds all of its styles, scripts and
+// diagrams, so it can be viewed without network access.
+package explorer
+
+import (
+	_ "embed"
+	"html/template"
+	"io"
+	"strings"
+
+	"github.com/dogmatiq/configkit"
+	"github.com/dogmatiq/configkit/internal/flowgraph"
+	"github.com/dogmatiq/enginekit/message"
+)
+
+// Write writes an HTML report that describes apps to w.
+//
+// The applications may be obtained from [configkit.FromApplication], or from a
+// remote server using an [api.Client]. The options control how type names are
+// rendered within the report.
+//
+// [api.Client]: https://pkg.go.dev/github.com/dogmatiq/configkit/api#Client
+func Write(
+	w io.Writer,
+	apps []configkit.Application,
+	options ...configkit.RenderOption,
+) error {
+	names := configkit.NewTypeNameRenderer(options...)
+	for _, app := range apps {
+		names.AddEntity(app)
+	}
+
+	g := flowgraph.New(apps...)
+
+	r := report{
+		MessageKinds: []message.Kind{
+			message.CommandKind,
+			message.EventKind,
+			message.TimeoutKind,
+		},
+		HandlerTypes: configkit.HandlerTypes,
+	}
+
+	var diagram strings.Builder
+	g.WriteSVG(&diagram, names.Render)
+	r.Diagram = template.HTML(diagram.String())
+
+	handlerIDs := map[handlerKey]string{}
+	for _, a := range g.Applications {
+		for _, n := range a.Handlers {
+			handlerIDs[handlerKey{a.Identity, n.Handler.Identity()}] = n.ID
+		}
+	}
+
+	messageIDs := map[message.Name]string{}
+	for _, n := range g.Messages {
+		messageIDs[n.Name] = n.ID
+	}
+
+	for _, a := range g.Applications {
+		app := reportApplication{
+			Name:     a.Identity.Name,
+			Key:      a.Identity.Key,
+			TypeName: names.Render(a.TypeName),
+		}
+
+		for _, n := range a.Handlers {
+			h := reportHandler{
+				ID:          n.ID,
+				Type:        n.Handler.HandlerType().String(),
+				Name:        n.Handler.Identity().Name,
+				Key:         n.Handler.Identity().Key,
+				TypeName:    names.Render(n.Handler.TypeName()),
+				Application: a.Identity.Name,
+				Disabled:    n.Handler.IsDisabled(),
+			}
+
+			mn := n.Handler.MessageNames()
+
+			for name, k := range mn.SortedConsumed(message.CommandKind, message.EventKind) {
+				h.Messages = append(h.Messages, reportRoute{
+					Verb: "handles",
+					Kind: k.String(),
+					Ref:  reportRef{messageIDs[name], names.Render(string(name))},
+				})
+			}
+
+			for name, k := range mn.SortedProduced() {
+				h.Messages = append(h.Messages, reportRoute{
+					Verb: message.MapByKind(k, "executes", "records", "schedules"),
+					Kind: k.String(),
+					Ref:  reportRef{messageIDs[name], names.Render(string(name))},
+				})
+			}
+
+			h.Search = searchText(h.Name, h.Key, h.TypeName, h.Type, h.Application)
+
+			app.Handlers = append(app.Handlers, reportRef{n.ID, h.Name})
+			r.Handlers = append(r.Handlers, h)
+		}
+
+		r.Applications = append(r.Applications, app)
+	}
+
+	for _, n := range g.Messages {
+		m := reportMessage{
+			ID:    n.ID,
+			Kind:  n.Usage.Kind.String(),
+			Name:  string(n.Name),
+			Label: names.Render(string(n.Name)),
+		}
+
+		for _, x := range n.Usage.Producers {
+			m.Producers = append(m.Producers, handlerRef(handlerIDs, x))
+		}
+
+		for _, x := range n.Usage.Consumers {
+			m.Consumers = append(m.Consumers, handlerRef(handlerIDs, x))
+		}
+
+		m.Search = searchText(m.Name, m.Label, m.Kind)
+
+		r.Messages = append(r.Messages, m)
+	}
+
+	return reportTemplate.Execute(w, r)
+}
+
+// report is the data passed to the report template.
+type report struct {
+	Applications []reportApplication
+	Handlers     []reportHandler
+	Messages     []reportMessage
+	Diagram      template.HTML
+	HandlerTypes []configkit.HandlerType
+	MessageKinds []message.Kind
+}
+
+type reportApplication struct {
+	Name, Key, TypeName string
+	Handlers            []reportRef
+}
+
+type reportHandler struct {
+	ID, Type, Name, Key, TypeName, Application string
+	Disabled                                   bool
+	Messages                                   []reportRoute
+	Search                                     string
+}
+
+type reportMessage struct {
+	ID, Kind, Name, Label string
+	Producers, Consumers  []reportRef
+	Search                string
+}
+
+// reportRoute describes a handler's use of a message.
+type reportRoute struct {
+	Verb, Kind string
+	Ref        reportRef
+}
+
+// reportRef is a link to a handler or message within the report.
+type reportRef struct {
+	ID, Label string
+}
+
+// handlerKey uniquely identifies a handler across several applications.
+type handlerKey struct {
+	Application configkit.Identity
+	Handler     configkit.Identity
+}
+
+func handlerRef(ids map[handlerKey]string, x configkit.ApplicationHandler) reportRef {
+	id := x.Handler.Identity()
+
+	return reportRef{
+		ids[handlerKey{x.Application, id}],
+		id.Name + " (" + x.Application.Name + ")",
+	}
+}
+
+// searchText returns the text that the report's search box matches against.
+func searchText(values ...string) string {
+	return strings.ToLower(strings.Join(values, " "))
+}
+
+//go:embed report.html.tmpl
+var reportTemplateText string
+
+var reportTemplate = template.Must(
+	template.New("report").Parse(reportTemplateText),
+)
@@ -0,0 +1,118 @@
+package explorer_test
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/dogmatiq/configkit"
+	. "github.com/dogmatiq/configkit/explorer"
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("func Write()", func() {
+	var apps []configkit.Application
+
+	BeforeEach(func() {
+		apps = []configkit.Application{
+			configkit.FromApplication(&ApplicationStub{
+				ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+					c.Identity("<app-1>", "59a82a24-a181-41e8-9b93-17a6ce86956e")
+					c.Routes(
+						dogma.ViaAggregate(&AggregateMessageHandlerStub{
+							ConfigureFunc: func(c dogma.AggregateConfigurer) {
+								c.Identity("<aggregate>", "14769f7f-87fe-48dd-916e-5bcab6ba6aca")
+								c.Routes(
+									dogma.HandlesCommand[*CommandStub[TypeA]](),
+									dogma.RecordsEvent[*EventStub[TypeA]](),
+								)
+							},
+						}),
+					)
+				},
+			}),
+			configkit.FromApplication(&ApplicationStub{
+				ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+					c.Identity("<app-2>", "e28f056e-e5a0-4ee7-aaf1-1d1fe02fb6e3")
+					c.Routes(
+						dogma.ViaProjection(&ProjectionMessageHandlerStub{
+							ConfigureFunc: func(c dogma.ProjectionConfigurer) {
+								c.Identity("<projection>", "70fdf7fa-4b24-448d-bd29-7ecc71d18c56")
+								c.Routes(
+									dogma.HandlesEvent[*EventStub[TypeA]](),
+								)
+								c.Disable()
+							},
+						}),
+					)
+				},
+			}),
+		}
+	})
+
+	It("writes an HTML report that describes the applications", func() {
+		var buf bytes.Buffer
+		err := Write(&buf, apps, configkit.WithPackageNames(), configkit.WithShortTypeArgs())
+		Expect(err).ShouldNot(HaveOccurred())
+
+		html := buf.String()
+
+		Expect(html).To(HavePrefix("<!DOCTYPE html>"))
+		Expect(html).To(ContainSubstring(`<svg xmlns="http://www.w3.org/2000/svg" class="flowgraph"`))
+		Expect(html).To(ContainSubstring(`<section class="item handler" id="h1" data-type="aggregate"`))
+		Expect(html).To(ContainSubstring(`<section class="item handler disabled" id="h2" data-type="projection"`))
+		Expect(html).To(ContainSubstring(`<section class="item message" id="m2" data-kind="event"`))
+		Expect(html).To(ContainSubstring(`<code title="*github.com/dogmatiq/enginekit/enginetest/stubs.EventStub[github.com/dogmatiq/enginekit/enginetest/stubs.TypeA]">*stubs.EventStub[TypeA]</code>`))
+		Expect(html).To(ContainSubstring(`<a href="#h2">&lt;projection&gt; (&lt;app-2&gt;)</a>`))
+	})
+
+	It("does not reference any external resources", func() {
+		var buf bytes.Buffer
+		err := Write(&buf, apps)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		html := strings.ReplaceAll(buf.String(), `xmlns="http://www.w3.org/2000/svg"`, "")
+
+		Expect(html).NotTo(ContainSubstring("http://"))
+		Expect(html).NotTo(ContainSubstring("https://"))
+		Expect(html).NotTo(ContainSubstring(" src="))
+	})
+
+	It("produces the same output each time it is called", func() {
+		var a, b bytes.Buffer
+
+		err := Write(&a, apps)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		err = Write(&b, []configkit.Application{apps[1], apps[0]})
+		Expect(err).ShouldNot(HaveOccurred())
+
+		Expect(a.String()).To(Equal(b.String()))
+	})
+
+	It("supports configurations that have been unmarshaled from protocol buffers", func() {
+		var unmarshaled []configkit.Application
+
+		for _, app := range apps {
+			pb, err := configkit.ToProto(app)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			cfg, err := configkit.FromProto(pb)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			unmarshaled = append(unmarshaled, cfg)
+		}
+
+		var a, b bytes.Buffer
+
+		err := Write(&a, apps)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		err = Write(&b, unmarshaled)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		Expect(a.String()).To(Equal(b.String()))
+	})
+})
@@ -1,4 +1,4 @@
-package goreflect_test
+package explorer_test
 
 import (
 	"reflect"
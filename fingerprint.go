@@ -0,0 +1,131 @@
+package configkit
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"hash"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/dogmatiq/enginekit/message"
+)
+
+// Fingerprint returns a hash of the configuration of app.
+//
+// Two applications have the same fingerprint if and only if they are equal
+// according to [IsApplicationEqual], regardless of how their configurations
+// were obtained. For example, the fingerprint of an application is unchanged
+// by a round-trip through [ToProto] and [FromProto], provided that its
+// metadata is passed to [FromProto] using the [WithMetadata] option.
+//
+// The fingerprint is a SHA-256 hash of a canonical encoding of the
+// application's identity, type name, messages, metadata and handlers.
+//
+// The fingerprint is not part of the [ToProto] representation, as the
+// configpb schema has no field for it. Instead, api.Server sends it alongside
+// the protocol buffers messages that it serves.
+func Fingerprint(app Application) []byte {
+	h := sha256.New()
+
+	writeFingerprintString(h, "configkit.Application/1")
+	writeFingerprintIdentity(h, app.Identity())
+	writeFingerprintString(h, app.TypeName())
+	writeFingerprintMessages(h, app.MessageNames())
+	writeFingerprintMetadata(h, app.Metadata())
+
+	handlers := slices.Collect(maps.Values(app.Handlers()))
+	slices.SortFunc(handlers, func(a, b Handler) int {
+		x, y := a.Identity(), b.Identity()
+		if c := strings.Compare(x.Name, y.Name); c != 0 {
+			return c
+		}
+		return strings.Compare(x.Key, y.Key)
+	})
+
+	writeFingerprintUint(h, len(handlers))
+	for _, x := range handlers {
+		h.Write(HandlerFingerprint(x))
+	}
+
+	return h.Sum(nil)
+}
+
+// HandlerFingerprint returns a hash of the configuration of h.
+//
+// Two handlers have the same fingerprint if and only if they are equal
+// according to [IsHandlerEqual].
+func HandlerFingerprint(h Handler) []byte {
+	w := sha256.New()
+
+	writeFingerprintString(w, "configkit.Handler/1")
+	writeFingerprintIdentity(w, h.Identity())
+	writeFingerprintString(w, h.TypeName())
+	writeFingerprintString(w, string(h.HandlerType()))
+	writeFingerprintBool(w, h.IsDisabled())
+	writeFingerprintMessages(w, h.MessageNames())
+	writeFingerprintMetadata(w, h.Metadata())
+
+	return w.Sum(nil)
+}
+
+func writeFingerprintIdentity(w hash.Hash, id Identity) {
+	writeFingerprintString(w, id.Name)
+	writeFingerprintString(w, id.Key)
+}
+
+func writeFingerprintMessages(w hash.Hash, messages EntityMessages[message.Name]) {
+	names := make([]message.Name, 0, len(messages))
+	for n := range messages {
+		names = append(names, n)
+	}
+	slices.Sort(names)
+
+	writeFingerprintUint(w, len(names))
+
+	for _, n := range names {
+		em := messages[n]
+		writeFingerprintString(w, string(n))
+		writeFingerprintString(w, em.Kind.String())
+		writeFingerprintBool(w, em.IsProduced)
+		writeFingerprintBool(w, em.IsConsumed)
+	}
+}
+
+// writeFingerprintMetadata writes m to w.
+//
+// Nothing is written if m is empty, such that the fingerprints of
+// configurations without metadata are the same as they were before metadata
+// was introduced.
+func writeFingerprintMetadata(w hash.Hash, m Metadata) {
+	if len(m) == 0 {
+		return
+	}
+
+	writeFingerprintString(w, "metadata")
+	writeFingerprintUint(w, len(m))
+
+	for _, k := range slices.Sorted(maps.Keys(m)) {
+		writeFingerprintString(w, k)
+		writeFingerprintString(w, m[k])
+	}
+}
+
+// writeFingerprintString writes a length-prefixed string to w, such that the
+// encoding of a sequence of strings is unambiguous.
+func writeFingerprintString(w hash.Hash, s string) {
+	writeFingerprintUint(w, len(s))
+	w.Write([]byte(s))
+}
+
+func writeFingerprintUint(w hash.Hash, n int) {
+	w.Write(binary.BigEndian.AppendUint64(nil, uint64(n)))
+}
+
+func writeFingerprintBool(w hash.Hash, b bool) {
+	if b {
+		w.Write([]byte{1})
+	} else {
+		w.Write([]byte{0})
+	}
+}
@@ -0,0 +1,168 @@
+package configkit_test
+
+import (
+	. "github.com/dogmatiq/configkit"
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("func Fingerprint()", func() {
+	// fingerprintApplication is a mock of [dogma.Application] that has a
+	// different Go type name to [ApplicationStub].
+	type fingerprintApplication struct {
+		ApplicationStub
+	}
+
+	// newApp returns an application stub configured according to the given
+	// arguments.
+	newApp := func(name string, disable bool, messages ...dogma.AggregateRoute) *ApplicationStub {
+		return &ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity(name, "59a82a24-a181-41e8-9b93-17a6ce86956e")
+				c.Routes(
+					dogma.ViaAggregate(&AggregateMessageHandlerStub{
+						ConfigureFunc: func(c dogma.AggregateConfigurer) {
+							c.Identity("<aggregate>", "14769f7f-87fe-48dd-916e-5bcab6ba6aca")
+							c.Routes(
+								dogma.HandlesCommand[*CommandStub[TypeA]](),
+								dogma.RecordsEvent[*EventStub[TypeA]](),
+							)
+							c.Routes(messages...)
+						},
+					}),
+					dogma.ViaProjection(&ProjectionMessageHandlerStub{
+						ConfigureFunc: func(c dogma.ProjectionConfigurer) {
+							c.Identity("<projection>", "70fdf7fa-8d5c-4a49-a5e7-2f9a6c6b1b39")
+							c.Routes(
+								dogma.HandlesEvent[*EventStub[TypeA]](),
+							)
+							if disable {
+								c.Disable()
+							}
+						},
+					}),
+				)
+			},
+		}
+	}
+
+	It("returns a SHA-256 hash", func() {
+		Expect(Fingerprint(FromApplication(newApp("<app>", false)))).To(HaveLen(32))
+	})
+
+	It("returns the same fingerprint for equal configurations", func() {
+		a := FromApplication(newApp("<app>", false))
+		b := FromApplication(newApp("<app>", false))
+
+		Expect(IsApplicationEqual(a, b)).To(BeTrue())
+		Expect(Fingerprint(a)).To(Equal(Fingerprint(b)))
+	})
+
+	It("is unchanged by a round-trip through the protocol buffers representation", func() {
+		cfg := FromApplication(newApp("<app>", false))
+
+		apb, err := ToProto(cfg)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		out, err := FromProto(apb)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		Expect(Fingerprint(out)).To(Equal(Fingerprint(cfg)))
+	})
+
+	It("returns a different fingerprint if the metadata is not equal", func() {
+		a := FromApplication(newApp("<app>", false))
+		b := FromApplication(newApp("<app>", false), WithMetadata(MetadataSet{
+			"14769f7f-87fe-48dd-916e-5bcab6ba6aca": {OwnerMetadataKey: "payments"},
+		}))
+
+		Expect(IsApplicationEqual(a, b)).To(BeFalse())
+		Expect(Fingerprint(a)).NotTo(Equal(Fingerprint(b)))
+	})
+
+	It("is unchanged by a round-trip through the protocol buffers representation if the metadata is attached separately", func() {
+		cfg := FromApplication(newApp("<app>", false), WithMetadata(MetadataSet{
+			"59a82a24-a181-41e8-9b93-17a6ce86956e": {OwnerMetadataKey: "payments"},
+			"14769f7f-87fe-48dd-916e-5bcab6ba6aca": {BoundedContextMetadataKey: "orders"},
+		}))
+
+		apb, err := ToProto(cfg)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		out, err := FromProto(apb, WithMetadata(CollectMetadata(cfg)))
+		Expect(err).ShouldNot(HaveOccurred())
+
+		Expect(Fingerprint(out)).To(Equal(Fingerprint(cfg)))
+	})
+
+	DescribeTable(
+		"returns a different fingerprint if the configurations are not equal",
+		func(app dogma.Application) {
+			a := FromApplication(newApp("<app>", false))
+			b := FromApplication(app)
+
+			Expect(IsApplicationEqual(a, b)).To(BeFalse())
+			Expect(Fingerprint(a)).NotTo(Equal(Fingerprint(b)))
+		},
+		Entry(
+			"identity",
+			newApp("<other>", false),
+		),
+		Entry(
+			"type name",
+			&fingerprintApplication{*newApp("<app>", false)},
+		),
+		Entry(
+			"disabled handler",
+			newApp("<app>", true),
+		),
+		Entry(
+			"handler messages",
+			newApp("<app>", false, dogma.RecordsEvent[*EventStub[TypeB]]()),
+		),
+	)
+})
+
+var _ = Describe("func HandlerFingerprint()", func() {
+	It("returns the same fingerprint for handlers that are equal", func() {
+		handler := &IntegrationMessageHandlerStub{
+			ConfigureFunc: func(c dogma.IntegrationConfigurer) {
+				c.Identity("<integration>", "e28f056e-e5a0-4ee7-aaf1-1d1fe02fb6e3")
+				c.Routes(
+					dogma.HandlesCommand[*CommandStub[TypeA]](),
+				)
+			},
+		}
+
+		a := FromIntegration(handler)
+		b := FromIntegration(handler)
+
+		Expect(IsHandlerEqual(a, b)).To(BeTrue())
+		Expect(HandlerFingerprint(a)).To(Equal(HandlerFingerprint(b)))
+	})
+
+	It("returns different fingerprints for handlers with different identities", func() {
+		a := FromIntegration(&IntegrationMessageHandlerStub{
+			ConfigureFunc: func(c dogma.IntegrationConfigurer) {
+				c.Identity("<integration>", "e28f056e-e5a0-4ee7-aaf1-1d1fe02fb6e3")
+				c.Routes(
+					dogma.HandlesCommand[*CommandStub[TypeA]](),
+				)
+			},
+		})
+
+		b := FromIntegration(&IntegrationMessageHandlerStub{
+			ConfigureFunc: func(c dogma.IntegrationConfigurer) {
+				c.Identity("<other>", "e28f056e-e5a0-4ee7-aaf1-1d1fe02fb6e3")
+				c.Routes(
+					dogma.HandlesCommand[*CommandStub[TypeA]](),
+				)
+			},
+		})
+
+		Expect(HandlerFingerprint(a)).NotTo(Equal(HandlerFingerprint(b)))
+	})
+})
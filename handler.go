@@ -41,6 +41,7 @@ type RichHandler interface {
 //  1. have the same identity
 //  2. produce and consume the same message types
 //  3. are implemented using the same Go types
+//  4. have the same metadata
 //
 // Point 3. refers to the type used to implement the dogma.Aggregate,
 // dogma.Process, dogma.Integration or dogma.Projection interface (not the type
@@ -54,7 +55,8 @@ func IsHandlerEqual(a, b Handler) bool {
 		a.TypeName() == b.TypeName() &&
 		a.HandlerType() == b.HandlerType() &&
 		a.IsDisabled() == b.IsDisabled() &&
-		a.MessageNames().IsEqual(b.MessageNames())
+		a.MessageNames().IsEqual(b.MessageNames()) &&
+		a.Metadata().IsEqual(b.Metadata())
 }
 
 func configureRoutes[T dogma.MessageRoute](
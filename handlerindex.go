@@ -0,0 +1,167 @@
+package configkit
+
+import (
+	"iter"
+	"maps"
+	"slices"
+
+	"github.com/dogmatiq/enginekit/message"
+)
+
+// HandlerIndex is a read-only collection of handlers that is indexed by
+// identity name, identity key and the names of the messages that each handler
+// produces and consumes.
+//
+// Unlike the equivalent methods of [HandlerSet] and [RichHandlerSet], each
+// lookup is O(1). Use [IndexHandlers] or [IndexRichHandlers] to obtain the
+// index of an application's handlers.
+//
+// H is the type of the handlers in the index, either [Handler] or
+// [RichHandler].
+type HandlerIndex[H Handler] struct {
+	handlers  map[Identity]H
+	names     map[string]H
+	keys      map[string]H
+	consumers map[message.Name][]H
+	producers map[message.Name][]H
+}
+
+// IndexHandlers returns an index of the handlers within app.
+//
+// The configurations produced by [FromApplication], [FromProto], [Bind] and
+// [ApplyOverlays] build their index once, when they are constructed. For any
+// other implementation of [Application], a new index is built on each call.
+func IndexHandlers(app Application) *HandlerIndex[Handler] {
+	if x, ok := app.(interface {
+		handlerIndex() *HandlerIndex[Handler]
+	}); ok {
+		return x.handlerIndex()
+	}
+
+	x := &HandlerIndex[Handler]{}
+	for _, h := range app.Handlers() {
+		x.add(h)
+	}
+	return x
+}
+
+// IndexRichHandlers returns an index of the handlers within app.
+//
+// The configurations produced by [FromApplication], [Bind] and
+// [ApplyOverlays] build their index once, when they are constructed. For any
+// other implementation of [RichApplication], a new index is built on each
+// call.
+func IndexRichHandlers(app RichApplication) *HandlerIndex[RichHandler] {
+	if x, ok := app.(interface {
+		richHandlerIndex() *HandlerIndex[RichHandler]
+	}); ok {
+		return x.richHandlerIndex()
+	}
+
+	x := &HandlerIndex[RichHandler]{}
+	for _, h := range app.RichHandlers() {
+		x.add(h)
+	}
+	return x
+}
+
+// add adds h to the index.
+//
+// It returns false if the index already contains a handler with the same name
+// or key as h.
+func (x *HandlerIndex[H]) add(h H) bool {
+	id := h.Identity()
+
+	if _, ok := x.names[id.Name]; ok {
+		return false
+	}
+
+	if _, ok := x.keys[id.Key]; ok {
+		return false
+	}
+
+	if x.handlers == nil {
+		x.handlers = map[Identity]H{}
+		x.names = map[string]H{}
+		x.keys = map[string]H{}
+		x.consumers = map[message.Name][]H{}
+		x.producers = map[message.Name][]H{}
+	}
+
+	x.handlers[id] = h
+	x.names[id.Name] = h
+	x.keys[id.Key] = h
+
+	for n, em := range h.MessageNames() {
+		if em.IsConsumed {
+			x.consumers[n] = append(x.consumers[n], h)
+		}
+
+		if em.IsProduced {
+			x.producers[n] = append(x.producers[n], h)
+		}
+	}
+
+	return true
+}
+
+// Len returns the number of handlers in the index.
+func (x *HandlerIndex[H]) Len() int {
+	return len(x.handlers)
+}
+
+// All returns an iterator that yields each handler in the index, in no
+// particular order.
+func (x *HandlerIndex[H]) All() iter.Seq[H] {
+	return maps.Values(x.handlers)
+}
+
+// ByIdentity returns the handler with the given identity.
+func (x *HandlerIndex[H]) ByIdentity(id Identity) (H, bool) {
+	h, ok := x.handlers[id]
+	return h, ok
+}
+
+// ByName returns the handler with the given name.
+func (x *HandlerIndex[H]) ByName(n string) (H, bool) {
+	h, ok := x.names[n]
+	return h, ok
+}
+
+// ByKey returns the handler with the given key.
+func (x *HandlerIndex[H]) ByKey(k string) (H, bool) {
+	h, ok := x.keys[k]
+	return h, ok
+}
+
+// ConsumersOf returns an iterator that yields the handlers that consume
+// messages with the given name, in the order they were added to the index.
+func (x *HandlerIndex[H]) ConsumersOf(n message.Name) iter.Seq[H] {
+	return slices.Values(x.consumers[n])
+}
+
+// ProducersOf returns an iterator that yields the handlers that produce
+// messages with the given name, in the order they were added to the index.
+func (x *HandlerIndex[H]) ProducersOf(n message.Name) iter.Seq[H] {
+	return slices.Values(x.producers[n])
+}
+
+// richHandlerIndex is an index of the handlers within a [RichApplication],
+// which indexes each handler as both a [RichHandler] and a [Handler].
+type richHandlerIndex struct {
+	rich  HandlerIndex[RichHandler]
+	plain HandlerIndex[Handler]
+}
+
+// add adds h to the index.
+//
+// It returns false if the index already contains a handler with the same name
+// or key as h.
+func (x *richHandlerIndex) add(h RichHandler) bool {
+	if !x.rich.add(h) {
+		return false
+	}
+
+	x.plain.add(h)
+	return true
+}
@@ -0,0 +1,233 @@
+package configkit
+
+import (
+	"slices"
+
+	//revive:disable:dot-imports
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	"github.com/dogmatiq/enginekit/message"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("type HandlerIndex", func() {
+	var (
+		index              *HandlerIndex[Handler]
+		aggregate, process *unmarshaledHandler
+	)
+
+	BeforeEach(func() {
+		index = &HandlerIndex[Handler]{}
+
+		aggregate = &unmarshaledHandler{
+			ident: MustNewIdentity("<aggregate>", "cb0a2a3b-64d6-4d2b-a5ba-6e2b3d4bd4a1"),
+			names: EntityMessages[message.Name]{
+				message.NameOf(CommandA1): {Kind: message.CommandKind, IsConsumed: true},
+				message.NameOf(EventA1):   {Kind: message.EventKind, IsProduced: true},
+			},
+			handlerType: AggregateHandlerType,
+		}
+
+		process = &unmarshaledHandler{
+			ident: MustNewIdentity("<process>", "9a5b1cb8-1c4f-4b5e-8ec2-5d7f6b1c9f0e"),
+			names: EntityMessages[message.Name]{
+				message.NameOf(EventA1):   {Kind: message.EventKind, IsConsumed: true},
+				message.NameOf(CommandA1): {Kind: message.CommandKind, IsProduced: true},
+			},
+			handlerType: ProcessHandlerType,
+		}
+	})
+
+	Describe("func add()", func() {
+		It("adds the handler to the index", func() {
+			Expect(index.add(aggregate)).To(BeTrue())
+			Expect(index.add(process)).To(BeTrue())
+			Expect(index.Len()).To(Equal(2))
+			Expect(slices.Collect(index.All())).To(ConsistOf(aggregate, process))
+		})
+
+		It("returns false if the index contains a handler with the same name", func() {
+			index.add(aggregate)
+
+			conflict := &unmarshaledHandler{
+				ident:       MustNewIdentity("<aggregate>", "0f2d6f8e-3f55-4a8a-a1f0-6e4b8c1d2e3f"),
+				handlerType: AggregateHandlerType,
+			}
+
+			Expect(index.add(conflict)).To(BeFalse())
+			Expect(index.Len()).To(Equal(1))
+
+			_, ok := index.ByKey(conflict.ident.Key)
+			Expect(ok).To(BeFalse())
+		})
+
+		It("returns false if the index contains a handler with the same key", func() {
+			index.add(aggregate)
+
+			conflict := &unmarshaledHandler{
+				ident:       MustNewIdentity("<other>", aggregate.ident.Key),
+				handlerType: AggregateHandlerType,
+			}
+
+			Expect(index.add(conflict)).To(BeFalse())
+			Expect(index.Len()).To(Equal(1))
+
+			_, ok := index.ByName("<other>")
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	When("the index contains handlers", func() {
+		BeforeEach(func() {
+			index.add(aggregate)
+			index.add(process)
+		})
+
+		Describe("func ByIdentity()", func() {
+			It("returns the handler with the given identity", func() {
+				h, ok := index.ByIdentity(aggregate.ident)
+				Expect(ok).To(BeTrue())
+				Expect(h).To(BeIdenticalTo(aggregate))
+			})
+
+			It("returns false if there is no such handler", func() {
+				_, ok := index.ByIdentity(MustNewIdentity("<aggregate>", process.ident.Key))
+				Expect(ok).To(BeFalse())
+			})
+		})
+
+		Describe("func ByName()", func() {
+			It("returns the handler with the given name", func() {
+				h, ok := index.ByName("<process>")
+				Expect(ok).To(BeTrue())
+				Expect(h).To(BeIdenticalTo(process))
+			})
+
+			It("returns false if there is no such handler", func() {
+				_, ok := index.ByName("<unknown>")
+				Expect(ok).To(BeFalse())
+			})
+		})
+
+		Describe("func ByKey()", func() {
+			It("returns the handler with the given key", func() {
+				h, ok := index.ByKey(aggregate.ident.Key)
+				Expect(ok).To(BeTrue())
+				Expect(h).To(BeIdenticalTo(aggregate))
+			})
+
+			It("returns false if there is no such handler", func() {
+				_, ok := index.ByKey("2b7c3f43-8c55-4b8e-9d0a-1e2f3a4b5c6d")
+				Expect(ok).To(BeFalse())
+			})
+		})
+
+		Describe("func ConsumersOf()", func() {
+			It("yields the handlers that consume the message", func() {
+				Expect(slices.Collect(index.ConsumersOf(message.NameOf(CommandA1)))).To(ConsistOf(aggregate))
+				Expect(slices.Collect(index.ConsumersOf(message.NameOf(EventA1)))).To(ConsistOf(process))
+			})
+
+			It("yields nothing if no handlers consume the message", func() {
+				Expect(slices.Collect(index.ConsumersOf(message.NameOf(EventB1)))).To(BeEmpty())
+			})
+		})
+
+		Describe("func ProducersOf()", func() {
+			It("yields the handlers that produce the message", func() {
+				Expect(slices.Collect(index.ProducersOf(message.NameOf(EventA1)))).To(ConsistOf(aggregate))
+				Expect(slices.Collect(index.ProducersOf(message.NameOf(CommandA1)))).To(ConsistOf(process))
+			})
+
+			It("yields nothing if no handlers produce the message", func() {
+				Expect(slices.Collect(index.ProducersOf(message.NameOf(EventB1)))).To(BeEmpty())
+			})
+		})
+	})
+
+	It("is empty if it is the zero value", func() {
+		Expect(index.Len()).To(Equal(0))
+		Expect(slices.Collect(index.All())).To(BeEmpty())
+
+		_, ok := index.ByName("<aggregate>")
+		Expect(ok).To(BeFalse())
+	})
+})
+
+var _ = Describe("func IndexHandlers()", func() {
+	var app RichApplication
+
+	BeforeEach(func() {
+		app = FromApplication(&ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app>", "3f0e5c8a-7a2b-4c1d-9e8f-0a1b2c3d4e5f")
+				c.Routes(
+					dogma.ViaIntegration(&IntegrationMessageHandlerStub{
+						ConfigureFunc: func(c dogma.IntegrationConfigurer) {
+							c.Identity("<integration>", "6c1b2a3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d")
+							c.Routes(
+								dogma.HandlesCommand[*CommandStub[TypeA]](),
+								dogma.RecordsEvent[*EventStub[TypeA]](),
+							)
+						},
+					}),
+				)
+			},
+		})
+	})
+
+	It("returns the index built when the configuration was constructed", func() {
+		Expect(IndexHandlers(app)).To(BeIdenticalTo(IndexHandlers(app)))
+
+		h, ok := IndexHandlers(app).ByName("<integration>")
+		Expect(ok).To(BeTrue())
+		Expect(h).To(BeIdenticalTo(app.Handlers()[h.Identity()]))
+	})
+
+	It("returns the index of an unmarshaled configuration", func() {
+		marshaled, err := ToProto(app)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		unmarshaled, err := FromProto(marshaled)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		Expect(IndexHandlers(unmarshaled)).To(BeIdenticalTo(IndexHandlers(unmarshaled)))
+		Expect(slices.Collect(IndexHandlers(unmarshaled).ConsumersOf(message.NameOf(CommandA1)))).To(HaveLen(1))
+	})
+
+	It("builds an index for other implementations", func() {
+		index := IndexHandlers(struct{ Application }{app})
+		Expect(index).NotTo(BeIdenticalTo(IndexHandlers(app)))
+		Expect(index.Len()).To(Equal(1))
+
+		_, ok := index.ByKey("6c1b2a3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d")
+		Expect(ok).To(BeTrue())
+	})
+})
+
+var _ = Describe("func IndexRichHandlers()", func() {
+	It("returns the index built when the configuration was constructed", func() {
+		app := FromApplication(&ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app>", "3f0e5c8a-7a2b-4c1d-9e8f-0a1b2c3d4e5f")
+				c.Routes(
+					dogma.ViaProjection(&ProjectionMessageHandlerStub{
+						ConfigureFunc: func(c dogma.ProjectionConfigurer) {
+							c.Identity("<projection>", "6c1b2a3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d")
+							c.Routes(
+								dogma.HandlesEvent[*EventStub[TypeA]](),
+							)
+						},
+					}),
+				)
+			},
+		})
+
+		Expect(IndexRichHandlers(app)).To(BeIdenticalTo(IndexRichHandlers(app)))
+
+		h, ok := IndexRichHandlers(app).ByName("<projection>")
+		Expect(ok).To(BeTrue())
+		Expect(h.HandlerType()).To(Equal(ProjectionHandlerType))
+	})
+})
@@ -0,0 +1,180 @@
+package configkit_test
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/dogmatiq/configkit"
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	"github.com/dogmatiq/enginekit/message"
+	"github.com/dogmatiq/enginekit/protobuf/uuidpb"
+)
+
+func BenchmarkFromApplication(b *testing.B) {
+	for _, n := range []int{100, 1000, 5000} {
+		b.Run(
+			fmt.Sprintf("%d handlers", n),
+			func(b *testing.B) {
+				app := largeApplication(n)
+
+				b.ReportAllocs()
+				b.ResetTimer()
+
+				for b.Loop() {
+					FromApplication(app)
+				}
+			},
+		)
+	}
+}
+
+func BenchmarkFromProto(b *testing.B) {
+	for _, n := range []int{100, 1000, 5000} {
+		b.Run(
+			fmt.Sprintf("%d handlers", n),
+			func(b *testing.B) {
+				marshaled, err := ToProto(FromApplication(largeApplication(n)))
+				if err != nil {
+					b.Fatal(err)
+				}
+
+				b.ReportAllocs()
+				b.ResetTimer()
+
+				for b.Loop() {
+					if _, err := FromProto(marshaled); err != nil {
+						b.Fatal(err)
+					}
+				}
+			},
+		)
+	}
+}
+
+func BenchmarkMessageNames(b *testing.B) {
+	for _, n := range []int{100, 1000, 5000} {
+		b.Run(
+			fmt.Sprintf("%d handlers", n),
+			func(b *testing.B) {
+				rich := FromApplication(largeApplication(n))
+
+				marshaled, err := ToProto(rich)
+				if err != nil {
+					b.Fatal(err)
+				}
+
+				unmarshaled, err := FromProto(marshaled)
+				if err != nil {
+					b.Fatal(err)
+				}
+
+				for name, app := range map[string]Application{
+					"FromApplication": rich,
+					"FromProto":       unmarshaled,
+				} {
+					b.Run(
+						name,
+						func(b *testing.B) {
+							b.ReportAllocs()
+
+							for b.Loop() {
+								app.MessageNames()
+							}
+						},
+					)
+				}
+			},
+		)
+	}
+}
+
+func BenchmarkHandlerSet_ConsumersOf(b *testing.B) {
+	for _, n := range []int{100, 1000, 5000} {
+		b.Run(
+			fmt.Sprintf("%d handlers", n),
+			func(b *testing.B) {
+				handlers := FromApplication(largeApplication(n)).Handlers()
+				name := message.NameOf(EventA1)
+
+				b.ReportAllocs()
+				b.ResetTimer()
+
+				for b.Loop() {
+					handlers.ConsumersOf(name)
+				}
+			},
+		)
+	}
+}
+
+func BenchmarkHandlerIndex_ConsumersOf(b *testing.B) {
+	for _, n := range []int{100, 1000, 5000} {
+		b.Run(
+			fmt.Sprintf("%d handlers", n),
+			func(b *testing.B) {
+				index := IndexHandlers(FromApplication(largeApplication(n)))
+				name := message.NameOf(EventA1)
+
+				b.ReportAllocs()
+				b.ResetTimer()
+
+				for b.Loop() {
+					for range index.ConsumersOf(name) {
+					}
+				}
+			},
+		)
+	}
+}
+
+// largeApplication returns an application containing n handlers.
+//
+// The handlers share the same message types, so that each handler that is
+// registered is checked for conflicting routes against many existing handlers.
+func largeApplication(n int) dogma.Application {
+	routes := []dogma.HandlerRoute{
+		dogma.ViaAggregate(&AggregateMessageHandlerStub{
+			ConfigureFunc: func(c dogma.AggregateConfigurer) {
+				c.Identity("<aggregate>", uuidpb.Generate().AsString())
+				c.Routes(
+					dogma.HandlesCommand[*CommandStub[TypeA]](),
+					dogma.RecordsEvent[*EventStub[TypeA]](),
+				)
+			},
+		}),
+	}
+
+	for i := range n - 1 {
+		name := fmt.Sprintf("<handler-%d>", i)
+		key := uuidpb.Generate().AsString()
+
+		if i%2 == 0 {
+			routes = append(routes, dogma.ViaProcess(&ProcessMessageHandlerStub{
+				ConfigureFunc: func(c dogma.ProcessConfigurer) {
+					c.Identity(name, key)
+					c.Routes(
+						dogma.HandlesEvent[*EventStub[TypeA]](),
+						dogma.ExecutesCommand[*CommandStub[TypeA]](),
+					)
+				},
+			}))
+		} else {
+			routes = append(routes, dogma.ViaProjection(&ProjectionMessageHandlerStub{
+				ConfigureFunc: func(c dogma.ProjectionConfigurer) {
+					c.Identity(name, key)
+					c.Routes(
+						dogma.HandlesEvent[*EventStub[TypeA]](),
+					)
+				},
+			}))
+		}
+	}
+
+	return &ApplicationStub{
+		ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+			c.Identity("<app>", appKey)
+			c.Routes(routes...)
+		},
+	}
+}
@@ -0,0 +1,240 @@
+package configkit
+
+import (
+	"fmt"
+	"iter"
+	"path"
+	"slices"
+
+	"github.com/dogmatiq/enginekit/message"
+)
+
+// HandlerFilter is a predicate that selects handlers within a query.
+//
+// See [HandlerSet.Query] and [RichHandlerSet.Query].
+type HandlerFilter func(Handler) bool
+
+// FilterByType returns a filter that selects handlers of any of the given
+// types.
+func FilterByType(types ...HandlerType) HandlerFilter {
+	for _, t := range types {
+		t.MustValidate()
+	}
+
+	return filterByType(types...)
+}
+
+// filterByType returns a filter that selects handlers of any of the given
+// types. Unlike [FilterByType], it does not panic if a type is invalid.
+func filterByType(types ...HandlerType) HandlerFilter {
+	return func(h Handler) bool {
+		return slices.Contains(types, h.HandlerType())
+	}
+}
+
+// FilterEnabled returns a filter that selects handlers that are enabled.
+func FilterEnabled() HandlerFilter {
+	return func(h Handler) bool {
+		return !h.IsDisabled()
+	}
+}
+
+// FilterDisabled returns a filter that selects handlers that are disabled.
+func FilterDisabled() HandlerFilter {
+	return func(h Handler) bool {
+		return h.IsDisabled()
+	}
+}
+
+// FilterConsumes returns a filter that selects handlers that consume at least
+// one message of any of the given kinds.
+//
+// If no kinds are given, it selects handlers that consume any message.
+func FilterConsumes(kinds ...message.Kind) HandlerFilter {
+	return func(h Handler) bool {
+		for range h.MessageNames().Consumed(kinds...) {
+			return true
+		}
+		return false
+	}
+}
+
+// FilterProduces returns a filter that selects handlers that produce at least
+// one message of any of the given kinds.
+//
+// If no kinds are given, it selects handlers that produce any message.
+func FilterProduces(kinds ...message.Kind) HandlerFilter {
+	return func(h Handler) bool {
+		for range h.MessageNames().Produced(kinds...) {
+			return true
+		}
+		return false
+	}
+}
+
+// FilterByMetadata returns a filter that selects handlers with metadata that
+// has the given key.
+//
+// If any values are given, it only selects handlers where the value associated
+// with the key is one of those values.
+func FilterByMetadata(key string, values ...string) HandlerFilter {
+	return func(h Handler) bool {
+		v, ok := h.Metadata()[key]
+		if !ok {
+			return false
+		}
+		return len(values) == 0 || slices.Contains(values, v)
+	}
+}
+
+// FilterByName returns a filter that selects handlers with names that match the
+// given pattern.
+//
+// The pattern syntax is that of [path.Match]. It returns an error if the
+// pattern is malformed.
+func FilterByName(pattern string) (HandlerFilter, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid handler name pattern %q: %w", pattern, err)
+	}
+
+	return func(h Handler) bool {
+		ok, _ := path.Match(pattern, h.Identity().Name)
+		return ok
+	}, nil
+}
+
+// MustFilterByName returns a filter that selects handlers with names that
+// match the given pattern.
+//
+// It panics if the pattern is malformed.
+func MustFilterByName(pattern string) HandlerFilter {
+	fn, err := FilterByName(pattern)
+	if err != nil {
+		panic(err)
+	}
+
+	return fn
+}
+
+// FilterAny returns a filter that selects handlers that match any of the given
+// filters.
+func FilterAny(filters ...HandlerFilter) HandlerFilter {
+	return func(h Handler) bool {
+		for _, fn := range filters {
+			if fn(h) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// FilterNot returns a filter that selects handlers that do not match the given
+// filter.
+func FilterNot(filter HandlerFilter) HandlerFilter {
+	return func(h Handler) bool {
+		return !filter(h)
+	}
+}
+
+// queryHandlers returns an iterator that yields the handlers in s that match
+// all of the given filters.
+func queryHandlers[H Handler](s map[Identity]H, filters ...HandlerFilter) iter.Seq[H] {
+	return func(yield func(H) bool) {
+		for _, h := range s {
+			if matchesAll(h, filters) && !yield(h) {
+				return
+			}
+		}
+	}
+}
+
+// matchesAll returns true if h matches all of the given filters.
+func matchesAll(h Handler, filters []HandlerFilter) bool {
+	for _, fn := range filters {
+		if !fn(h) {
+			return false
+		}
+	}
+	return true
+}
+
+// The functions below are generic implementations of the methods that are
+// common to both [HandlerSet] and [RichHandlerSet].
+
+func addHandler[H Handler](s map[Identity]H, h H) bool {
+	i := h.Identity()
+	for x := range s {
+		if i.ConflictsWith(x) {
+			return false
+		}
+	}
+
+	s[i] = h
+	return true
+}
+
+func hasHandler[H Handler](s map[Identity]H, h H) bool {
+	x, ok := s[h.Identity()]
+	return ok && any(x) == any(h)
+}
+
+func findHandler[H Handler](s map[Identity]H, filters ...HandlerFilter) (H, bool) {
+	for h := range queryHandlers(s, filters...) {
+		return h, true
+	}
+
+	var zero H
+	return zero, false
+}
+
+func filterHandlers[S ~map[Identity]H, H Handler](s S, filters ...HandlerFilter) S {
+	subset := S{}
+
+	for h := range queryHandlers(s, filters...) {
+		subset[h.Identity()] = h
+	}
+
+	return subset
+}
+
+func isHandlerSetEqual[H Handler](s, o map[Identity]H) bool {
+	if len(s) != len(o) {
+		return false
+	}
+
+	for i, h := range s {
+		x, ok := o[i]
+		if !ok || !IsHandlerEqual(x, h) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// handlersOfType returns the handlers in s of type t, as values of type T.
+func handlersOfType[T any, H Handler](s map[Identity]H, t HandlerType) []T {
+	var r []T
+
+	for h := range queryHandlers(s, FilterByType(t)) {
+		if x, ok := any(h).(T); ok {
+			r = append(r, x)
+		}
+	}
+
+	return r
+}
+
+// rangeHandlersOfType invokes fn for each handler in s of type t.
+func rangeHandlersOfType[T any, H Handler](s map[Identity]H, t HandlerType, fn func(T) bool) bool {
+	for h := range queryHandlers(s, FilterByType(t)) {
+		if x, ok := any(h).(T); ok {
+			if !fn(x) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
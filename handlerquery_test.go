@@ -0,0 +1,180 @@
+package configkit_test
+
+import (
+	"slices"
+
+	. "github.com/dogmatiq/configkit"
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	"github.com/dogmatiq/enginekit/message"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("handler queries", func() {
+	aggregate := FromAggregate(&AggregateMessageHandlerStub{
+		ConfigureFunc: func(c dogma.AggregateConfigurer) {
+			c.Identity("<aggregate>", aggregateKey)
+			c.Routes(
+				dogma.HandlesCommand[*CommandStub[TypeA]](),
+				dogma.RecordsEvent[*EventStub[TypeA]](),
+			)
+		},
+	}, WithMetadata(MetadataSet{
+		aggregateKey: {OwnerMetadataKey: "payments"},
+	}))
+
+	process := FromProcess(&ProcessMessageHandlerStub{
+		ConfigureFunc: func(c dogma.ProcessConfigurer) {
+			c.Identity("<process>", processKey)
+			c.Routes(
+				dogma.HandlesEvent[*EventStub[TypeA]](),
+				dogma.ExecutesCommand[*CommandStub[TypeB]](),
+				dogma.SchedulesTimeout[*TimeoutStub[TypeA]](),
+			)
+		},
+	}, WithMetadata(MetadataSet{
+		processKey: {OwnerMetadataKey: "billing"},
+	}))
+
+	projection := FromProjection(&ProjectionMessageHandlerStub{
+		ConfigureFunc: func(c dogma.ProjectionConfigurer) {
+			c.Identity("<projection>", projectionKey)
+			c.Routes(
+				dogma.HandlesEvent[*EventStub[TypeA]](),
+			)
+			c.Disable()
+		},
+	})
+
+	Describe("func HandlerSet.Query()", func() {
+		set := NewHandlerSet(aggregate, process, projection)
+
+		DescribeTable(
+			"it yields the handlers that match all of the filters",
+			func(expect []Handler, filters ...HandlerFilter) {
+				Expect(slices.Collect(set.Query(filters...))).To(ConsistOf(expect))
+			},
+			Entry(
+				"no filters",
+				[]Handler{aggregate, process, projection},
+			),
+			Entry(
+				"by type",
+				[]Handler{aggregate, projection},
+				FilterByType(AggregateHandlerType, ProjectionHandlerType),
+			),
+			Entry(
+				"enabled",
+				[]Handler{aggregate, process},
+				FilterEnabled(),
+			),
+			Entry(
+				"disabled",
+				[]Handler{projection},
+				FilterDisabled(),
+			),
+			Entry(
+				"consumes any message",
+				[]Handler{aggregate, process, projection},
+				FilterConsumes(),
+			),
+			Entry(
+				"consumes a specific kind",
+				[]Handler{process},
+				FilterConsumes(message.TimeoutKind),
+			),
+			Entry(
+				"produces a specific kind",
+				[]Handler{process},
+				FilterProduces(message.CommandKind),
+			),
+			Entry(
+				"produces any message",
+				[]Handler{aggregate, process},
+				FilterProduces(),
+			),
+			Entry(
+				"name pattern",
+				[]Handler{process, projection},
+				MustFilterByName("<pro*>"),
+			),
+			Entry(
+				"metadata key",
+				[]Handler{aggregate, process},
+				FilterByMetadata(OwnerMetadataKey),
+			),
+			Entry(
+				"metadata value",
+				[]Handler{aggregate},
+				FilterByMetadata(OwnerMetadataKey, "payments", "shipping"),
+			),
+			Entry(
+				"any of several filters",
+				[]Handler{aggregate, projection},
+				FilterAny(
+					FilterByType(AggregateHandlerType),
+					FilterDisabled(),
+				),
+			),
+			Entry(
+				"negated filter",
+				[]Handler{aggregate},
+				FilterNot(MustFilterByName("<pro*>")),
+			),
+			Entry(
+				"multiple filters",
+				[]Handler{projection},
+				FilterConsumes(message.EventKind),
+				FilterDisabled(),
+			),
+		)
+
+		It("stops iterating when the yield function returns false", func() {
+			count := 0
+			for range set.Query() {
+				count++
+				break
+			}
+
+			Expect(count).To(Equal(1))
+		})
+	})
+
+	Describe("func RichHandlerSet.Query()", func() {
+		It("yields rich handlers that match all of the filters", func() {
+			set := NewRichHandlerSet(aggregate, process, projection)
+
+			var matches []RichHandler
+			for h := range set.Query(FilterEnabled(), FilterProduces(message.EventKind)) {
+				matches = append(matches, h)
+			}
+
+			Expect(matches).To(ConsistOf(aggregate))
+		})
+	})
+
+	Describe("func FilterByType()", func() {
+		It("panics if the handler type is invalid", func() {
+			Expect(func() {
+				FilterByType("<invalid>")
+			}).To(Panic())
+		})
+	})
+
+	Describe("func FilterByName()", func() {
+		It("returns an error if the pattern is malformed", func() {
+			_, err := FilterByName("[")
+			Expect(err).To(MatchError(`invalid handler name pattern "[": syntax error in pattern`))
+		})
+	})
+
+	Describe("func MustFilterByName()", func() {
+		It("panics if the pattern is malformed", func() {
+			Expect(func() {
+				MustFilterByName("[")
+			}).To(Panic())
+		})
+	})
+})
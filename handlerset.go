@@ -1,12 +1,20 @@
 package configkit
 
 import (
+	"cmp"
 	"context"
+	"iter"
+	"maps"
+	"slices"
+	"strings"
 
 	"github.com/dogmatiq/enginekit/message"
 )
 
 // HandlerSet is a collection of handlers.
+//
+// Lookups by name, key or message scan every handler in the set. Use
+// [IndexHandlers] to look up an application's handlers in constant time.
 type HandlerSet map[Identity]Handler
 
 // NewHandlerSet returns a HandlerSet containing the given handlers.
@@ -29,21 +37,12 @@ func NewHandlerSet(handlers ...Handler) HandlerSet {
 // It returns true if the handler was added, or false if the set already
 // contained a handler with the same name or key as h.
 func (s HandlerSet) Add(h Handler) bool {
-	i := h.Identity()
-	for x := range s {
-		if i.ConflictsWith(x) {
-			return false
-		}
-	}
-
-	s[i] = h
-	return true
+	return addHandler(s, h)
 }
 
 // Has returns true if s contains h.
 func (s HandlerSet) Has(h Handler) bool {
-	x, ok := s[h.Identity()]
-	return ok && x == h
+	return hasHandler(s, h)
 }
 
 // ByIdentity returns the handler with the given identity.
@@ -68,9 +67,7 @@ func (s HandlerSet) ByKey(k string) (Handler, bool) {
 
 // ByType returns the subset of handlers of the given type.
 func (s HandlerSet) ByType(t HandlerType) HandlerSet {
-	return s.Filter(func(h Handler) bool {
-		return h.HandlerType() == t
-	})
+	return filterHandlers(s, filterByType(t))
 }
 
 // ConsumersOf returns the subset of handlers that consume messages with the
@@ -102,44 +99,45 @@ func (s HandlerSet) MessageNames() EntityMessages[message.Name] {
 
 // IsEqual returns true if o contains the same handlers as s.
 func (s HandlerSet) IsEqual(o HandlerSet) bool {
-	if len(s) != len(o) {
-		return false
-	}
+	return isHandlerSetEqual(s, o)
+}
 
-	for i, h := range s {
-		x, ok := o[i]
-		if !ok || !IsHandlerEqual(x, h) {
-			return false
-		}
-	}
+// Clone returns a copy of s.
+//
+// The handlers themselves are not copied, as handler configurations are
+// immutable.
+func (s HandlerSet) Clone() HandlerSet {
+	return maps.Clone(s)
+}
+
+// Sorted returns an iterator that yields the handlers in the set in a
+// deterministic order.
+//
+// Handlers are ordered by their handler type, in the order that the types
+// appear in [HandlerTypes], then by name.
+func (s HandlerSet) Sorted() iter.Seq[Handler] {
+	return sortedHandlers(s)
+}
 
-	return true
+// Query returns an iterator that yields the handlers in the set that match all
+// of the given filters.
+//
+// If no filters are given, it yields all of the handlers in the set. The order
+// in which handlers are yielded is not guaranteed.
+func (s HandlerSet) Query(filters ...HandlerFilter) iter.Seq[Handler] {
+	return queryHandlers(s, filters...)
 }
 
 // Find returns a handler from the set for which the given predicate function
 // returns true.
 func (s HandlerSet) Find(fn func(Handler) bool) (Handler, bool) {
-	for _, h := range s {
-		if fn(h) {
-			return h, true
-		}
-	}
-
-	return nil, false
+	return findHandler(s, fn)
 }
 
 // Filter returns the subset of handlers for which the given predicate function
 // returns true.
 func (s HandlerSet) Filter(fn func(Handler) bool) HandlerSet {
-	subset := HandlerSet{}
-
-	for i, h := range s {
-		if fn(h) {
-			subset[i] = h
-		}
-	}
-
-	return subset
+	return filterHandlers(s, fn)
 }
 
 // AcceptVisitor visits each handler in the set.
@@ -160,54 +158,22 @@ func (s HandlerSet) AcceptVisitor(ctx context.Context, v Visitor) error {
 
 // Aggregates returns a slice containing the aggregate handlers in the set.
 func (s HandlerSet) Aggregates() []Aggregate {
-	var r []Aggregate
-
-	for _, h := range s {
-		if h.HandlerType() == AggregateHandlerType {
-			r = append(r, h)
-		}
-	}
-
-	return r
+	return handlersOfType[Aggregate](s, AggregateHandlerType)
 }
 
 // Processes returns a slice containing the process handlers in the set.
 func (s HandlerSet) Processes() []Process {
-	var r []Process
-
-	for _, h := range s {
-		if h.HandlerType() == ProcessHandlerType {
-			r = append(r, h)
-		}
-	}
-
-	return r
+	return handlersOfType[Process](s, ProcessHandlerType)
 }
 
 // Integrations returns a slice containing the integration handlers in the set.
 func (s HandlerSet) Integrations() []Integration {
-	var r []Integration
-
-	for _, h := range s {
-		if h.HandlerType() == IntegrationHandlerType {
-			r = append(r, h)
-		}
-	}
-
-	return r
+	return handlersOfType[Integration](s, IntegrationHandlerType)
 }
 
 // Projections returns a slice containing the projection handlers in the set.
 func (s HandlerSet) Projections() []Projection {
-	var r []Projection
-
-	for _, h := range s {
-		if h.HandlerType() == ProjectionHandlerType {
-			r = append(r, h)
-		}
-	}
-
-	return r
+	return handlersOfType[Projection](s, ProjectionHandlerType)
 }
 
 // RangeAggregates invokes fn once for each aggregate handler in the set.
@@ -217,15 +183,7 @@ func (s HandlerSet) Projections() []Projection {
 //
 // It returns true if fn returned true for all aggregate handlers.
 func (s HandlerSet) RangeAggregates(fn func(Aggregate) bool) bool {
-	for _, h := range s {
-		if h.HandlerType() == AggregateHandlerType {
-			if !fn(h) {
-				return false
-			}
-		}
-	}
-
-	return true
+	return rangeHandlersOfType(s, AggregateHandlerType, fn)
 }
 
 // RangeProcesses invokes fn once for each process handler in the set.
@@ -235,15 +193,7 @@ func (s HandlerSet) RangeAggregates(fn func(Aggregate) bool) bool {
 //
 // It returns true if fn returned true for all process handlers.
 func (s HandlerSet) RangeProcesses(fn func(Process) bool) bool {
-	for _, h := range s {
-		if h.HandlerType() == ProcessHandlerType {
-			if !fn(h) {
-				return false
-			}
-		}
-	}
-
-	return true
+	return rangeHandlersOfType(s, ProcessHandlerType, fn)
 }
 
 // RangeIntegrations invokes fn once for each integration handler in the set.
@@ -253,15 +203,7 @@ func (s HandlerSet) RangeProcesses(fn func(Process) bool) bool {
 //
 // It returns true if fn returned true for all integration handlers.
 func (s HandlerSet) RangeIntegrations(fn func(Integration) bool) bool {
-	for _, h := range s {
-		if h.HandlerType() == IntegrationHandlerType {
-			if !fn(h) {
-				return false
-			}
-		}
-	}
-
-	return true
+	return rangeHandlersOfType(s, IntegrationHandlerType, fn)
 }
 
 // RangeProjections invokes fn once for each projection handler in the set.
@@ -271,18 +213,13 @@ func (s HandlerSet) RangeIntegrations(fn func(Integration) bool) bool {
 //
 // It returns true if fn returned true for all projection handlers.
 func (s HandlerSet) RangeProjections(fn func(Projection) bool) bool {
-	for _, h := range s {
-		if h.HandlerType() == ProjectionHandlerType {
-			if !fn(h) {
-				return false
-			}
-		}
-	}
-
-	return true
+	return rangeHandlersOfType(s, ProjectionHandlerType, fn)
 }
 
 // RichHandlerSet is a collection of rich handlers.
+//
+// Lookups by name, key or message scan every handler in the set. Use
+// [IndexRichHandlers] to look up an application's handlers in constant time.
 type RichHandlerSet map[Identity]RichHandler
 
 // NewRichHandlerSet returns a RichHandlerSet containing the given handlers.
@@ -305,21 +242,12 @@ func NewRichHandlerSet(handlers ...RichHandler) RichHandlerSet {
 // It returns true if the handler was added, or false if the set already
 // contained a handler with the same name or key as h.
 func (s RichHandlerSet) Add(h RichHandler) bool {
-	i := h.Identity()
-	for x := range s {
-		if i.ConflictsWith(x) {
-			return false
-		}
-	}
-
-	s[i] = h
-	return true
+	return addHandler(s, h)
 }
 
 // Has returns true if s contains h.
 func (s RichHandlerSet) Has(h RichHandler) bool {
-	x, ok := s[h.Identity()]
-	return ok && x == h
+	return hasHandler(s, h)
 }
 
 // ByIdentity returns the handler with the given identity.
@@ -330,65 +258,37 @@ func (s RichHandlerSet) ByIdentity(i Identity) (RichHandler, bool) {
 
 // ByName returns the handler with the given name.
 func (s RichHandlerSet) ByName(n string) (RichHandler, bool) {
-	for i, h := range s {
-		if i.Name == n {
-			return h, true
-		}
-	}
-
-	return nil, false
+	return s.Find(func(h RichHandler) bool {
+		return h.Identity().Name == n
+	})
 }
 
 // ByKey returns the handler with the given key.
 func (s RichHandlerSet) ByKey(k string) (RichHandler, bool) {
-	for i, h := range s {
-		if i.Key == k {
-			return h, true
-		}
-	}
-
-	return nil, false
+	return s.Find(func(h RichHandler) bool {
+		return h.Identity().Key == k
+	})
 }
 
 // ByType returns the subset of handlers of the given type.
 func (s RichHandlerSet) ByType(t HandlerType) RichHandlerSet {
-	subset := RichHandlerSet{}
-
-	for i, h := range s {
-		if h.HandlerType() == t {
-			subset[i] = h
-		}
-	}
-
-	return subset
+	return filterHandlers(s, filterByType(t))
 }
 
 // ConsumersOf returns the subset of handlers that consume messages of the given
 // type.
 func (s RichHandlerSet) ConsumersOf(t message.Type) RichHandlerSet {
-	subset := RichHandlerSet{}
-
-	for i, h := range s {
-		if h.MessageTypes()[t].IsConsumed {
-			subset[i] = h
-		}
-	}
-
-	return subset
+	return s.Filter(func(h RichHandler) bool {
+		return h.MessageTypes()[t].IsConsumed
+	})
 }
 
 // ProducersOf returns the subset of handlers that produce messages of the given
 // type.
 func (s RichHandlerSet) ProducersOf(t message.Type) RichHandlerSet {
-	subset := RichHandlerSet{}
-
-	for i, h := range s {
-		if h.MessageTypes()[t].IsProduced {
-			subset[i] = h
-		}
-	}
-
-	return subset
+	return s.Filter(func(h RichHandler) bool {
+		return h.MessageTypes()[t].IsProduced
+	})
 }
 
 // MessageTypes returns information about the messages used all handlers in s.
@@ -404,44 +304,49 @@ func (s RichHandlerSet) MessageTypes() EntityMessages[message.Type] {
 
 // IsEqual returns true if o contains the same handlers as s.
 func (s RichHandlerSet) IsEqual(o RichHandlerSet) bool {
-	if len(s) != len(o) {
-		return false
-	}
+	return isHandlerSetEqual(s, o)
+}
 
-	for i, h := range s {
-		x, ok := o[i]
-		if !ok || !IsHandlerEqual(x, h) {
-			return false
-		}
-	}
+// Clone returns a copy of s.
+//
+// The handlers themselves are not copied, as handler configurations are
+// immutable.
+func (s RichHandlerSet) Clone() RichHandlerSet {
+	return maps.Clone(s)
+}
 
-	return true
+// Sorted returns an iterator that yields the handlers in the set in a
+// deterministic order.
+//
+// Handlers are ordered by their handler type, in the order that the types
+// appear in [HandlerTypes], then by name.
+func (s RichHandlerSet) Sorted() iter.Seq[RichHandler] {
+	return sortedHandlers(s)
+}
+
+// Query returns an iterator that yields the handlers in the set that match all
+// of the given filters.
+//
+// If no filters are given, it yields all of the handlers in the set. The order
+// in which handlers are yielded is not guaranteed.
+func (s RichHandlerSet) Query(filters ...HandlerFilter) iter.Seq[RichHandler] {
+	return queryHandlers(s, filters...)
 }
 
 // Find returns a handler from the set for which the given predicate function
 // returns true.
 func (s RichHandlerSet) Find(fn func(RichHandler) bool) (RichHandler, bool) {
-	for _, h := range s {
-		if fn(h) {
-			return h, true
-		}
-	}
-
-	return nil, false
+	return findHandler(s, func(h Handler) bool {
+		return fn(h.(RichHandler))
+	})
 }
 
 // Filter returns the subset of handlers for which the given predicate function
 // returns true.
 func (s RichHandlerSet) Filter(fn func(RichHandler) bool) RichHandlerSet {
-	subset := RichHandlerSet{}
-
-	for i, h := range s {
-		if fn(h) {
-			subset[i] = h
-		}
-	}
-
-	return subset
+	return filterHandlers(s, func(h Handler) bool {
+		return fn(h.(RichHandler))
+	})
 }
 
 // AcceptRichVisitor visits each handler in the set.
@@ -462,54 +367,22 @@ func (s RichHandlerSet) AcceptRichVisitor(ctx context.Context, v RichVisitor) er
 
 // Aggregates returns a slice containing the aggregate handlers in the set.
 func (s RichHandlerSet) Aggregates() []RichAggregate {
-	var r []RichAggregate
-
-	for _, h := range s {
-		if x, ok := h.(RichAggregate); ok {
-			r = append(r, x)
-		}
-	}
-
-	return r
+	return handlersOfType[RichAggregate](s, AggregateHandlerType)
 }
 
 // Processes returns a slice containing the process handlers in the set.
 func (s RichHandlerSet) Processes() []RichProcess {
-	var r []RichProcess
-
-	for _, h := range s {
-		if x, ok := h.(RichProcess); ok {
-			r = append(r, x)
-		}
-	}
-
-	return r
+	return handlersOfType[RichProcess](s, ProcessHandlerType)
 }
 
 // Integrations returns a slice containing the integration handlers in the set.
 func (s RichHandlerSet) Integrations() []RichIntegration {
-	var r []RichIntegration
-
-	for _, h := range s {
-		if x, ok := h.(RichIntegration); ok {
-			r = append(r, x)
-		}
-	}
-
-	return r
+	return handlersOfType[RichIntegration](s, IntegrationHandlerType)
 }
 
 // Projections returns a slice containing the projection handlers in the set.
 func (s RichHandlerSet) Projections() []RichProjection {
-	var r []RichProjection
-
-	for _, h := range s {
-		if x, ok := h.(RichProjection); ok {
-			r = append(r, x)
-		}
-	}
-
-	return r
+	return handlersOfType[RichProjection](s, ProjectionHandlerType)
 }
 
 // RangeAggregates invokes fn once for each aggregate handler in the set.
@@ -519,15 +392,7 @@ func (s RichHandlerSet) Projections() []RichProjection {
 //
 // It returns true if fn returned true for all aggregate handlers.
 func (s RichHandlerSet) RangeAggregates(fn func(RichAggregate) bool) bool {
-	for _, h := range s {
-		if x, ok := h.(RichAggregate); ok {
-			if !fn(x) {
-				return false
-			}
-		}
-	}
-
-	return true
+	return rangeHandlersOfType(s, AggregateHandlerType, fn)
 }
 
 // RangeProcesses invokes fn once for each process handler in the set.
@@ -537,15 +402,7 @@ func (s RichHandlerSet) RangeAggregates(fn func(RichAggregate) bool) bool {
 //
 // It returns true if fn returned true for all process handlers.
 func (s RichHandlerSet) RangeProcesses(fn func(RichProcess) bool) bool {
-	for _, h := range s {
-		if x, ok := h.(RichProcess); ok {
-			if !fn(x) {
-				return false
-			}
-		}
-	}
-
-	return true
+	return rangeHandlersOfType(s, ProcessHandlerType, fn)
 }
 
 // RangeIntegrations invokes fn once for each integration handler in the set.
@@ -555,15 +412,7 @@ func (s RichHandlerSet) RangeProcesses(fn func(RichProcess) bool) bool {
 //
 // It returns true if fn returned true for all integration handlers.
 func (s RichHandlerSet) RangeIntegrations(fn func(RichIntegration) bool) bool {
-	for _, h := range s {
-		if x, ok := h.(RichIntegration); ok {
-			if !fn(x) {
-				return false
-			}
-		}
-	}
-
-	return true
+	return rangeHandlersOfType(s, IntegrationHandlerType, fn)
 }
 
 // RangeProjections invokes fn once for each projection handler in the set.
@@ -573,21 +422,37 @@ func (s RichHandlerSet) RangeIntegrations(fn func(RichIntegration) bool) bool {
 //
 // It returns true if fn returned true for all projection handlers.
 func (s RichHandlerSet) RangeProjections(fn func(RichProjection) bool) bool {
-	for _, h := range s {
-		if x, ok := h.(RichProjection); ok {
-			if !fn(x) {
-				return false
+	return rangeHandlersOfType(s, ProjectionHandlerType, fn)
+}
+
+// sortedHandlers returns an iterator that yields the handlers in s ordered by
+// handler type, then by name.
+func sortedHandlers[H Handler](s map[Identity]H) iter.Seq[H] {
+	return func(yield func(H) bool) {
+		sorted := slices.SortedFunc(maps.Values(s), compareHandlers)
+
+		for _, h := range sorted {
+			if !yield(h) {
+				return
 			}
 		}
 	}
-
-	return true
 }
 
-func (s RichHandlerSet) asHandlerSet() HandlerSet {
-	set := make(HandlerSet, len(s))
-	for k, v := range s {
-		set[k] = v
+// compareHandlers orders handlers by handler type, then by name, then by key.
+func compareHandlers[H Handler](a, b H) int {
+	if c := cmp.Compare(
+		slices.Index(HandlerTypes, a.HandlerType()),
+		slices.Index(HandlerTypes, b.HandlerType()),
+	); c != 0 {
+		return c
 	}
-	return set
+
+	ai, bi := a.Identity(), b.Identity()
+
+	if c := strings.Compare(ai.Name, bi.Name); c != 0 {
+		return c
+	}
+
+	return strings.Compare(ai.Key, bi.Key)
 }
@@ -3,6 +3,7 @@ package configkit_test
 import (
 	"context"
 	"errors"
+	"slices"
 
 	. "github.com/dogmatiq/configkit"
 	"github.com/dogmatiq/dogma"
@@ -148,6 +149,11 @@ var _ = Describe("type HandlerSet", func() {
 			Expect(subset).To(HaveLen(1))
 			Expect(set.Has(aggregate)).To(BeTrue())
 		})
+
+		It("returns an empty set if the type is invalid", func() {
+			subset := set.ByType("<invalid>")
+			Expect(subset).To(BeEmpty())
+		})
 	})
 
 	Describe("func ConsumersOf()", func() {
@@ -199,6 +205,18 @@ var _ = Describe("type HandlerSet", func() {
 		})
 	})
 
+	Describe("func Clone()", func() {
+		It("returns a copy that can be modified independently", func() {
+			s := NewHandlerSet(aggregate, projection)
+
+			c := s.Clone()
+			Expect(c).To(Equal(s))
+
+			delete(c, aggregate.Identity())
+			Expect(s.Has(aggregate)).To(BeTrue())
+		})
+	})
+
 	Describe("func IsEqual()", func() {
 		DescribeTable(
 			"returns true if the sets are equivalent",
@@ -253,6 +271,42 @@ var _ = Describe("type HandlerSet", func() {
 		)
 	})
 
+	Describe("func Sorted()", func() {
+		It("yields the handlers ordered by handler type, then by name", func() {
+			another := FromProjection(&ProjectionMessageHandlerStub{
+				ConfigureFunc: func(c dogma.ProjectionConfigurer) {
+					c.Identity("<another-proj-name>", processKey)
+					c.Routes(
+						dogma.HandlesEvent[*EventStub[TypeA]](),
+					)
+				},
+			})
+
+			set.Add(projection)
+			set.Add(another)
+			set.Add(aggregate)
+
+			Expect(slices.Collect(set.Sorted())).To(Equal([]Handler{
+				aggregate,
+				another,
+				projection,
+			}))
+		})
+
+		It("stops iterating when the yield function returns false", func() {
+			set.Add(aggregate)
+			set.Add(projection)
+
+			var visited []Handler
+			for h := range set.Sorted() {
+				visited = append(visited, h)
+				break
+			}
+
+			Expect(visited).To(Equal([]Handler{aggregate}))
+		})
+	})
+
 	Describe("func Find()", func() {
 		BeforeEach(func() {
 			set.Add(aggregate)
@@ -695,6 +749,11 @@ var _ = Describe("type RichHandlerSet", func() {
 			Expect(subset).To(HaveLen(1))
 			Expect(set.Has(aggregate)).To(BeTrue())
 		})
+
+		It("returns an empty set if the type is invalid", func() {
+			subset := set.ByType("<invalid>")
+			Expect(subset).To(BeEmpty())
+		})
 	})
 
 	Describe("func ConsumersOf()", func() {
@@ -746,6 +805,18 @@ var _ = Describe("type RichHandlerSet", func() {
 		})
 	})
 
+	Describe("func Clone()", func() {
+		It("returns a copy that can be modified independently", func() {
+			s := NewRichHandlerSet(aggregate, projection)
+
+			c := s.Clone()
+			Expect(c).To(Equal(s))
+
+			delete(c, aggregate.Identity())
+			Expect(s.Has(aggregate)).To(BeTrue())
+		})
+	})
+
 	Describe("func IsEqual()", func() {
 		DescribeTable(
 			"returns true if the sets are equivalent",
@@ -800,6 +871,42 @@ var _ = Describe("type RichHandlerSet", func() {
 		)
 	})
 
+	Describe("func Sorted()", func() {
+		It("yields the handlers ordered by handler type, then by name", func() {
+			another := FromProjection(&ProjectionMessageHandlerStub{
+				ConfigureFunc: func(c dogma.ProjectionConfigurer) {
+					c.Identity("<another-proj-name>", processKey)
+					c.Routes(
+						dogma.HandlesEvent[*EventStub[TypeA]](),
+					)
+				},
+			})
+
+			set.Add(projection)
+			set.Add(another)
+			set.Add(aggregate)
+
+			Expect(slices.Collect(set.Sorted())).To(Equal([]RichHandler{
+				aggregate,
+				another,
+				projection,
+			}))
+		})
+
+		It("stops iterating when the yield function returns false", func() {
+			set.Add(aggregate)
+			set.Add(projection)
+
+			var visited []RichHandler
+			for h := range set.Sorted() {
+				visited = append(visited, h)
+				break
+			}
+
+			Expect(visited).To(Equal([]RichHandler{aggregate}))
+		})
+	})
+
 	Describe("func Find()", func() {
 		BeforeEach(func() {
 			set.Add(aggregate)
@@ -0,0 +1,178 @@
+package history
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dogmatiq/configkit"
+	"github.com/dogmatiq/configkit/snapshot"
+)
+
+// FileStorage is an implementation of [Storage] that keeps revisions in files
+// within a directory.
+//
+// Each application has its own sub-directory, named after its key. Each
+// revision is a snapshot file within that sub-directory, as written by
+// [snapshot.Write], named after the time at which the revision was observed.
+type FileStorage struct {
+	// Dir is the directory that contains the revisions. It is created if it
+	// does not already exist.
+	Dir string
+}
+
+var _ Storage = (*FileStorage)(nil)
+
+// revisionFileNameFormat is the format of the name of each revision file. The
+// fixed-width timestamp ensures that lexical order is chronological order.
+const revisionFileNameFormat = "%020d.json"
+
+// Append adds a revision of the application with the given key.
+//
+// The revision is written to a temporary file that is renamed into place once
+// it is complete, such that a failure part-way through writing the revision
+// does not leave a partial revision in the directory.
+//
+// It returns an error if the revision was observed before 1970, or after
+// 2262, as the file name can not represent such times.
+func (s *FileStorage) Append(_ context.Context, key string, r Revision) error {
+	dir, err := s.appDir(key)
+	if err != nil {
+		return err
+	}
+
+	ns := r.ObservedAt.UnixNano()
+	if ns < 0 || !time.Unix(0, ns).Equal(r.ObservedAt) {
+		return fmt.Errorf(
+			"can not store a revision observed at %s, which is outside the supported range of 1970 to 2262",
+			r.ObservedAt,
+		)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := snapshot.Write(&buf, snapshot.Snapshot{
+		CreatedAt:    r.ObservedAt,
+		Applications: []configkit.Application{r.Application},
+	}); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf(revisionFileNameFormat, ns)
+	path := filepath.Join(dir, name)
+
+	if _, err := os.Lstat(path); err == nil {
+		return fmt.Errorf("%s: %w", path, fs.ErrExist)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	// The temporary file does not have a ".json" extension, so it is ignored
+	// by Revisions() if it is left behind.
+	f, err := os.CreateTemp(dir, name+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name()) // no-op once renamed
+
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+// Revisions returns the revisions of the application with the given key, in
+// the order they were appended.
+func (s *FileStorage) Revisions(_ context.Context, key string) ([]Revision, error) {
+	dir, err := s.appDir(key)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var revisions []Revision
+
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+
+		snap, err := snapshot.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		if len(snap.Applications) != 1 {
+			return nil, fmt.Errorf(
+				"%s: expected exactly one application, found %d",
+				entry.Name(),
+				len(snap.Applications),
+			)
+		}
+
+		app := snap.Applications[0]
+
+		revisions = append(revisions, Revision{
+			ObservedAt:  snap.CreatedAt,
+			Fingerprint: configkit.Fingerprint(app),
+			Application: app,
+		})
+	}
+
+	return revisions, nil
+}
+
+// Keys returns the keys of the applications that have at least one revision.
+func (s *FileStorage) Keys(context.Context) ([]string, error) {
+	entries, err := os.ReadDir(s.Dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			keys = append(keys, entry.Name())
+		}
+	}
+
+	return keys, nil
+}
+
+// appDir returns the directory that contains the revisions of the application
+// with the given key.
+func (s *FileStorage) appDir(key string) (string, error) {
+	if key == "" || key == "." || key == ".." || strings.ContainsAny(key, `/\`) {
+		return "", fmt.Errorf("invalid application key %q", key)
+	}
+
+	return filepath.Join(s.Dir, key), nil
+}
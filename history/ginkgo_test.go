@@ -1,4 +1,4 @@
-package gotypes_test
+package history_test
 
 import (
 	"reflect"
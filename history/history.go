@@ -0,0 +1,309 @@
+// Package history records the configurations of applications over time, and
+// answers questions about how they have changed.
+package history
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dogmatiq/configkit"
+	"github.com/dogmatiq/configkit/api"
+	"github.com/dogmatiq/configkit/snapshot"
+	"github.com/dogmatiq/enginekit/message"
+)
+
+// History records the configurations of applications over time.
+//
+// Each configuration that is recorded is stored as a [Revision] of the
+// application with the same identity key, unless it has the same fingerprint as
+// the latest existing revision of that application.
+type History struct {
+	// Storage is the storage used to persist revisions.
+	Storage Storage
+
+	m sync.Mutex
+}
+
+// Record records the configurations of the given applications, as observed at
+// the given time.
+//
+// It returns an error if at is before the latest revision of any of the
+// applications, or if at is equal to the time of the latest revision and the
+// configuration has changed.
+func (h *History) Record(
+	ctx context.Context,
+	at time.Time,
+	apps ...configkit.Application,
+) error {
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	for _, app := range apps {
+		if err := h.record(ctx, at, app); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (h *History) record(
+	ctx context.Context,
+	at time.Time,
+	app configkit.Application,
+) error {
+	key := app.Identity().Key
+
+	revisions, err := h.Storage.Revisions(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	fp := configkit.Fingerprint(app)
+
+	if n := len(revisions); n != 0 {
+		latest := revisions[n-1]
+
+		if at.Before(latest.ObservedAt) {
+			return fmt.Errorf(
+				"can not record the %s application at %s, it has already been recorded at %s",
+				app.Identity(),
+				at.Format(time.RFC3339Nano),
+				latest.ObservedAt.Format(time.RFC3339Nano),
+			)
+		}
+
+		if bytes.Equal(latest.Fingerprint, fp) {
+			return nil
+		}
+
+		// Each revision must have a distinct time, otherwise it is ambiguous
+		// which configuration was in effect at that time.
+		if at.Equal(latest.ObservedAt) {
+			return fmt.Errorf(
+				"can not record the %s application at %s, a different configuration has already been recorded at that time",
+				app.Identity(),
+				at.Format(time.RFC3339Nano),
+			)
+		}
+	}
+
+	return h.Storage.Append(
+		ctx,
+		key,
+		Revision{
+			ObservedAt:  at,
+			Fingerprint: fp,
+			Application: app,
+		},
+	)
+}
+
+// RecordSnapshot records the configurations of the applications in s, as
+// observed at the time the snapshot was created.
+func (h *History) RecordSnapshot(ctx context.Context, s snapshot.Snapshot) error {
+	return h.Record(ctx, s.CreatedAt, s.Applications...)
+}
+
+// Poll records the configurations of the applications served by c at the given
+// interval, until ctx is canceled or an error occurs.
+//
+// Configurations are only downloaded from the server when they have changed
+// since the previous poll.
+func (h *History) Poll(
+	ctx context.Context,
+	c *api.Client,
+	interval time.Duration,
+) error {
+	var fingerprint []byte
+
+	for {
+		apps, latest, modified, err := c.ListApplicationsIfModified(ctx, fingerprint)
+		if err != nil {
+			// Report cancelation consistently, regardless of whether it
+			// occurred during a request or between requests.
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+
+		if modified {
+			if err := h.Record(ctx, time.Now(), apps...); err != nil {
+				return err
+			}
+			fingerprint = latest
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// At returns the configuration of the application with the given key, as it
+// was at time t.
+//
+// ok is false if the application had not been observed by time t.
+func (h *History) At(
+	ctx context.Context,
+	key string,
+	t time.Time,
+) (_ configkit.Application, ok bool, _ error) {
+	revisions, err := h.Storage.Revisions(ctx, key)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if r, ok := revisionAt(revisions, t); ok {
+		return r.Application, true, nil
+	}
+
+	return nil, false, nil
+}
+
+// FirstSeen returns the time at which the handler with the given key was first
+// observed within the application with the given key.
+//
+// ok is false if the handler has never been observed within the application.
+func (h *History) FirstSeen(
+	ctx context.Context,
+	appKey, handlerKey string,
+) (_ time.Time, ok bool, _ error) {
+	revisions, err := h.Storage.Revisions(ctx, appKey)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	for _, r := range revisions {
+		if _, ok := r.Application.Handlers().ByKey(handlerKey); ok {
+			return r.ObservedAt, true, nil
+		}
+	}
+
+	return time.Time{}, false, nil
+}
+
+// ChangeSet is a set of changes to an application's configuration that were
+// observed at a specific point in time.
+type ChangeSet struct {
+	// Revision is the revision that introduced the changes.
+	Revision Revision
+
+	// Changes is the list of changes from the previous revision.
+	Changes []configkit.Change
+}
+
+// Changes returns the changes to the application with the given key that were
+// observed after t1, up to and including t2.
+//
+// Each element of the result describes the changes introduced by a single
+// revision, relative to the revision before it. If the application was first
+// observed within the period, the changes for the first revision describe the
+// addition of each of its handlers.
+func (h *History) Changes(
+	ctx context.Context,
+	key string,
+	t1, t2 time.Time,
+) ([]ChangeSet, error) {
+	revisions, err := h.Storage.Revisions(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		changes []ChangeSet
+		prev    configkit.Application
+	)
+
+	for _, r := range revisions {
+		if r.ObservedAt.After(t2) {
+			break
+		}
+
+		if r.ObservedAt.After(t1) {
+			before := prev
+			if before == nil {
+				before = empty{r.Application}
+			}
+
+			changes = append(changes, ChangeSet{
+				Revision: r,
+				Changes:  configkit.Diff(before, r.Application),
+			})
+		}
+
+		prev = r.Application
+	}
+
+	return changes, nil
+}
+
+// Diff returns the net changes to the application with the given key between
+// t1 and t2.
+//
+// ok is false if the application had not been observed by time t2. If the
+// application had not been observed by time t1, the changes describe the
+// addition of each of its handlers.
+func (h *History) Diff(
+	ctx context.Context,
+	key string,
+	t1, t2 time.Time,
+) (_ []configkit.Change, ok bool, _ error) {
+	revisions, err := h.Storage.Revisions(ctx, key)
+	if err != nil {
+		return nil, false, err
+	}
+
+	after, ok := revisionAt(revisions, t2)
+	if !ok {
+		return nil, false, nil
+	}
+
+	var before configkit.Application = empty{after.Application}
+	if r, ok := revisionAt(revisions, t1); ok {
+		before = r.Application
+	}
+
+	return configkit.Diff(before, after.Application), true, nil
+}
+
+// revisionAt returns the latest revision that was observed at or before t.
+func revisionAt(revisions []Revision, t time.Time) (Revision, bool) {
+	for i := len(revisions) - 1; i >= 0; i-- {
+		if !revisions[i].ObservedAt.After(t) {
+			return revisions[i], true
+		}
+	}
+
+	return Revision{}, false
+}
+
+// empty is a [configkit.Application] with the same identity and type as the
+// embedded application, but with no messages, metadata or handlers. It is used
+// as the "before" configuration when diffing an application that has not
+// previously been observed.
+type empty struct {
+	configkit.Application
+}
+
+func (empty) MessageNames() configkit.EntityMessages[message.Name] {
+	return nil
+}
+
+func (empty) MessageTypes() configkit.EntityMessages[message.Type] {
+	return nil
+}
+
+func (empty) Metadata() configkit.Metadata {
+	return nil
+}
+
+func (empty) Handlers() configkit.HandlerSet {
+	return nil
+}
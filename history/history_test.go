@@ -0,0 +1,381 @@
+package history_test
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/dogmatiq/configkit"
+	"github.com/dogmatiq/configkit/api"
+	. "github.com/dogmatiq/configkit/history"
+	"github.com/dogmatiq/configkit/snapshot"
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	"github.com/dogmatiq/enginekit/grpc/configgrpc"
+	"github.com/dogmatiq/enginekit/message"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+var _ = Describe("type History", func() {
+	var (
+		ctx            context.Context
+		history        *History
+		t1, t2, t3     time.Time
+		v1, v2, v3     configkit.Application
+		appKey         = "59a82a24-a181-41e8-9b93-17a6ce86956e"
+		aggregateKey   = "14769f7f-87fe-48dd-916e-5bcab6ba6aca"
+		integrationKey = "e28f056e-e5a0-4ee7-aaf1-1d1fe02fb6e3"
+	)
+
+	newApp := func(
+		disabled, withIntegration bool,
+		routes ...dogma.AggregateRoute,
+	) configkit.Application {
+		return configkit.FromApplication(&ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app>", appKey)
+				c.Routes(
+					dogma.ViaAggregate(&AggregateMessageHandlerStub{
+						ConfigureFunc: func(c dogma.AggregateConfigurer) {
+							c.Identity("<aggregate>", aggregateKey)
+							c.Routes(
+								dogma.HandlesCommand[*CommandStub[TypeA]](),
+								dogma.RecordsEvent[*EventStub[TypeA]](),
+							)
+							c.Routes(routes...)
+							if disabled {
+								c.Disable()
+							}
+						},
+					}),
+				)
+
+				if withIntegration {
+					c.Routes(
+						dogma.ViaIntegration(&IntegrationMessageHandlerStub{
+							ConfigureFunc: func(c dogma.IntegrationConfigurer) {
+								c.Identity("<integration>", integrationKey)
+								c.Routes(
+									dogma.HandlesCommand[*CommandStub[TypeB]](),
+								)
+							},
+						}),
+					)
+				}
+			},
+		})
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		history = &History{Storage: &MemoryStorage{}}
+
+		t1 = time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+		t2 = t1.Add(1 * time.Hour)
+		t3 = t2.Add(1 * time.Hour)
+
+		v1 = newApp(false, false)
+		v2 = newApp(true, false)
+		v3 = newApp(true, true)
+	})
+
+	recordAll := func() {
+		Expect(history.Record(ctx, t1, v1)).To(Succeed())
+		Expect(history.Record(ctx, t2, v2)).To(Succeed())
+		Expect(history.Record(ctx, t3, v3)).To(Succeed())
+	}
+
+	Describe("func Record()", func() {
+		It("does not record a configuration with the same fingerprint as the latest revision", func() {
+			Expect(history.Record(ctx, t1, v1)).To(Succeed())
+			Expect(history.Record(ctx, t2, newApp(false, false))).To(Succeed())
+
+			revisions, err := history.Storage.Revisions(ctx, appKey)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(revisions).To(HaveLen(1))
+			Expect(revisions[0].ObservedAt).To(Equal(t1))
+		})
+
+		It("records a configuration that reverts to an earlier revision", func() {
+			Expect(history.Record(ctx, t1, v1)).To(Succeed())
+			Expect(history.Record(ctx, t2, v2)).To(Succeed())
+			Expect(history.Record(ctx, t3, v1)).To(Succeed())
+
+			revisions, err := history.Storage.Revisions(ctx, appKey)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(revisions).To(HaveLen(3))
+		})
+
+		It("returns an error if the configuration was observed before the latest revision", func() {
+			Expect(history.Record(ctx, t2, v1)).To(Succeed())
+
+			err := history.Record(ctx, t1, v2)
+			Expect(err).To(MatchError(
+				"can not record the <app>/59a82a24-a181-41e8-9b93-17a6ce86956e application at 2025-10-01T00:00:00Z, it has already been recorded at 2025-10-01T01:00:00Z",
+			))
+		})
+	})
+
+	Describe("func RecordSnapshot()", func() {
+		It("records the applications at the time the snapshot was created", func() {
+			s := snapshot.New(v1)
+			s.CreatedAt = t2
+
+			Expect(history.RecordSnapshot(ctx, s)).To(Succeed())
+
+			_, ok, err := history.At(ctx, appKey, t1)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ok).To(BeFalse())
+
+			app, ok, err := history.At(ctx, appKey, t2)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(configkit.IsApplicationEqual(app, v1)).To(BeTrue())
+		})
+	})
+
+	Describe("func At()", func() {
+		BeforeEach(recordAll)
+
+		It("returns the configuration as it was at the given time", func() {
+			app, ok, err := history.At(ctx, appKey, t2.Add(30*time.Minute))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(app).To(BeIdenticalTo(v2))
+		})
+
+		It("returns false if the application had not been observed by the given time", func() {
+			_, ok, err := history.At(ctx, appKey, t1.Add(-time.Nanosecond))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Describe("func FirstSeen()", func() {
+		BeforeEach(recordAll)
+
+		It("returns the time at which the handler was first observed", func() {
+			t, ok, err := history.FirstSeen(ctx, appKey, integrationKey)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(t).To(Equal(t3))
+
+			t, ok, err = history.FirstSeen(ctx, appKey, aggregateKey)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(t).To(Equal(t1))
+		})
+
+		It("returns false if the handler has never been observed", func() {
+			_, ok, err := history.FirstSeen(ctx, appKey, "938b829d-e4d7-4780-bf06-ea349453ba8f")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Describe("func Changes()", func() {
+		BeforeEach(recordAll)
+
+		It("returns the changes introduced by each revision within the period", func() {
+			changes, err := history.Changes(ctx, appKey, t1, t3)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(changes).To(HaveLen(2))
+
+			Expect(changes[0].Revision.ObservedAt).To(Equal(t2))
+			Expect(changes[0].Changes).To(Equal([]configkit.Change{
+				{
+					Type:    configkit.HandlerDisabled,
+					Handler: configkit.MustNewIdentity("<aggregate>", aggregateKey),
+				},
+			}))
+
+			Expect(changes[1].Revision.ObservedAt).To(Equal(t3))
+			Expect(changes[1].Changes).To(Equal([]configkit.Change{
+				{
+					Type:    configkit.HandlerAdded,
+					Handler: configkit.MustNewIdentity("<integration>", integrationKey),
+				},
+			}))
+		})
+
+		It("describes the first revision as the addition of each handler", func() {
+			changes, err := history.Changes(ctx, appKey, time.Time{}, t1)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(changes).To(HaveLen(1))
+			Expect(changes[0].Changes).To(Equal([]configkit.Change{
+				{
+					Type:    configkit.HandlerAdded,
+					Handler: configkit.MustNewIdentity("<aggregate>", aggregateKey),
+				},
+			}))
+		})
+
+		It("describes the metadata of the first revision as a change", func() {
+			history = &History{Storage: &MemoryStorage{}}
+
+			app := configkit.FromApplication(
+				v1.(configkit.RichApplication).Application(),
+				configkit.WithMetadata(configkit.MetadataSet{
+					appKey: {configkit.OwnerMetadataKey: "<team>"},
+				}),
+			)
+			Expect(history.Record(ctx, t1, app)).To(Succeed())
+
+			changes, err := history.Changes(ctx, appKey, time.Time{}, t1)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(changes).To(HaveLen(1))
+			Expect(changes[0].Changes).To(Equal([]configkit.Change{
+				{
+					Type:  configkit.MetadataChanged,
+					After: `owner="<team>"`,
+				},
+				{
+					Type:    configkit.HandlerAdded,
+					Handler: configkit.MustNewIdentity("<aggregate>", aggregateKey),
+				},
+			}))
+		})
+	})
+
+	Describe("func Diff()", func() {
+		BeforeEach(recordAll)
+
+		It("returns the net changes between two points in time", func() {
+			changes, ok, err := history.Diff(ctx, appKey, t1, t3)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(changes).To(Equal([]configkit.Change{
+				{
+					Type:    configkit.HandlerDisabled,
+					Handler: configkit.MustNewIdentity("<aggregate>", aggregateKey),
+				},
+				{
+					Type:    configkit.HandlerAdded,
+					Handler: configkit.MustNewIdentity("<integration>", integrationKey),
+				},
+			}))
+		})
+
+		It("includes route changes", func() {
+			t4 := t3.Add(1 * time.Hour)
+			v4 := newApp(true, true, dogma.RecordsEvent[*EventStub[TypeB]]())
+			Expect(history.Record(ctx, t4, v4)).To(Succeed())
+
+			changes, ok, err := history.Diff(ctx, appKey, t3, t4)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(changes).To(Equal([]configkit.Change{
+				{
+					Type:    configkit.RouteAdded,
+					Handler: configkit.MustNewIdentity("<aggregate>", aggregateKey),
+					Route: configkit.Route{
+						Message:    message.NameOf(&EventStub[TypeB]{}),
+						Kind:       message.EventKind,
+						IsProduced: true,
+					},
+				},
+			}))
+		})
+
+		It("returns false if the application had not been observed by the end of the period", func() {
+			_, ok, err := history.Diff(ctx, appKey, time.Time{}, t1.Add(-time.Nanosecond))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Describe("func Poll()", func() {
+		It("records the configurations served by the API", func() {
+			listener, err := net.Listen("tcp", "127.0.0.1:")
+			Expect(err).ShouldNot(HaveOccurred())
+
+			server := api.NewServer(v1)
+			gserver := grpc.NewServer()
+			configgrpc.RegisterConfigAPIServer(gserver, server)
+			go gserver.Serve(listener)
+			defer gserver.Stop()
+
+			conn, err := grpc.NewClient(
+				listener.Addr().String(),
+				grpc.WithTransportCredentials(insecure.NewCredentials()),
+			)
+			Expect(err).ShouldNot(HaveOccurred())
+			defer conn.Close()
+
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+
+			result := make(chan error, 1)
+			go func() {
+				result <- history.Poll(ctx, api.NewClient(conn), 5*time.Millisecond)
+			}()
+
+			Eventually(func() int {
+				revisions, err := history.Storage.Revisions(ctx, appKey)
+				Expect(err).ShouldNot(HaveOccurred())
+				return len(revisions)
+			}).Should(Equal(1))
+
+			Expect(server.Update(v2)).To(Succeed())
+
+			Eventually(func() int {
+				revisions, err := history.Storage.Revisions(ctx, appKey)
+				Expect(err).ShouldNot(HaveOccurred())
+				return len(revisions)
+			}).Should(Equal(2))
+
+			cancel()
+			Eventually(result).Should(Receive(Equal(context.Canceled)))
+		})
+
+		It("returns the context error if ctx is canceled while a request is in flight", func() {
+			listener, err := net.Listen("tcp", "127.0.0.1:")
+			Expect(err).ShouldNot(HaveOccurred())
+
+			server := &blockingServer{inFlight: make(chan struct{})}
+			gserver := grpc.NewServer()
+			configgrpc.RegisterConfigAPIServer(gserver, server)
+			go gserver.Serve(listener)
+			defer gserver.Stop()
+
+			conn, err := grpc.NewClient(
+				listener.Addr().String(),
+				grpc.WithTransportCredentials(insecure.NewCredentials()),
+			)
+			Expect(err).ShouldNot(HaveOccurred())
+			defer conn.Close()
+
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+
+			result := make(chan error, 1)
+			go func() {
+				result <- history.Poll(ctx, api.NewClient(conn), time.Hour)
+			}()
+
+			Eventually(server.inFlight).Should(BeClosed())
+
+			cancel()
+			Eventually(result).Should(Receive(Equal(context.Canceled)))
+		})
+	})
+})
+
+// blockingServer is an implementation of [configgrpc.ConfigAPIServer] that
+// blocks each request until it is canceled.
+type blockingServer struct {
+	configgrpc.UnimplementedConfigAPIServer
+	inFlight chan struct{}
+}
+
+func (s *blockingServer) ListApplications(
+	ctx context.Context,
+	_ *configgrpc.ListApplicationsRequest,
+) (*configgrpc.ListApplicationsResponse, error) {
+	close(s.inFlight)
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
@@ -0,0 +1,86 @@
+package history
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/dogmatiq/configkit"
+)
+
+// Revision is a configuration of an application that was observed at a
+// specific point in time.
+type Revision struct {
+	// ObservedAt is the time at which the configuration was first observed.
+	ObservedAt time.Time
+
+	// Fingerprint is the fingerprint of the configuration, as per
+	// [configkit.Fingerprint].
+	Fingerprint []byte
+
+	// Application is the configuration of the application.
+	Application configkit.Application
+}
+
+// Storage is an interface for persisting the revisions of applications.
+type Storage interface {
+	// Append adds a revision of the application with the given key.
+	//
+	// The revision's observation time is never before that of the latest
+	// existing revision of the same application.
+	Append(ctx context.Context, key string, r Revision) error
+
+	// Revisions returns the revisions of the application with the given key,
+	// in the order they were appended.
+	Revisions(ctx context.Context, key string) ([]Revision, error)
+
+	// Keys returns the keys of the applications that have at least one
+	// revision, in any order.
+	Keys(ctx context.Context) ([]string, error)
+}
+
+// MemoryStorage is an implementation of [Storage] that keeps revisions in
+// memory.
+type MemoryStorage struct {
+	m         sync.RWMutex
+	revisions map[string][]Revision
+}
+
+var _ Storage = (*MemoryStorage)(nil)
+
+// Append adds a revision of the application with the given key.
+func (s *MemoryStorage) Append(_ context.Context, key string, r Revision) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if s.revisions == nil {
+		s.revisions = map[string][]Revision{}
+	}
+
+	s.revisions[key] = append(s.revisions[key], r)
+
+	return nil
+}
+
+// Revisions returns the revisions of the application with the given key, in
+// the order they were appended.
+func (s *MemoryStorage) Revisions(_ context.Context, key string) ([]Revision, error) {
+	s.m.RLock()
+	defer s.m.RUnlock()
+
+	return slices.Clone(s.revisions[key]), nil
+}
+
+// Keys returns the keys of the applications that have at least one revision.
+func (s *MemoryStorage) Keys(context.Context) ([]string, error) {
+	s.m.RLock()
+	defer s.m.RUnlock()
+
+	var keys []string
+	for k := range s.revisions {
+		keys = append(keys, k)
+	}
+
+	return keys, nil
+}
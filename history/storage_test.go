@@ -0,0 +1,197 @@
+package history_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dogmatiq/configkit"
+	. "github.com/dogmatiq/configkit/history"
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// describeStorage declares specs that are common to all implementations of
+// [Storage].
+func describeStorage(setup func() (s Storage, teardown func())) {
+	var (
+		ctx      context.Context
+		storage  Storage
+		teardown func()
+		app      configkit.Application
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		storage, teardown = setup()
+
+		app = configkit.FromApplication(&ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app>", "59a82a24-a181-41e8-9b93-17a6ce86956e")
+			},
+		})
+	})
+
+	AfterEach(func() {
+		teardown()
+	})
+
+	It("returns the revisions in the order they were appended", func() {
+		t1 := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+		t2 := t1.Add(1 * time.Hour)
+
+		for _, t := range []time.Time{t1, t2} {
+			err := storage.Append(ctx, app.Identity().Key, Revision{
+				ObservedAt:  t,
+				Fingerprint: configkit.Fingerprint(app),
+				Application: app,
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+		}
+
+		revisions, err := storage.Revisions(ctx, app.Identity().Key)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(revisions).To(HaveLen(2))
+
+		Expect(revisions[0].ObservedAt).To(BeTemporally("==", t1))
+		Expect(revisions[1].ObservedAt).To(BeTemporally("==", t2))
+
+		for _, r := range revisions {
+			Expect(r.Fingerprint).To(Equal(configkit.Fingerprint(app)))
+			Expect(configkit.IsApplicationEqual(r.Application, app)).To(BeTrue())
+		}
+	})
+
+	It("returns no revisions for an unknown application", func() {
+		revisions, err := storage.Revisions(ctx, "7d3927ce-d879-40a4-bd67-0fafc79d3c36")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(revisions).To(BeEmpty())
+	})
+
+	It("returns the keys of the applications that have revisions", func() {
+		keys, err := storage.Keys(ctx)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(keys).To(BeEmpty())
+
+		err = storage.Append(ctx, app.Identity().Key, Revision{
+			ObservedAt:  time.Now(),
+			Fingerprint: configkit.Fingerprint(app),
+			Application: app,
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+
+		keys, err = storage.Keys(ctx)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(keys).To(ConsistOf(app.Identity().Key))
+	})
+
+	When("used by a History", func() {
+		It("rejects a different configuration observed at the same time as the latest revision", func() {
+			history := &History{Storage: storage}
+			at := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+
+			err := history.Record(ctx, at, app)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			err = history.Record(ctx, at, app)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			changed := configkit.FromApplication(&ApplicationStub{
+				ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+					c.Identity("<renamed>", "59a82a24-a181-41e8-9b93-17a6ce86956e")
+				},
+			})
+
+			err = history.Record(ctx, at, changed)
+			Expect(err).To(MatchError(
+				"can not record the <renamed>/59a82a24-a181-41e8-9b93-17a6ce86956e application at 2025-10-01T00:00:00Z, a different configuration has already been recorded at that time",
+			))
+
+			revisions, err := storage.Revisions(ctx, app.Identity().Key)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(revisions).To(HaveLen(1))
+		})
+	})
+}
+
+var _ = Describe("type MemoryStorage", func() {
+	describeStorage(func() (Storage, func()) {
+		return &MemoryStorage{}, func() {}
+	})
+})
+
+var _ = Describe("type FileStorage", func() {
+	var dir string
+
+	describeStorage(func() (Storage, func()) {
+		var err error
+		dir, err = os.MkdirTemp("", "configkit-history-")
+		Expect(err).ShouldNot(HaveOccurred())
+
+		return &FileStorage{Dir: dir}, func() {
+			os.RemoveAll(dir)
+		}
+	})
+
+	It("ignores revisions that were not completely written", func() {
+		s := &FileStorage{Dir: dir}
+		ctx := context.Background()
+
+		app := configkit.FromApplication(&ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app>", "59a82a24-a181-41e8-9b93-17a6ce86956e")
+			},
+		})
+
+		err := s.Append(ctx, app.Identity().Key, Revision{
+			ObservedAt:  time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC),
+			Fingerprint: configkit.Fingerprint(app),
+			Application: app,
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+
+		err = os.WriteFile(
+			filepath.Join(dir, app.Identity().Key, "01759276800000000000.json.123456.tmp"),
+			[]byte(`{"schema_version": 2, "crea`),
+			0o644,
+		)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		revisions, err := s.Revisions(ctx, app.Identity().Key)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(revisions).To(HaveLen(1))
+
+		entries, err := os.ReadDir(filepath.Join(dir, app.Identity().Key))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(entries).To(HaveLen(2)) // the revision and the partial file
+	})
+
+	It("returns an error if the revision was observed before 1970", func() {
+		s := &FileStorage{Dir: dir}
+
+		app := configkit.FromApplication(&ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app>", "59a82a24-a181-41e8-9b93-17a6ce86956e")
+			},
+		})
+
+		err := s.Append(context.Background(), app.Identity().Key, Revision{
+			ObservedAt:  time.Date(1969, 12, 31, 23, 59, 59, 0, time.UTC),
+			Fingerprint: configkit.Fingerprint(app),
+			Application: app,
+		})
+		Expect(err).To(MatchError(
+			"can not store a revision observed at 1969-12-31 23:59:59 +0000 UTC, which is outside the supported range of 1970 to 2262",
+		))
+	})
+
+	It("returns an error if the application key is not a valid file name", func() {
+		s := &FileStorage{Dir: os.TempDir()}
+
+		_, err := s.Revisions(context.Background(), "../escape")
+		Expect(err).To(MatchError(`invalid application key "../escape"`))
+	})
+})
@@ -162,15 +162,22 @@ func isValidIdentityName(n string) bool {
 	return true
 }
 
+// configureIdentity sets *entityIdent to the identity with the given name and
+// key.
+//
+// in describes the module that contains the entity, as per inModule(), or is
+// empty if the entity is not part of a module.
 func configureIdentity(
 	entityIdent *Identity,
 	name, key string,
 	entityType reflect.Type,
+	in string,
 ) {
 	if !entityIdent.IsZero() {
 		validation.Panicf(
-			"%s is configured with multiple identities (%s and %s/%s), Identity() must be called exactly once within Configure()",
+			"%s%s is configured with multiple identities (%s and %s/%s), Identity() must be called exactly once within Configure()",
 			entityType,
+			in,
 			*entityIdent,
 			name,
 			key,
@@ -182,8 +189,9 @@ func configureIdentity(
 
 	if err != nil {
 		validation.Panicf(
-			"%s is configured with an invalid identity, %s",
+			"%s%s is configured with an invalid identity, %s",
 			entityType,
+			in,
 			err,
 		)
 	}
@@ -192,11 +200,13 @@ func configureIdentity(
 func mustHaveValidIdentity(
 	entityIdent Identity,
 	entityType reflect.Type,
+	in string,
 ) {
 	if entityIdent.IsZero() {
 		validation.Panicf(
-			"%s is configured without an identity, Identity() must be called exactly once within Configure()",
+			"%s%s is configured without an identity, Identity() must be called exactly once within Configure()",
 			entityType,
+			in,
 		)
 	}
 }
@@ -4,7 +4,7 @@ import (
 	"context"
 	"reflect"
 
-	"github.com/dogmatiq/configkit/internal/typename/goreflect"
+	"github.com/dogmatiq/configkit/typename"
 	"github.com/dogmatiq/dogma"
 	"github.com/dogmatiq/enginekit/message"
 )
@@ -28,15 +28,33 @@ type RichIntegration interface {
 //
 // It panics if the handler is configured incorrectly. Use Recover() to convert
 // configuration related panic values to errors.
-func FromIntegration(h dogma.IntegrationMessageHandler) RichIntegration {
-	cfg := fromIntegrationUnvalidated(h)
+func FromIntegration(h dogma.IntegrationMessageHandler, options ...ConfigureOption) RichIntegration {
+	cfg := fromIntegrationUnvalidated(h, newConfigureOptions(options))
 	cfg.mustValidate()
 	return cfg
 }
 
-func fromIntegrationUnvalidated(h dogma.IntegrationMessageHandler) *richIntegration {
-	cfg := &richIntegration{handler: h}
-	h.Configure(&integrationConfigurer{cfg})
+func fromIntegrationUnvalidated(
+	h dogma.IntegrationMessageHandler,
+	opts configureOptions,
+) *richIntegration {
+	cfg := &richIntegration{handler: h, module: opts.moduleName()}
+
+	opts.configure(
+		cfg.ReflectType(),
+		func() PanicEntity {
+			return PanicEntity{IntegrationHandlerType, cfg.ident, cfg.TypeName()}
+		},
+		func() {
+			c := &integrationConfigurer{config: cfg}
+			defer c.end()
+			h.Configure(c)
+		},
+	)
+
+	cfg.names = asMessageNames(cfg.types)
+	cfg.ident, cfg.metadata = opts.resolve(cfg.ident)
+
 	return cfg
 }
 
@@ -44,6 +62,9 @@ func fromIntegrationUnvalidated(h dogma.IntegrationMessageHandler) *richIntegrat
 type richIntegration struct {
 	ident      Identity
 	types      EntityMessages[message.Type]
+	names      EntityMessages[message.Name]
+	metadata   Metadata
+	module     string
 	isDisabled bool
 	handler    dogma.IntegrationMessageHandler
 }
@@ -53,15 +74,23 @@ func (h *richIntegration) Identity() Identity {
 }
 
 func (h *richIntegration) MessageNames() EntityMessages[message.Name] {
-	return asMessageNames(h.types)
+	return h.names
 }
 
 func (h *richIntegration) MessageTypes() EntityMessages[message.Type] {
 	return h.types
 }
 
+func (h *richIntegration) Metadata() Metadata {
+	return h.metadata
+}
+
+func (h *richIntegration) moduleName() string {
+	return h.module
+}
+
 func (h *richIntegration) TypeName() string {
-	return goreflect.NameOf(h.ReflectType())
+	return typename.FromReflect(h.ReflectType())
 }
 
 func (h *richIntegration) ReflectType() reflect.Type {
@@ -93,24 +122,28 @@ func (h *richIntegration) isConfigured() bool {
 }
 
 func (h *richIntegration) mustValidate() {
-	mustHaveValidIdentity(h.Identity(), h.ReflectType())
+	mustHaveValidIdentity(h.Identity(), h.ReflectType(), inModule(h))
 	mustHaveConsumerRoute(&h.types, message.CommandKind, h.Identity(), h.ReflectType())
 }
 
 // integrationConfigurer is the default implementation of
 // [dogma.IntegrationConfigurer].
 type integrationConfigurer struct {
+	configurerLifecycle
 	config *richIntegration
 }
 
 func (c *integrationConfigurer) Identity(name, key string) {
-	configureIdentity(&c.config.ident, name, key, c.config.ReflectType())
+	c.guard(c.config.ReflectType(), "Identity")
+	configureIdentity(&c.config.ident, name, key, c.config.ReflectType(), inModule(c.config))
 }
 
 func (c *integrationConfigurer) Routes(routes ...dogma.IntegrationRoute) {
+	c.guard(c.config.ReflectType(), "Routes")
 	configureRoutes(&c.config.types, routes, c.config.ident, c.config.ReflectType())
 }
 
 func (c *integrationConfigurer) Disable(...dogma.DisableOption) {
+	c.guard(c.config.ReflectType(), "Disable")
 	c.config.isDisabled = true
 }
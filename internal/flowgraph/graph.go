@@ -0,0 +1,125 @@
+// Package flowgraph builds graphs that describe the flow of messages between
+// the handlers of one or more applications.
+package flowgraph
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/dogmatiq/configkit"
+	"github.com/dogmatiq/enginekit/message"
+)
+
+// Graph is a directed graph of handlers and the messages that flow between
+// them.
+//
+// The order of the nodes and edges, and the node IDs, are deterministic for
+// any given set of applications.
+type Graph struct {
+	Applications []Application
+	Messages     []MessageNode
+	Edges        []Edge
+}
+
+// Application is a group of handler nodes that belong to the same
+// application.
+type Application struct {
+	ID       string
+	Identity configkit.Identity
+	TypeName string
+	Handlers []HandlerNode
+}
+
+// HandlerNode is a node that represents a handler.
+type HandlerNode struct {
+	ID      string
+	Handler configkit.Handler
+}
+
+// MessageNode is a node that represents a message.
+type MessageNode struct {
+	ID    string
+	Name  message.Name
+	Usage configkit.MessageUsage
+}
+
+// Edge is a directed edge between a handler node and a message node.
+//
+// An edge from a handler to a message indicates that the handler produces the
+// message. An edge from a message to a handler indicates that the handler
+// consumes the message.
+type Edge struct {
+	From, To string
+	Kind     message.Kind
+}
+
+// New returns the graph of the given applications.
+func New(apps ...configkit.Application) Graph {
+	var g Graph
+
+	apps = sortedApplications(apps)
+	handlerIDs := map[handlerKey]string{}
+
+	for i, app := range apps {
+		a := Application{
+			ID:       fmt.Sprintf("a%d", i+1),
+			Identity: app.Identity(),
+			TypeName: app.TypeName(),
+		}
+
+		for h := range app.Handlers().Sorted() {
+			id := fmt.Sprintf("h%d", len(handlerIDs)+1)
+			handlerIDs[handlerKey{app.Identity(), h.Identity()}] = id
+			a.Handlers = append(a.Handlers, HandlerNode{id, h})
+		}
+
+		g.Applications = append(g.Applications, a)
+	}
+
+	catalog := configkit.NewMessageCatalog(apps...)
+
+	for n, u := range catalog.Sorted() {
+		id := fmt.Sprintf("m%d", len(g.Messages)+1)
+		g.Messages = append(g.Messages, MessageNode{id, n, u})
+
+		for _, x := range u.Producers {
+			g.Edges = append(g.Edges, Edge{
+				From: handlerIDs[handlerKey{x.Application, x.Handler.Identity()}],
+				To:   id,
+				Kind: u.Kind,
+			})
+		}
+
+		for _, x := range u.Consumers {
+			g.Edges = append(g.Edges, Edge{
+				From: id,
+				To:   handlerIDs[handlerKey{x.Application, x.Handler.Identity()}],
+				Kind: u.Kind,
+			})
+		}
+	}
+
+	return g
+}
+
+// handlerKey uniquely identifies a handler across several applications.
+type handlerKey struct {
+	Application configkit.Identity
+	Handler     configkit.Identity
+}
+
+// sortedApplications returns a copy of apps, ordered by name, then by key.
+func sortedApplications(apps []configkit.Application) []configkit.Application {
+	apps = slices.Clone(apps)
+
+	slices.SortFunc(apps, func(a, b configkit.Application) int {
+		x, y := a.Identity(), b.Identity()
+		if c := strings.Compare(x.Name, y.Name); c != 0 {
+			return c
+		}
+		return strings.Compare(x.Key, y.Key)
+	})
+
+	return apps
+}
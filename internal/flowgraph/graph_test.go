@@ -0,0 +1,167 @@
+package flowgraph_test
+
+import (
+	"strings"
+
+	"github.com/dogmatiq/configkit"
+	. "github.com/dogmatiq/configkit/internal/flowgraph"
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	"github.com/dogmatiq/enginekit/message"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("type Graph", func() {
+	var app1, app2 configkit.Application
+
+	BeforeEach(func() {
+		app1 = configkit.FromApplication(&ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app-1>", "59a82a24-a181-41e8-9b93-17a6ce86956e")
+				c.Routes(
+					dogma.ViaAggregate(&AggregateMessageHandlerStub{
+						ConfigureFunc: func(c dogma.AggregateConfigurer) {
+							c.Identity("<aggregate>", "14769f7f-87fe-48dd-916e-5bcab6ba6aca")
+							c.Routes(
+								dogma.HandlesCommand[*CommandStub[TypeA]](),
+								dogma.RecordsEvent[*EventStub[TypeA]](),
+							)
+						},
+					}),
+				)
+			},
+		})
+
+		app2 = configkit.FromApplication(&ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app-2>", "e28f056e-e5a0-4ee7-aaf1-1d1fe02fb6e3")
+				c.Routes(
+					dogma.ViaProjection(&ProjectionMessageHandlerStub{
+						ConfigureFunc: func(c dogma.ProjectionConfigurer) {
+							c.Identity("<projection>", "70fdf7fa-4b24-448d-bd29-7ecc71d18c56")
+							c.Routes(
+								dogma.HandlesEvent[*EventStub[TypeA]](),
+							)
+							c.Disable()
+						},
+					}),
+				)
+			},
+		})
+	})
+
+	Describe("func New()", func() {
+		It("assigns deterministic IDs to the nodes", func() {
+			g := New(app2, app1)
+
+			Expect(g.Applications).To(HaveLen(2))
+			Expect(g.Applications[0].ID).To(Equal("a1"))
+			Expect(g.Applications[0].Identity.Name).To(Equal("<app-1>"))
+			Expect(g.Applications[0].Handlers[0].ID).To(Equal("h1"))
+			Expect(g.Applications[1].ID).To(Equal("a2"))
+			Expect(g.Applications[1].Handlers[0].ID).To(Equal("h2"))
+
+			Expect(g.Messages).To(HaveLen(2))
+			Expect(g.Messages[0].ID).To(Equal("m1"))
+			Expect(g.Messages[0].Name).To(Equal(message.NameFor[*CommandStub[TypeA]]()))
+			Expect(g.Messages[1].ID).To(Equal("m2"))
+			Expect(g.Messages[1].Name).To(Equal(message.NameFor[*EventStub[TypeA]]()))
+		})
+
+		It("adds edges from producers to messages and from messages to consumers", func() {
+			g := New(app1, app2)
+
+			Expect(g.Edges).To(Equal([]Edge{
+				{From: "m1", To: "h1", Kind: message.CommandKind},
+				{From: "h1", To: "m2", Kind: message.EventKind},
+				{From: "m2", To: "h2", Kind: message.EventKind},
+			}))
+		})
+	})
+
+	Describe("func WriteMermaid()", func() {
+		It("writes a flowchart with a subgraph for each application", func() {
+			var w strings.Builder
+			New(app1, app2).WriteMermaid(
+				&w,
+				func(n string) string {
+					return strings.TrimPrefix(n, "*github.com/dogmatiq/enginekit/enginetest/")
+				},
+			)
+
+			expected := "flowchart LR\n"
+			expected += "    subgraph a1 [\"#lt;app-1#gt;\"]\n"
+			expected += "        h1[\"#lt;aggregate#gt;\"]\n"
+			expected += "    end\n"
+			expected += "    subgraph a2 [\"#lt;app-2#gt;\"]\n"
+			expected += "        h2[(\"#lt;projection#gt;\")]\n"
+			expected += "    end\n"
+			expected += "    m1>\"stubs.CommandStub[github.com/dogmatiq/enginekit/enginetest/stubs.TypeA]\"]\n"
+			expected += "    m2{{\"stubs.EventStub[github.com/dogmatiq/enginekit/enginetest/stubs.TypeA]\"}}\n"
+			expected += "    m1 --> h1\n"
+			expected += "    h1 --> m2\n"
+			expected += "    m2 --> h2\n"
+			expected += "    classDef disabled stroke-dasharray: 5 5\n"
+			expected += "    class h2 disabled\n"
+
+			Expect(
+				strings.Split(w.String(), "\n"),
+			).To(
+				Equal(strings.Split(expected, "\n")),
+			)
+		})
+	})
+
+	Describe("func WriteSVG()", func() {
+		It("places each node in a column according to its distance from the start of the flow", func() {
+			var w strings.Builder
+			New(app1, app2).WriteSVG(
+				&w,
+				func(n string) string { return "<message>" },
+			)
+
+			svg := w.String()
+
+			Expect(svg).To(HavePrefix(`<svg xmlns="http://www.w3.org/2000/svg" class="flowgraph"`))
+			Expect(svg).To(HaveSuffix("</svg>\n"))
+
+			// m1 (the command) is the only source node, so it is in the first
+			// column, followed by the aggregate, the event, and finally the
+			// projection.
+			Expect(svg).To(ContainSubstring(`<g class="node message command" data-id="m1"><title>command *github.com/dogmatiq/enginekit/enginetest/stubs.CommandStub[github.com/dogmatiq/enginekit/enginetest/stubs.TypeA]</title><rect x="16" y="16" width="87" height="32"/><text x="28" y="36">&lt;message&gt;</text></g>`))
+			Expect(svg).To(ContainSubstring(`<g class="node handler aggregate" data-id="h1"><title>aggregate &lt;aggregate&gt; in &lt;app-1&gt;</title><rect x="167" y="16"`))
+			Expect(svg).To(ContainSubstring(`<g class="node message event" data-id="m2"><title>event *github.com/dogmatiq/enginekit/enginetest/stubs.EventStub[github.com/dogmatiq/enginekit/enginetest/stubs.TypeA]</title><rect x="332" y="16"`))
+			Expect(svg).To(ContainSubstring(`<g class="node handler projection disabled" data-id="h2"><title>projection &lt;projection&gt; in &lt;app-2&gt;</title><rect x="483" y="16"`))
+			Expect(svg).To(ContainSubstring(`<path class="edge command" data-from="m1" data-to="h1" d="M 103 32 C 135 32, 135 32, 167 32" marker-end="url(#arrow)"/>`))
+		})
+
+		It("draws edges that form a cycle below the nodes", func() {
+			app := configkit.FromApplication(&ApplicationStub{
+				ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+					c.Identity("<app>", "59a82a24-a181-41e8-9b93-17a6ce86956e")
+					c.Routes(
+						dogma.ViaProcess(&ProcessMessageHandlerStub{
+							ConfigureFunc: func(c dogma.ProcessConfigurer) {
+								c.Identity("<process>", "bea52cf4-e403-4b18-819d-88ade7836308")
+								c.Routes(
+									dogma.HandlesEvent[*EventStub[TypeA]](),
+									dogma.ExecutesCommand[*CommandStub[TypeA]](),
+									dogma.SchedulesTimeout[*TimeoutStub[TypeA]](),
+								)
+							},
+						}),
+					)
+				},
+			})
+
+			var w strings.Builder
+			New(app).WriteSVG(
+				&w,
+				func(n string) string { return "<message>" },
+			)
+
+			Expect(w.String()).To(ContainSubstring(`<path class="edge timeout" data-from="m3" data-to="h1" d="M 361 96 C 361 128, 210 128, 210 48" marker-end="url(#arrow)"/>`))
+		})
+	})
+})
@@ -0,0 +1,106 @@
+package flowgraph
+
+// nodeIDs returns the IDs of all nodes in g, handlers first, in the order in
+// which they appear in the graph.
+func (g Graph) nodeIDs() []string {
+	var ids []string
+
+	for _, a := range g.Applications {
+		for _, n := range a.Handlers {
+			ids = append(ids, n.ID)
+		}
+	}
+
+	for _, n := range g.Messages {
+		ids = append(ids, n.ID)
+	}
+
+	return ids
+}
+
+// ranks assigns each node to a layer such that, ignoring the edges that form
+// cycles, every edge points from a lower layer to a higher one.
+//
+// The assignment depends only on the order of the nodes and edges, which is
+// itself deterministic.
+func (g Graph) ranks() map[string]int {
+	ids := g.nodeIDs()
+
+	out := map[string][]string{}
+	for _, e := range g.Edges {
+		out[e.From] = append(out[e.From], e.To)
+	}
+
+	// Identify the edges that close a cycle by performing a depth-first
+	// search, so that they can be ignored when assigning layers.
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := map[string]int{}
+	back := map[[2]string]bool{}
+
+	var visit func(string)
+	visit = func(id string) {
+		state[id] = visiting
+
+		for _, to := range out[id] {
+			switch state[to] {
+			case unvisited:
+				visit(to)
+			case visiting:
+				back[[2]string{id, to}] = true
+			}
+		}
+
+		state[id] = visited
+	}
+
+	for _, id := range ids {
+		if state[id] == unvisited {
+			visit(id)
+		}
+	}
+
+	// Assign layers using the longest path from any source node, visiting the
+	// nodes in topological order.
+	in := map[string]int{}
+	for _, e := range g.Edges {
+		if !back[[2]string{e.From, e.To}] {
+			in[e.To]++
+		}
+	}
+
+	var queue []string
+	for _, id := range ids {
+		if in[id] == 0 {
+			queue = append(queue, id)
+		}
+	}
+
+	ranks := map[string]int{}
+
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+
+		for _, to := range out[id] {
+			if back[[2]string{id, to}] {
+				continue
+			}
+
+			if r := ranks[id] + 1; r > ranks[to] {
+				ranks[to] = r
+			}
+
+			in[to]--
+			if in[to] == 0 {
+				queue = append(queue, to)
+			}
+		}
+	}
+
+	return ranks
+}
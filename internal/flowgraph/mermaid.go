@@ -0,0 +1,122 @@
+package flowgraph
+
+import (
+	"io"
+	"strings"
+
+	"github.com/dogmatiq/configkit"
+	"github.com/dogmatiq/enginekit/message"
+	"github.com/dogmatiq/iago/must"
+)
+
+// WriteMermaid writes a Mermaid flowchart of g to w.
+//
+// render is used to render the names of messages. The handlers of each
+// application are grouped into a subgraph when there is more than one
+// application.
+func (g Graph) WriteMermaid(w io.Writer, render func(string) string) {
+	must.WriteString(w, "flowchart LR\n")
+
+	var disabled []string
+	multi := len(g.Applications) > 1
+
+	for _, a := range g.Applications {
+		indent := "    "
+
+		if multi {
+			must.Fprintf(w, "    subgraph %s [%s]\n", a.ID, mermaidLabel(a.Identity.Name))
+			indent += "    "
+		}
+
+		for _, n := range a.Handlers {
+			open, close := mermaidHandlerShape(n.Handler.HandlerType())
+
+			must.Fprintf(
+				w,
+				"%s%s%s%s%s\n",
+				indent,
+				n.ID,
+				open,
+				mermaidLabel(n.Handler.Identity().Name),
+				close,
+			)
+
+			if n.Handler.IsDisabled() {
+				disabled = append(disabled, n.ID)
+			}
+		}
+
+		if multi {
+			must.WriteString(w, "    end\n")
+		}
+	}
+
+	for _, n := range g.Messages {
+		open, close := mermaidMessageShape(n.Usage.Kind)
+
+		must.Fprintf(
+			w,
+			"    %s%s%s%s\n",
+			n.ID,
+			open,
+			mermaidLabel(render(string(n.Name))),
+			close,
+		)
+	}
+
+	for _, e := range g.Edges {
+		arrow := "-->"
+		if e.Kind == message.TimeoutKind {
+			arrow = "-.->"
+		}
+
+		must.Fprintf(w, "    %s %s %s\n", e.From, arrow, e.To)
+	}
+
+	if len(disabled) > 0 {
+		must.WriteString(w, "    classDef disabled stroke-dasharray: 5 5\n")
+		must.Fprintf(w, "    class %s disabled\n", strings.Join(disabled, ","))
+	}
+}
+
+// mermaidHandlerShape returns the delimiters of the node shape used to
+// represent handlers of type t.
+func mermaidHandlerShape(t configkit.HandlerType) (open, close string) {
+	switch t {
+	case configkit.AggregateHandlerType:
+		return "[", "]"
+	case configkit.ProcessHandlerType:
+		return "([", "])"
+	case configkit.IntegrationHandlerType:
+		return "[[", "]]"
+	default: // configkit.ProjectionHandlerType
+		return "[(", ")]"
+	}
+}
+
+// mermaidMessageShape returns the delimiters of the node shape used to
+// represent messages of kind k.
+func mermaidMessageShape(k message.Kind) (open, close string) {
+	shape := message.MapByKind(
+		k,
+		[2]string{">", "]"},
+		[2]string{"{{", "}}"},
+		[2]string{"[/", "/]"},
+	)
+
+	return shape[0], shape[1]
+}
+
+// mermaidLabel returns a quoted Mermaid label containing s.
+func mermaidLabel(s string) string {
+	return `"` + mermaidEscaper.Replace(s) + `"`
+}
+
+// mermaidEscaper replaces characters that have special meaning within Mermaid
+// labels with their entity codes.
+var mermaidEscaper = strings.NewReplacer(
+	"#", "#35;",
+	`"`, "#quot;",
+	"<", "#lt;",
+	">", "#gt;",
+)
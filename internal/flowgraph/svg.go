@@ -0,0 +1,210 @@
+package flowgraph
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"unicode/utf8"
+
+	"github.com/dogmatiq/iago/must"
+)
+
+// Dimensions of the elements within the SVG diagram, in pixels.
+const (
+	svgNodeHeight    = 32
+	svgNodePadding   = 12
+	svgCharWidth     = 7
+	svgColumnGap     = 64
+	svgRowGap        = 16
+	svgMargin        = 16
+	svgLabelBaseline = 20
+	svgBackEdgeDepth = 32
+)
+
+// svgNode is a node positioned within the SVG diagram.
+type svgNode struct {
+	ID      string
+	Label   string
+	Title   string
+	Class   string
+	X, Y, W int
+}
+
+// WriteSVG writes an SVG diagram of g to w.
+//
+// render is used to render the names of messages. Each node links to the
+// fragment identifier that matches its node ID, such that the diagram can be
+// embedded within a document that describes each handler and message.
+//
+// The diagram does not require any external resources.
+func (g Graph) WriteSVG(w io.Writer, render func(string) string) {
+	nodes := g.svgNodes(render)
+	width, height := layoutSVGNodes(nodes, g.ranks())
+
+	index := map[string]*svgNode{}
+	for i := range nodes {
+		index[nodes[i].ID] = &nodes[i]
+	}
+
+	must.Fprintf(
+		w,
+		`<svg xmlns="http://www.w3.org/2000/svg" class="flowgraph" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		width, height, width, height,
+	)
+
+	must.WriteString(w, svgPreamble)
+
+	for _, e := range g.Edges {
+		must.Fprintf(
+			w,
+			`<path class="edge %s" data-from="%s" data-to="%s" d="%s" marker-end="url(#arrow)"/>`+"\n",
+			e.Kind,
+			e.From,
+			e.To,
+			svgEdgePath(index[e.From], index[e.To]),
+		)
+	}
+
+	for _, n := range nodes {
+		must.Fprintf(
+			w,
+			`<a href="#%s"><g class="node %s" data-id="%s"><title>%s</title><rect x="%d" y="%d" width="%d" height="%d"/><text x="%d" y="%d">%s</text></g></a>`+"\n",
+			n.ID,
+			n.Class,
+			n.ID,
+			html.EscapeString(n.Title),
+			n.X, n.Y, n.W, svgNodeHeight,
+			n.X+svgNodePadding, n.Y+svgLabelBaseline,
+			html.EscapeString(n.Label),
+		)
+	}
+
+	must.WriteString(w, "</svg>\n")
+}
+
+// svgEdgePath returns the SVG path data for an edge between two nodes.
+//
+// Edges that point to a node in a later column are drawn from the right side
+// of one node to the left side of the other. Edges that point backwards, which
+// only occur within cycles, are drawn as arcs below the nodes.
+func svgEdgePath(from, to *svgNode) string {
+	if to.X > from.X {
+		x1, y1 := from.X+from.W, from.Y+svgNodeHeight/2
+		x2, y2 := to.X, to.Y+svgNodeHeight/2
+
+		return fmt.Sprintf(
+			"M %d %d C %d %d, %d %d, %d %d",
+			x1, y1,
+			x1+svgColumnGap/2, y1,
+			x2-svgColumnGap/2, y2,
+			x2, y2,
+		)
+	}
+
+	x1, y1 := from.X+from.W/2, from.Y+svgNodeHeight
+	x2, y2 := to.X+to.W/2, to.Y+svgNodeHeight
+	below := max(y1, y2) + svgBackEdgeDepth
+
+	return fmt.Sprintf(
+		"M %d %d C %d %d, %d %d, %d %d",
+		x1, y1,
+		x1, below,
+		x2, below,
+		x2, y2,
+	)
+}
+
+// svgNodes returns the nodes of g, in the order returned by nodeIDs(), without
+// any position information.
+func (g Graph) svgNodes(render func(string) string) []svgNode {
+	var nodes []svgNode
+
+	for _, a := range g.Applications {
+		for _, n := range a.Handlers {
+			class := "handler " + n.Handler.HandlerType().String()
+			if n.Handler.IsDisabled() {
+				class += " disabled"
+			}
+
+			id := n.Handler.Identity()
+
+			nodes = append(nodes, svgNode{
+				ID:    n.ID,
+				Label: id.Name,
+				Title: n.Handler.HandlerType().String() + " " + id.Name + " in " + a.Identity.Name,
+				Class: class,
+			})
+		}
+	}
+
+	for _, n := range g.Messages {
+		nodes = append(nodes, svgNode{
+			ID:    n.ID,
+			Label: render(string(n.Name)),
+			Title: n.Usage.Kind.String() + " " + string(n.Name),
+			Class: "message " + n.Usage.Kind.String(),
+		})
+	}
+
+	return nodes
+}
+
+// layoutSVGNodes positions each node in the column that corresponds to its
+// rank, and returns the dimensions of the diagram.
+func layoutSVGNodes(nodes []svgNode, ranks map[string]int) (width, height int) {
+	var columns [][]*svgNode
+
+	for i := range nodes {
+		r := ranks[nodes[i].ID]
+		for len(columns) <= r {
+			columns = append(columns, nil)
+		}
+		columns[r] = append(columns[r], &nodes[i])
+	}
+
+	x := svgMargin
+
+	for _, col := range columns {
+		w := 0
+		for _, n := range col {
+			w = max(w, utf8.RuneCountInString(n.Label)*svgCharWidth+2*svgNodePadding)
+		}
+
+		y := svgMargin
+
+		for _, n := range col {
+			n.X, n.Y, n.W = x, y, w
+			y += svgNodeHeight + svgRowGap
+		}
+
+		height = max(height, y-svgRowGap+svgBackEdgeDepth+svgMargin)
+		x += w + svgColumnGap
+	}
+
+	width = x - svgColumnGap + svgMargin
+	if len(columns) == 0 {
+		width, height = 2*svgMargin, 2*svgMargin
+	}
+
+	return width, height
+}
+
+// svgPreamble contains the definitions and styles used by the SVG diagram.
+const svgPreamble = `<defs><marker id="arrow" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="8" markerHeight="8" orient="auto-start-reverse"><path d="M 0 0 L 10 5 L 0 10 z"/></marker></defs>
+<style>
+.flowgraph text { font: 12px monospace; fill: #222; }
+.flowgraph rect { stroke: #555; stroke-width: 1; }
+.flowgraph .edge { fill: none; stroke: #888; stroke-width: 1.5; }
+.flowgraph .edge.timeout { stroke-dasharray: 4 3; }
+.flowgraph .handler rect { fill: #dbe9f6; }
+.flowgraph .handler.process rect { rx: 16; }
+.flowgraph .handler.integration rect { fill: #e4dcf3; }
+.flowgraph .handler.projection rect { fill: #dcf0e0; }
+.flowgraph .handler.disabled rect { stroke-dasharray: 5 5; opacity: 0.6; }
+.flowgraph .message rect { fill: #fdf3d8; rx: 4; }
+.flowgraph .message.event rect { fill: #fbe2d5; }
+.flowgraph .message.timeout rect { fill: #eeeeee; }
+.flowgraph .highlight rect { stroke: #d33; stroke-width: 2.5; }
+.flowgraph .dim { opacity: 0.25; }
+</style>
+`
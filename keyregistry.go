@@ -0,0 +1,255 @@
+package configkit
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/dogmatiq/configkit/internal/validation"
+	"github.com/dogmatiq/enginekit/protobuf/uuidpb"
+)
+
+// GenerateKey returns a deterministic identity key derived from the given
+// namespace and name.
+//
+// The namespace must be an RFC 4122 UUID, typically the key of the application
+// that contains the entity. The returned key is a version 5 (SHA-1 based)
+// UUID, so the same namespace and name always produce the same key.
+//
+// It returns a non-nil error if the namespace or name is invalid.
+func GenerateKey(namespace, name string) (string, error) {
+	ns, err := uuidpb.Parse(namespace)
+	if err != nil {
+		return "", validation.Errorf(
+			"invalid namespace %#v, namespaces must be RFC 4122 UUIDs",
+			namespace,
+		)
+	}
+
+	if err := ValidateIdentityName(name); err != nil {
+		return "", err
+	}
+
+	return uuidpb.Derive(ns, name).AsString(), nil
+}
+
+// MustGenerateKey returns a deterministic identity key derived from the given
+// namespace and name.
+//
+// It panics if the namespace or name is invalid.
+func MustGenerateKey(namespace, name string) string {
+	k, err := GenerateKey(namespace, name)
+	if err != nil {
+		panic(err)
+	}
+
+	return k
+}
+
+// KeyUsage describes the use of an identity key by a single entity.
+type KeyUsage struct {
+	// Application is the identity of the application that contains the
+	// entity.
+	Application Identity `json:"application"`
+
+	// Identity is the identity of the entity itself. If the entity is the
+	// application, it is equal to Application.
+	Identity Identity `json:"identity"`
+
+	// HandlerType is the type of the handler that uses the key. It is empty if
+	// the entity is the application.
+	HandlerType HandlerType `json:"handler_type,omitempty"`
+
+	// TypeName is the fully-qualified name of the Go type that implements the
+	// entity.
+	TypeName string `json:"type_name"`
+}
+
+// KeyConflict describes an identity key that is used within more than one
+// application.
+type KeyConflict struct {
+	Key    string
+	Usages []KeyUsage
+}
+
+func (c KeyConflict) Error() string {
+	var names []string
+	for _, u := range c.Usages {
+		names = append(names, u.Application.Name+"/"+u.Identity.Name)
+	}
+
+	return fmt.Sprintf(
+		"the key %s is used by entities in more than one application: %s",
+		c.Key,
+		strings.Join(names, ", "),
+	)
+}
+
+// KeyRegistry records the identity keys used by a set of applications and
+// their handlers.
+//
+// The zero-value is an empty registry, ready to use.
+type KeyRegistry struct {
+	usages map[string][]KeyUsage
+}
+
+// Add records the keys used by app and its handlers.
+func (r *KeyRegistry) Add(app Application) {
+	appIdent := app.Identity()
+
+	r.add(KeyUsage{
+		Application: appIdent,
+		Identity:    appIdent,
+		TypeName:    app.TypeName(),
+	})
+
+	for _, h := range app.Handlers() {
+		r.add(KeyUsage{
+			Application: appIdent,
+			Identity:    h.Identity(),
+			HandlerType: h.HandlerType(),
+			TypeName:    h.TypeName(),
+		})
+	}
+}
+
+// Merge records all of the key usages in o.
+//
+// It is typically used to combine a snapshot of the keys that are already
+// deployed with those of the applications that are about to be deployed. A nil
+// registry is treated as an empty registry.
+func (r *KeyRegistry) Merge(o *KeyRegistry) {
+	if o == nil {
+		return
+	}
+
+	for _, usages := range o.usages {
+		for _, u := range usages {
+			r.add(u)
+		}
+	}
+}
+
+// Keys returns the keys in the registry, in lexical order.
+func (r *KeyRegistry) Keys() []string {
+	keys := make([]string, 0, len(r.usages))
+	for k := range r.usages {
+		keys = append(keys, k)
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
+
+// Usages returns the recorded usages of the given key.
+func (r *KeyRegistry) Usages(k string) []KeyUsage {
+	return slices.Clone(r.usages[k])
+}
+
+// Conflicts returns the keys that are used within more than one application.
+//
+// The conflicts are returned in lexical order of their keys.
+func (r *KeyRegistry) Conflicts() []KeyConflict {
+	var conflicts []KeyConflict
+
+	for _, k := range r.Keys() {
+		usages := r.usages[k]
+
+		for _, u := range usages[1:] {
+			if u.Application.Key != usages[0].Application.Key {
+				conflicts = append(
+					conflicts,
+					KeyConflict{k, slices.Clone(usages)},
+				)
+				break
+			}
+		}
+	}
+
+	return conflicts
+}
+
+// keyRegistryEntry is the JSON representation of the usages of a single key.
+type keyRegistryEntry struct {
+	Key    string     `json:"key"`
+	Usages []KeyUsage `json:"usages"`
+}
+
+// MarshalJSON returns a JSON representation of the registry.
+//
+// The output is deterministic, making it suitable for committing to version
+// control or comparing with the registry of a previous deployment.
+func (r *KeyRegistry) MarshalJSON() ([]byte, error) {
+	entries := []keyRegistryEntry{}
+
+	for _, k := range r.Keys() {
+		entries = append(entries, keyRegistryEntry{k, r.usages[k]})
+	}
+
+	return json.Marshal(entries)
+}
+
+// UnmarshalJSON replaces the contents of the registry with that of its JSON
+// representation.
+func (r *KeyRegistry) UnmarshalJSON(data []byte) error {
+	var entries []keyRegistryEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return err
+	}
+
+	r.usages = nil
+
+	for _, e := range entries {
+		for _, u := range e.Usages {
+			if u.Identity.Key != e.Key {
+				return fmt.Errorf(
+					"usage of %s is recorded under the key %s",
+					u.Identity,
+					e.Key,
+				)
+			}
+
+			r.add(u)
+		}
+	}
+
+	return nil
+}
+
+// add records a single key usage, ignoring exact duplicates.
+func (r *KeyRegistry) add(u KeyUsage) {
+	k := u.Identity.Key
+	usages := r.usages[k]
+
+	if slices.Contains(usages, u) {
+		return
+	}
+
+	usages = append(usages, u)
+	slices.SortFunc(usages, compareKeyUsages)
+
+	if r.usages == nil {
+		r.usages = map[string][]KeyUsage{}
+	}
+	r.usages[k] = usages
+}
+
+// compareKeyUsages orders key usages by application key, then handler type,
+// then entity name.
+func compareKeyUsages(a, b KeyUsage) int {
+	if c := strings.Compare(a.Application.Key, b.Application.Key); c != 0 {
+		return c
+	}
+
+	if c := strings.Compare(string(a.HandlerType), string(b.HandlerType)); c != 0 {
+		return c
+	}
+
+	if c := strings.Compare(a.Identity.Name, b.Identity.Name); c != 0 {
+		return c
+	}
+
+	return strings.Compare(a.TypeName, b.TypeName)
+}
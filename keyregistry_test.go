@@ -0,0 +1,261 @@
+package configkit_test
+
+import (
+	"encoding/json"
+
+	. "github.com/dogmatiq/configkit"
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("func GenerateKey()", func() {
+	It("returns a deterministic key", func() {
+		a, err := GenerateKey(appKey, "<name>")
+		Expect(err).ShouldNot(HaveOccurred())
+
+		b, err := GenerateKey(appKey, "<name>")
+		Expect(err).ShouldNot(HaveOccurred())
+
+		Expect(a).To(Equal(b))
+		Expect(ValidateIdentityKey(a)).To(Succeed())
+	})
+
+	It("returns a version 5 UUID", func() {
+		k := MustGenerateKey(appKey, "<name>")
+		Expect(k[14]).To(Equal(byte('5')))
+	})
+
+	It("returns different keys for different names", func() {
+		Expect(MustGenerateKey(appKey, "<name-1>")).NotTo(Equal(MustGenerateKey(appKey, "<name-2>")))
+	})
+
+	It("returns different keys for different namespaces", func() {
+		Expect(MustGenerateKey(appKey, "<name>")).NotTo(Equal(MustGenerateKey(aggregateKey, "<name>")))
+	})
+
+	It("returns an error if the namespace is invalid", func() {
+		_, err := GenerateKey("<namespace>", "<name>")
+		Expect(err).To(MatchError(`invalid namespace "<namespace>", namespaces must be RFC 4122 UUIDs`))
+	})
+
+	It("returns an error if the name is invalid", func() {
+		_, err := GenerateKey(appKey, "")
+		Expect(err).Should(HaveOccurred())
+	})
+})
+
+var _ = Describe("func MustGenerateKey()", func() {
+	It("panics if the namespace is invalid", func() {
+		Expect(func() {
+			MustGenerateKey("<namespace>", "<name>")
+		}).To(Panic())
+	})
+})
+
+var _ = Describe("type KeyRegistry", func() {
+	var (
+		app1, app2 Application
+		registry   *KeyRegistry
+	)
+
+	BeforeEach(func() {
+		app1 = FromApplication(&ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app-1>", appKey)
+				c.Routes(
+					dogma.ViaAggregate(&AggregateMessageHandlerStub{
+						ConfigureFunc: func(c dogma.AggregateConfigurer) {
+							c.Identity("<aggregate>", aggregateKey)
+							c.Routes(
+								dogma.HandlesCommand[*CommandStub[TypeA]](),
+								dogma.RecordsEvent[*EventStub[TypeA]](),
+							)
+						},
+					}),
+				)
+			},
+		})
+
+		app2 = FromApplication(&ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app-2>", processKey)
+				c.Routes(
+					dogma.ViaProjection(&ProjectionMessageHandlerStub{
+						ConfigureFunc: func(c dogma.ProjectionConfigurer) {
+							c.Identity("<projection>", projectionKey)
+							c.Routes(
+								dogma.HandlesEvent[*EventStub[TypeA]](),
+							)
+						},
+					}),
+				)
+			},
+		})
+
+		registry = &KeyRegistry{}
+	})
+
+	Describe("func Add()", func() {
+		It("records the keys of the application and its handlers", func() {
+			registry.Add(app1)
+
+			Expect(registry.Keys()).To(ConsistOf(appKey, aggregateKey))
+			Expect(registry.Usages(aggregateKey)).To(ConsistOf(
+				KeyUsage{
+					Application: app1.Identity(),
+					Identity:    MustNewIdentity("<aggregate>", aggregateKey),
+					HandlerType: AggregateHandlerType,
+					TypeName:    "*github.com/dogmatiq/enginekit/enginetest/stubs.AggregateMessageHandlerStub",
+				},
+			))
+		})
+
+		It("does not record duplicate usages", func() {
+			registry.Add(app1)
+			registry.Add(app1)
+
+			Expect(registry.Usages(appKey)).To(HaveLen(1))
+			Expect(registry.Conflicts()).To(BeEmpty())
+		})
+	})
+
+	Describe("func Keys()", func() {
+		It("returns the keys in lexical order", func() {
+			registry.Add(app1)
+			registry.Add(app2)
+
+			Expect(registry.Keys()).To(Equal([]string{
+				aggregateKey,
+				appKey,
+				projectionKey,
+				processKey,
+			}))
+		})
+	})
+
+	Describe("func Conflicts()", func() {
+		It("returns nil if there are no conflicts", func() {
+			registry.Add(app1)
+			registry.Add(app2)
+
+			Expect(registry.Conflicts()).To(BeEmpty())
+		})
+
+		It("returns keys that are used in more than one application", func() {
+			reuse := FromApplication(&ApplicationStub{
+				ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+					c.Identity("<app-3>", integrationKey)
+					c.Routes(
+						dogma.ViaProjection(&ProjectionMessageHandlerStub{
+							ConfigureFunc: func(c dogma.ProjectionConfigurer) {
+								c.Identity("<copy-pasted>", aggregateKey)
+								c.Routes(
+									dogma.HandlesEvent[*EventStub[TypeA]](),
+								)
+							},
+						}),
+					)
+				},
+			})
+
+			registry.Add(app1)
+			registry.Add(reuse)
+
+			conflicts := registry.Conflicts()
+			Expect(conflicts).To(HaveLen(1))
+			Expect(conflicts[0].Key).To(Equal(aggregateKey))
+			Expect(conflicts[0].Usages).To(HaveLen(2))
+			Expect(conflicts[0].Error()).To(Equal(
+				"the key 14769f7f-87fe-48dd-916e-5bcab6ba6aca is used by entities in more than one application: <app-1>/<aggregate>, <app-3>/<copy-pasted>",
+			))
+		})
+
+		It("does not report keys that are shared by renamed entities within the same application", func() {
+			renamed := FromApplication(&ApplicationStub{
+				ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+					c.Identity("<app-1-renamed>", appKey)
+				},
+			})
+
+			registry.Add(app1)
+			registry.Add(renamed)
+
+			Expect(registry.Usages(appKey)).To(HaveLen(2))
+			Expect(registry.Conflicts()).To(BeEmpty())
+		})
+	})
+
+	Describe("func Merge()", func() {
+		It("detects conflicts with the merged registry", func() {
+			deployed := &KeyRegistry{}
+			deployed.Add(app1)
+
+			reuse := FromApplication(&ApplicationStub{
+				ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+					c.Identity("<app-3>", aggregateKey)
+				},
+			})
+
+			registry.Add(reuse)
+			registry.Merge(deployed)
+
+			Expect(registry.Conflicts()).To(HaveLen(1))
+		})
+
+		It("treats a nil registry as empty", func() {
+			registry.Add(app1)
+			registry.Merge(nil)
+
+			Expect(registry.Keys()).To(ConsistOf(appKey, aggregateKey))
+		})
+	})
+
+	Describe("func MarshalJSON() and UnmarshalJSON()", func() {
+		It("round-trips the registry", func() {
+			registry.Add(app1)
+			registry.Add(app2)
+
+			data, err := json.Marshal(registry)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			var r KeyRegistry
+			err = json.Unmarshal(data, &r)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Expect(r.Keys()).To(Equal(registry.Keys()))
+			for _, k := range r.Keys() {
+				Expect(r.Usages(k)).To(Equal(registry.Usages(k)))
+			}
+		})
+
+		It("produces deterministic output", func() {
+			other := &KeyRegistry{}
+
+			registry.Add(app1)
+			registry.Add(app2)
+			other.Add(app2)
+			other.Add(app1)
+
+			a, err := json.Marshal(registry)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			b, err := json.Marshal(other)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Expect(a).To(Equal(b))
+		})
+
+		It("returns an error if a usage is recorded under the wrong key", func() {
+			var r KeyRegistry
+			err := json.Unmarshal(
+				[]byte(`[{"key":"`+appKey+`","usages":[{"application":"<app> `+appKey+`","identity":"<agg> `+aggregateKey+`","type_name":"T"}]}]`),
+				&r,
+			)
+			Expect(err).To(MatchError(
+				"usage of <agg>/14769f7f-87fe-48dd-916e-5bcab6ba6aca is recorded under the key 59a82a24-a181-41e8-9b93-17a6ce86956e",
+			))
+		})
+	})
+})
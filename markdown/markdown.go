@@ -0,0 +1,360 @@
+// Package markdown generates Markdown documentation from application
+// configurations.
+//
+// The generated documents are deterministic, such that they may be committed
+// to version control and checked for freshness by regenerating them.
+package markdown
+
+import (
+	"fmt"
+	"io"
+	"iter"
+	"strings"
+
+	"github.com/dogmatiq/configkit"
+	"github.com/dogmatiq/configkit/internal/flowgraph"
+	"github.com/dogmatiq/enginekit/message"
+	"github.com/dogmatiq/iago/must"
+)
+
+// Write writes a Markdown document that describes app to w.
+//
+// The options control how type names are rendered within the document.
+func Write(
+	w io.Writer,
+	app configkit.Application,
+	options ...configkit.RenderOption,
+) (err error) {
+	defer must.Recover(&err)
+
+	names := configkit.NewTypeNameRenderer(options...)
+	names.AddEntity(app)
+
+	catalog := configkit.NewMessageCatalog(app)
+
+	g := &generator{
+		w:       w,
+		app:     app,
+		names:   names,
+		catalog: catalog,
+		anchors: messageAnchors(catalog, names),
+	}
+
+	g.writeOverview()
+	g.writeHandlerTable()
+	g.writeFlow()
+	g.writeHandlerDetails()
+	g.writeMessageIndex()
+
+	return nil
+}
+
+// generator writes the sections of a single Markdown document.
+type generator struct {
+	w       io.Writer
+	app     configkit.Application
+	names   *configkit.TypeNameRenderer
+	catalog configkit.MessageCatalog[message.Name]
+	anchors map[message.Name]string
+}
+
+func (g *generator) writeOverview() {
+	id := g.app.Identity()
+
+	must.Fprintf(g.w, "# %s\n\n", escape(id.Name))
+	must.WriteString(g.w, "<!-- This document is generated from the application's configuration. DO NOT EDIT. -->\n\n")
+	must.WriteString(g.w, "## Overview\n\n")
+	must.WriteString(g.w, "| Property | Value |\n")
+	must.WriteString(g.w, "| --- | --- |\n")
+	must.Fprintf(g.w, "| Name | %s |\n", code(id.Name))
+	must.Fprintf(g.w, "| Key | %s |\n", code(id.Key))
+	must.Fprintf(g.w, "| Go type | %s |\n", code(g.names.Render(g.app.TypeName())))
+	must.Fprintf(g.w, "| Handlers | %s |\n", g.handlerCounts())
+	must.Fprintf(g.w, "| Messages | %s |\n", g.messageCounts())
+}
+
+func (g *generator) handlerCounts() string {
+	var counts []string
+
+	for _, t := range configkit.HandlerTypes {
+		if n := len(g.app.Handlers().ByType(t)); n != 0 {
+			counts = append(counts, plural(n, t.String(), pluralHandlerType(t)))
+		}
+	}
+
+	if len(counts) == 0 {
+		return "none"
+	}
+
+	return strings.Join(counts, ", ")
+}
+
+func (g *generator) messageCounts() string {
+	var commands, events, timeouts int
+
+	for _, u := range g.catalog {
+		message.SwitchByKind(
+			u.Kind,
+			func() { commands++ },
+			func() { events++ },
+			func() { timeouts++ },
+		)
+	}
+
+	var counts []string
+
+	if commands != 0 {
+		counts = append(counts, plural(commands, "command", "commands"))
+	}
+
+	if events != 0 {
+		counts = append(counts, plural(events, "event", "events"))
+	}
+
+	if timeouts != 0 {
+		counts = append(counts, plural(timeouts, "timeout", "timeouts"))
+	}
+
+	if len(counts) == 0 {
+		return "none"
+	}
+
+	return strings.Join(counts, ", ")
+}
+
+func (g *generator) writeHandlerTable() {
+	must.WriteString(g.w, "\n## Handlers\n\n")
+
+	if len(g.app.Handlers()) == 0 {
+		must.WriteString(g.w, "This application has no handlers.\n")
+		return
+	}
+
+	must.WriteString(g.w, "| Type | Name | Key | Go type |\n")
+	must.WriteString(g.w, "| --- | --- | --- | --- |\n")
+
+	for h := range g.app.Handlers().Sorted() {
+		id := h.Identity()
+
+		name := handlerLink(h)
+		if h.IsDisabled() {
+			name += " (disabled)"
+		}
+
+		must.Fprintf(
+			g.w,
+			"| %s | %s | %s | %s |\n",
+			h.HandlerType(),
+			name,
+			code(id.Key),
+			code(g.names.Render(h.TypeName())),
+		)
+	}
+}
+
+func (g *generator) writeFlow() {
+	if len(g.catalog) == 0 {
+		return
+	}
+
+	must.WriteString(g.w, "\n## Message flow\n\n")
+	must.WriteString(g.w, "```mermaid\n")
+	flowgraph.New(g.app).WriteMermaid(g.w, g.names.Render)
+	must.WriteString(g.w, "```\n")
+}
+
+func (g *generator) writeHandlerDetails() {
+	for h := range g.app.Handlers().Sorted() {
+		id := h.Identity()
+
+		must.Fprintf(g.w, "\n<a id=\"%s\"></a>\n\n", handlerAnchor(h))
+		must.Fprintf(g.w, "## %s %s\n\n", capitalize(h.HandlerType().String()), code(id.Name))
+		must.Fprintf(g.w, "- **Key:** %s\n", code(id.Key))
+		must.Fprintf(g.w, "- **Go type:** %s\n", code(g.names.Render(h.TypeName())))
+
+		if h.IsDisabled() {
+			must.WriteString(g.w, "- **Status:** disabled\n")
+		}
+
+		names := h.MessageNames()
+
+		g.writeMessageList(
+			"Handles",
+			names.SortedConsumed(message.CommandKind, message.EventKind),
+		)
+
+		for _, k := range []message.Kind{message.CommandKind, message.EventKind, message.TimeoutKind} {
+			g.writeMessageList(
+				message.MapByKind(k, "Executes", "Records", "Schedules"),
+				names.SortedProduced(k),
+			)
+		}
+	}
+}
+
+func (g *generator) writeMessageList(
+	heading string,
+	messages iter.Seq2[message.Name, message.Kind],
+) {
+	first := true
+
+	for n, k := range messages {
+		if first {
+			must.Fprintf(g.w, "\n### %s\n\n", heading)
+			first = false
+		}
+
+		must.Fprintf(g.w, "- %s %s\n", g.messageLink(n), k)
+	}
+}
+
+func (g *generator) writeMessageIndex() {
+	if len(g.catalog) == 0 {
+		return
+	}
+
+	must.WriteString(g.w, "\n## Messages\n\n")
+	must.WriteString(g.w, "| Message | Kind | Produced by | Consumed by |\n")
+	must.WriteString(g.w, "| --- | --- | --- | --- |\n")
+
+	for n, u := range g.catalog.Sorted() {
+		must.Fprintf(
+			g.w,
+			"| <a id=\"%s\"></a>%s | %s | %s | %s |\n",
+			g.anchors[n],
+			code(g.names.Render(string(n))),
+			u.Kind,
+			handlerLinks(u.Producers),
+			handlerLinks(u.Consumers),
+		)
+	}
+}
+
+func (g *generator) messageLink(n message.Name) string {
+	return fmt.Sprintf(
+		"[%s](#%s)",
+		code(g.names.Render(string(n))),
+		g.anchors[n],
+	)
+}
+
+func handlerLink(h configkit.Handler) string {
+	return fmt.Sprintf(
+		"[%s](#%s)",
+		code(h.Identity().Name),
+		handlerAnchor(h),
+	)
+}
+
+func handlerLinks(handlers []configkit.ApplicationHandler) string {
+	if len(handlers) == 0 {
+		return "—"
+	}
+
+	var links []string
+	for _, x := range handlers {
+		links = append(links, handlerLink(x.Handler))
+	}
+
+	return strings.Join(links, ", ")
+}
+
+func handlerAnchor(h configkit.Handler) string {
+	return "handler-" + slug(h.Identity().Key)
+}
+
+// messageAnchors returns a unique anchor for each message in c, based on its
+// rendered name.
+func messageAnchors(
+	c configkit.MessageCatalog[message.Name],
+	names *configkit.TypeNameRenderer,
+) map[message.Name]string {
+	anchors := map[message.Name]string{}
+	used := map[string]bool{}
+
+	for n := range c.Sorted() {
+		base := "message-" + slug(names.Render(string(n)))
+		anchor := base
+
+		for i := 2; used[anchor]; i++ {
+			anchor = fmt.Sprintf("%s-%d", base, i)
+		}
+
+		used[anchor] = true
+		anchors[n] = anchor
+	}
+
+	return anchors
+}
+
+// slug returns a representation of s that contains only lowercase letters,
+// digits and hyphens.
+func slug(s string) string {
+	var w strings.Builder
+	hyphen := false
+
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if hyphen && w.Len() != 0 {
+				w.WriteByte('-')
+			}
+			w.WriteRune(r)
+			hyphen = false
+		} else {
+			hyphen = true
+		}
+	}
+
+	return w.String()
+}
+
+// code returns s as a Markdown code span that is safe to use within a table.
+func code(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+
+	if strings.Contains(s, "`") {
+		return "`` " + s + " ``"
+	}
+
+	return "`" + s + "`"
+}
+
+// escape returns s with characters that have special meaning in Markdown
+// escaped.
+func escape(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	"*", `\*`,
+	"_", `\_`,
+	"[", `\[`,
+	"]", `\]`,
+	"<", `\<`,
+	">", `\>`,
+	"#", `\#`,
+	"|", `\|`,
+)
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+func plural(n int, singular, plural string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, singular)
+	}
+	return fmt.Sprintf("%d %s", n, plural)
+}
+
+func pluralHandlerType(t configkit.HandlerType) string {
+	if t == configkit.ProcessHandlerType {
+		return "processes"
+	}
+	return t.String() + "s"
+}
@@ -0,0 +1,128 @@
+package markdown_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/dogmatiq/configkit"
+	. "github.com/dogmatiq/configkit/markdown"
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("func Write()", func() {
+	var app configkit.Application
+
+	BeforeEach(func() {
+		app = configkit.FromApplication(&ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app>", "59a82a24-a181-41e8-9b93-17a6ce86956e")
+
+				c.Routes(
+					dogma.ViaAggregate(&AggregateMessageHandlerStub{
+						ConfigureFunc: func(c dogma.AggregateConfigurer) {
+							c.Identity("<aggregate>", "14769f7f-87fe-48dd-916e-5bcab6ba6aca")
+							c.Routes(
+								dogma.HandlesCommand[*CommandStub[TypeA]](),
+								dogma.RecordsEvent[*EventStub[TypeA]](),
+							)
+						},
+					}),
+					dogma.ViaProcess(&ProcessMessageHandlerStub{
+						ConfigureFunc: func(c dogma.ProcessConfigurer) {
+							c.Identity("<process>", "bea52cf4-e403-4b18-819d-88ade7836308")
+							c.Routes(
+								dogma.HandlesEvent[*EventStub[TypeA]](),
+								dogma.ExecutesCommand[*CommandStub[TypeA]](),
+								dogma.SchedulesTimeout[*TimeoutStub[TypeA]](),
+							)
+						},
+					}),
+					dogma.ViaIntegration(&IntegrationMessageHandlerStub{
+						ConfigureFunc: func(c dogma.IntegrationConfigurer) {
+							c.Identity("<integration>", "e28f056e-e5a0-4ee7-aaf1-1d1fe02fb6e3")
+							c.Routes(
+								dogma.HandlesCommand[*CommandStub[TypeB]](),
+								dogma.RecordsEvent[*EventStub[TypeB]](),
+							)
+						},
+					}),
+					dogma.ViaProjection(&ProjectionMessageHandlerStub{
+						ConfigureFunc: func(c dogma.ProjectionConfigurer) {
+							c.Identity("<projection>", "70fdf7fa-4b24-448d-bd29-7ecc71d18c56")
+							c.Routes(
+								dogma.HandlesEvent[*EventStub[TypeA]](),
+								dogma.HandlesEvent[*EventStub[TypeB]](),
+							)
+							c.Disable()
+						},
+					}),
+				)
+			},
+		})
+	})
+
+	It("writes a Markdown document that describes the application", func() {
+		var buf bytes.Buffer
+		err := Write(
+			&buf,
+			app,
+			configkit.WithPackageNames(),
+			configkit.WithShortTypeArgs(),
+		)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		expected, err := os.ReadFile("testdata/application.md")
+		Expect(err).ShouldNot(HaveOccurred())
+
+		// compare as slices, as the failure output for slices is better than
+		// for multiline strings :(
+		Expect(
+			strings.Split(buf.String(), "\n"),
+		).To(
+			Equal(strings.Split(string(expected), "\n")),
+		)
+	})
+
+	It("produces the same output each time it is called", func() {
+		var a, b bytes.Buffer
+
+		err := Write(&a, app)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		err = Write(&b, app)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		Expect(a.String()).To(Equal(b.String()))
+	})
+
+	It("describes an application without any handlers", func() {
+		app := configkit.FromApplication(&ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app>", "59a82a24-a181-41e8-9b93-17a6ce86956e")
+			},
+		})
+
+		var buf bytes.Buffer
+		err := Write(&buf, app)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(buf.String()).To(ContainSubstring("| Handlers | none |\n"))
+		Expect(buf.String()).To(ContainSubstring("This application has no handlers.\n"))
+		Expect(buf.String()).NotTo(ContainSubstring("mermaid"))
+	})
+
+	It("returns an error if the writer fails", func() {
+		err := Write(failingWriter{}, app)
+		Expect(err).To(MatchError("<error>"))
+	})
+})
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("<error>")
+}
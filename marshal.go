@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/dogmatiq/enginekit/message"
 	"github.com/dogmatiq/enginekit/protobuf/configpb"
@@ -13,6 +14,11 @@ import (
 
 // ToProto converts an application configuration to its protocol buffers
 // representation.
+//
+// The configpb schema is defined by enginekit and has no field for metadata, so
+// the result does not include the metadata of the application or its handlers. Use
+// [CollectMetadata] to obtain it separately, and the [WithMetadata] option to
+// attach it when calling [FromProto].
 func ToProto(app Application) (*configpb.Application, error) {
 	out := &configpb.Application{}
 
@@ -52,8 +58,15 @@ func ToProto(app Application) (*configpb.Application, error) {
 
 // FromProto converts an application configuration from its protocol buffers
 // representation.
-func FromProto(app *configpb.Application) (Application, error) {
+//
+// The returned configuration is immutable and safe for concurrent use. It does
+// not retain any reference to app.
+//
+// The [WithMetadata] option attaches metadata to the application and its
+// handlers. All other options are ignored.
+func FromProto(app *configpb.Application, options ...ConfigureOption) (Application, error) {
 	out := &unmarshaledApplication{}
+	opts := newConfigureOptions(options)
 
 	var err error
 	out.ident, err = unmarshalIdentity(app.GetIdentity())
@@ -61,6 +74,8 @@ func FromProto(app *configpb.Application) (Application, error) {
 		return nil, err
 	}
 
+	out.metadata = opts.metadata.Get(out.ident).Clone()
+
 	out.typeName = app.GetGoType()
 	if out.typeName == "" {
 		return nil, errors.New("application type name is empty")
@@ -78,15 +93,17 @@ func FromProto(app *configpb.Application) (Application, error) {
 	}
 
 	for _, h := range app.GetHandlers() {
-		handlerOut, err := unmarshalHandler(h, kinds)
+		handlerOut, err := unmarshalHandler(h, kinds, opts.metadata)
 		if err != nil {
 			return nil, err
 		}
 
-		if out.handlers == nil {
-			out.handlers = HandlerSet{}
+		if !out.handlers.add(handlerOut) {
+			return nil, fmt.Errorf(
+				"handler %s conflicts with the identity of another handler",
+				handlerOut.Identity(),
+			)
 		}
-		out.handlers.Add(handlerOut)
 	}
 
 	return out, nil
@@ -148,6 +165,7 @@ func marshalHandler(in Handler) (*configpb.Handler, error) {
 func unmarshalHandler(
 	in *configpb.Handler,
 	kinds map[message.Name]message.Kind,
+	metadata MetadataSet,
 ) (Handler, error) {
 	out := &unmarshaledHandler{
 		isDisabled: in.GetIsDisabled(),
@@ -159,6 +177,8 @@ func unmarshalHandler(
 		return nil, err
 	}
 
+	out.metadata = metadata.Get(out.ident).Clone()
+
 	out.typeName = in.GetGoType()
 	if out.typeName == "" {
 		return nil, errors.New("handler type name is empty")
@@ -296,7 +316,14 @@ func unmarshalMessageKind(r configpb.MessageKind) (message.Kind, error) {
 type unmarshaledApplication struct {
 	ident    Identity
 	typeName string
-	handlers HandlerSet
+	metadata Metadata
+	handlers HandlerIndex[Handler]
+
+	// names is the union of the messages used by the handlers. It is
+	// computed the first time it is needed, as handlers are added to the
+	// application after it is constructed.
+	namesOnce sync.Once
+	names     EntityMessages[message.Name]
 }
 
 func (a *unmarshaledApplication) Identity() Identity {
@@ -304,13 +331,19 @@ func (a *unmarshaledApplication) Identity() Identity {
 }
 
 func (a *unmarshaledApplication) MessageNames() EntityMessages[message.Name] {
-	names := EntityMessages[message.Name]{}
+	a.namesOnce.Do(func() {
+		a.names = EntityMessages[message.Name]{}
 
-	for _, h := range a.handlers {
-		names.merge(h.MessageNames())
-	}
+		for _, h := range a.handlers.handlers {
+			a.names.merge(h.MessageNames())
+		}
+	})
+
+	return a.names
+}
 
-	return names
+func (a *unmarshaledApplication) Metadata() Metadata {
+	return a.metadata
 }
 
 func (a *unmarshaledApplication) TypeName() string {
@@ -322,7 +355,11 @@ func (a *unmarshaledApplication) AcceptVisitor(ctx context.Context, v Visitor) e
 }
 
 func (a *unmarshaledApplication) Handlers() HandlerSet {
-	return a.handlers
+	return HandlerSet(a.handlers.handlers)
+}
+
+func (a *unmarshaledApplication) handlerIndex() *HandlerIndex[Handler] {
+	return &a.handlers
 }
 
 // unmarshaledHandler is an implementation of [Handler] that has been produced
@@ -330,6 +367,7 @@ func (a *unmarshaledApplication) Handlers() HandlerSet {
 type unmarshaledHandler struct {
 	ident       Identity
 	names       EntityMessages[message.Name]
+	metadata    Metadata
 	typeName    string
 	handlerType HandlerType
 	isDisabled  bool
@@ -345,6 +383,11 @@ func (h *unmarshaledHandler) MessageNames() EntityMessages[message.Name] {
 	return h.names
 }
 
+// Metadata returns the metadata attached to the entity.
+func (h *unmarshaledHandler) Metadata() Metadata {
+	return h.metadata
+}
+
 // TypeName returns the fully-qualified type name of the entity.
 func (h *unmarshaledHandler) TypeName() string {
 	return h.typeName
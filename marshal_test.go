@@ -19,31 +19,32 @@ var _ = Describe("func ToProto()", func() {
 		app = &unmarshaledApplication{
 			ident:    MustNewIdentity("<app>", "28c19ec0-a32f-4479-bb1d-02887e90077c"),
 			typeName: "<app type>",
-			handlers: HandlerSet{
-				MustNewIdentity("<handler>", "3c73fa07-1073-4cf3-a208-644e26b747d7"): &unmarshaledHandler{
-					ident: MustNewIdentity("<handler>", "3c73fa07-1073-4cf3-a208-644e26b747d7"),
-					names: EntityMessages[message.Name]{
-						message.NameOf(CommandA1): {
-							Kind:       message.CommandKind,
-							IsProduced: true,
-						},
-						message.NameOf(EventA1): {
-							Kind:       message.EventKind,
-							IsConsumed: true,
-						},
-					},
-					typeName:    "<handler type>",
-					handlerType: IntegrationHandlerType,
+			metadata: Metadata{OwnerMetadataKey: "<team>"},
+		}
+
+		app.handlers.add(&unmarshaledHandler{
+			ident: MustNewIdentity("<handler>", "3c73fa07-1073-4cf3-a208-644e26b747d7"),
+			names: EntityMessages[message.Name]{
+				message.NameOf(CommandA1): {
+					Kind:       message.CommandKind,
+					IsProduced: true,
+				},
+				message.NameOf(EventA1): {
+					Kind:       message.EventKind,
+					IsConsumed: true,
 				},
 			},
-		}
+			metadata:    Metadata{DataClassificationMetadataKey: "pii"},
+			typeName:    "<handler type>",
+			handlerType: IntegrationHandlerType,
+		})
 	})
 
 	It("produces a value that can be unmarshaled to an equivalent application", func() {
 		marshaled, err := ToProto(app)
 		Expect(err).ShouldNot(HaveOccurred())
 
-		unmarshaled, err := FromProto(marshaled)
+		unmarshaled, err := FromProto(marshaled, WithMetadata(CollectMetadata(app)))
 		Expect(err).ShouldNot(HaveOccurred())
 
 		Expect(ToString(unmarshaled)).To(Equal(ToString(app)))
@@ -51,6 +52,36 @@ var _ = Describe("func ToProto()", func() {
 		Expect(IsApplicationEqual(unmarshaled, app)).To(BeTrue())
 	})
 
+	It("does not include metadata", func() {
+		marshaled, err := ToProto(app)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		unmarshaled, err := FromProto(marshaled)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		Expect(unmarshaled.Metadata()).To(BeEmpty())
+
+		for _, h := range unmarshaled.Handlers() {
+			Expect(h.Metadata()).To(BeEmpty())
+		}
+	})
+
+	It("produces a value that can be unmarshaled to an immutable application", func() {
+		marshaled, err := ToProto(app)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		unmarshaled, err := FromProto(marshaled, WithMetadata(CollectMetadata(app)))
+		Expect(err).ShouldNot(HaveOccurred())
+
+		handlers := unmarshaled.Handlers().Clone()
+		for id, h := range handlers {
+			clear(h.MessageNames().Clone())
+			delete(handlers, id)
+		}
+
+		Expect(IsApplicationEqual(unmarshaled, app)).To(BeTrue())
+	})
+
 	It("returns an error if the identity is invalid", func() {
 		app.ident.Name = ""
 		_, err := ToProto(app)
@@ -64,7 +95,7 @@ var _ = Describe("func ToProto()", func() {
 	})
 
 	It("returns an error if one of the handlers is invalid", func() {
-		app.handlers.Add(&unmarshaledHandler{})
+		app.handlers.add(&unmarshaledHandler{})
 		_, err := ToProto(app)
 		Expect(err).Should(HaveOccurred())
 	})
@@ -100,6 +131,49 @@ var _ = Describe("func FromProto()", func() {
 		_, err := FromProto(app)
 		Expect(err).Should(HaveOccurred())
 	})
+
+	It("attaches the metadata given by the WithMetadata() option", func() {
+		handlerKey := uuidpb.Generate()
+		app.Handlers = append(app.Handlers, &configpb.Handler{
+			Identity: &identitypb.Identity{
+				Name: "<handler>",
+				Key:  handlerKey,
+			},
+			GoType: "<handler type>",
+			Type:   configpb.HandlerType_INTEGRATION,
+		})
+
+		unmarshaled, err := FromProto(app, WithMetadata(MetadataSet{
+			app.GetIdentity().GetKey().AsString(): {OwnerMetadataKey: "<team>"},
+			handlerKey.AsString():                 {DataClassificationMetadataKey: "pii"},
+		}))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(unmarshaled.Metadata()).To(Equal(Metadata{OwnerMetadataKey: "<team>"}))
+
+		h, ok := unmarshaled.Handlers().ByKey(handlerKey.AsString())
+		Expect(ok).To(BeTrue())
+		Expect(h.Metadata()).To(Equal(Metadata{DataClassificationMetadataKey: "pii"}))
+	})
+
+	It("returns an error if the handler identities conflict", func() {
+		key := uuidpb.Generate()
+
+		for _, name := range []string{"<handler-1>", "<handler-2>"} {
+			app.Handlers = append(app.Handlers, &configpb.Handler{
+				Identity: &identitypb.Identity{
+					Name: name,
+					Key:  key,
+				},
+				GoType: "<handler type>",
+				Type:   configpb.HandlerType_PROJECTION,
+			})
+		}
+
+		_, err := FromProto(app)
+		Expect(err).To(MatchError(
+			"handler <handler-2>/" + key.AsString() + " conflicts with the identity of another handler",
+		))
+	})
 })
 
 var _ = Describe("func marshalHandler()", func() {
@@ -159,19 +233,19 @@ var _ = Describe("func unmarshalHandler()", func() {
 
 	It("returns an error if the identity is invalid", func() {
 		handler.Identity.Name = ""
-		_, err := unmarshalHandler(handler, nil)
+		_, err := unmarshalHandler(handler, nil, nil)
 		Expect(err).Should(HaveOccurred())
 	})
 
 	It("returns an error if the type name is empty", func() {
 		handler.GoType = ""
-		_, err := unmarshalHandler(handler, nil)
+		_, err := unmarshalHandler(handler, nil, nil)
 		Expect(err).Should(HaveOccurred())
 	})
 
 	It("returns an error if the handler type is invalid", func() {
 		handler.Type = configpb.HandlerType_UNKNOWN_HANDLER_TYPE
-		_, err := unmarshalHandler(handler, nil)
+		_, err := unmarshalHandler(handler, nil, nil)
 		Expect(err).Should(HaveOccurred())
 	})
 
@@ -179,7 +253,7 @@ var _ = Describe("func unmarshalHandler()", func() {
 		handler.Messages = map[string]*configpb.MessageUsage{
 			"": {},
 		}
-		_, err := unmarshalHandler(handler, nil)
+		_, err := unmarshalHandler(handler, nil, nil)
 		Expect(err).Should(HaveOccurred())
 	})
 
@@ -187,7 +261,7 @@ var _ = Describe("func unmarshalHandler()", func() {
 		handler.Messages = map[string]*configpb.MessageUsage{
 			"pkg.Command": {},
 		}
-		_, err := unmarshalHandler(handler, nil)
+		_, err := unmarshalHandler(handler, nil, nil)
 		Expect(err).Should(HaveOccurred())
 	})
 })
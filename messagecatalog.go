@@ -0,0 +1,245 @@
+package configkit
+
+import (
+	"io"
+	"iter"
+	"slices"
+	"strings"
+
+	"github.com/dogmatiq/enginekit/message"
+	"github.com/dogmatiq/iago/indent"
+	"github.com/dogmatiq/iago/must"
+)
+
+// MessageCatalog is a message-centric view of the configuration of one or more
+// applications.
+//
+// Whereas [Entity.MessageNames] describes the messages used by a single
+// entity, a catalog describes the entities that use each message.
+type MessageCatalog[K comparable] map[K]MessageUsage
+
+// MessageUsage describes how a single message is used across one or more
+// applications.
+type MessageUsage struct {
+	// Kind is the kind of the message.
+	Kind message.Kind
+
+	// Producers is the list of handlers that produce the message, ordered by
+	// handler type, then by name.
+	Producers []ApplicationHandler
+
+	// Consumers is the list of handlers that consume the message, ordered by
+	// handler type, then by name.
+	Consumers []ApplicationHandler
+
+	// Applications is the list of applications that produce or consume the
+	// message, ordered by name.
+	Applications []Identity
+
+	// IsScheduled is true if the message is a timeout that is scheduled by at
+	// least one handler.
+	IsScheduled bool
+}
+
+// ApplicationHandler is a handler within a specific application.
+type ApplicationHandler struct {
+	Application Identity
+	Handler     Handler
+}
+
+// Owner returns the handler that "owns" the message.
+//
+// The owner of a command is the handler that handles it. The owner of an event
+// is the handler that records it. The owner of a timeout is the process that
+// schedules it.
+//
+// It returns false if there is no owner, or if there is more than one
+// candidate, such as when the message is used by several applications.
+func (u MessageUsage) Owner() (ApplicationHandler, bool) {
+	candidates := u.Producers
+	if u.Kind == message.CommandKind {
+		candidates = u.Consumers
+	}
+
+	if len(candidates) == 1 {
+		return candidates[0], true
+	}
+
+	return ApplicationHandler{}, false
+}
+
+// IsCrossApplication returns true if the message is produced or consumed by
+// more than one application.
+func (u MessageUsage) IsCrossApplication() bool {
+	return len(u.Applications) > 1
+}
+
+// NewMessageCatalog returns a catalog of the messages used by the given
+// applications, keyed by message name.
+func NewMessageCatalog(apps ...Application) MessageCatalog[message.Name] {
+	c := MessageCatalog[message.Name]{}
+
+	for _, app := range apps {
+		for _, h := range app.Handlers() {
+			c.add(app.Identity(), h, h.MessageNames())
+		}
+	}
+
+	c.sort()
+
+	return c
+}
+
+// NewRichMessageCatalog returns a catalog of the messages used by the given
+// applications, keyed by message type.
+func NewRichMessageCatalog(apps ...RichApplication) MessageCatalog[message.Type] {
+	c := MessageCatalog[message.Type]{}
+
+	for _, app := range apps {
+		for _, h := range app.RichHandlers() {
+			c.add(app.Identity(), h, h.MessageTypes())
+		}
+	}
+
+	c.sort()
+
+	return c
+}
+
+// Sorted returns an iterator that yields the messages in the catalog in a
+// deterministic order.
+//
+// Messages are ordered by name, except that timeout messages are always
+// yielded after all other messages.
+func (c MessageCatalog[K]) Sorted() iter.Seq2[K, MessageUsage] {
+	return func(yield func(K, MessageUsage) bool) {
+		kinds := func(yield func(K, message.Kind) bool) {
+			for k, u := range c {
+				if !yield(k, u.Kind) {
+					return
+				}
+			}
+		}
+
+		for k := range sortedMessages(kinds) {
+			if !yield(k, c[k]) {
+				return
+			}
+		}
+	}
+}
+
+// String returns a human-readable representation of the catalog.
+func (c MessageCatalog[K]) String() string {
+	var w strings.Builder
+
+	for k, u := range c.Sorted() {
+		if w.Len() != 0 {
+			must.WriteByte(&w, '\n')
+		}
+
+		must.Fprintf(
+			&w,
+			"%s %s%s\n",
+			u.Kind,
+			messageKeyName(k),
+			u.Kind.Symbol(),
+		)
+
+		iw := indent.NewIndenter(&w, nil)
+
+		if u.Kind != message.TimeoutKind {
+			for _, x := range u.Consumers {
+				writeApplicationHandler(iw, "handled", x)
+			}
+		}
+
+		for _, x := range u.Producers {
+			writeApplicationHandler(
+				iw,
+				message.MapByKind(u.Kind, "executed", "recorded", "scheduled"),
+				x,
+			)
+		}
+	}
+
+	return w.String()
+}
+
+func writeApplicationHandler(w io.Writer, verb string, x ApplicationHandler) {
+	id := x.Handler.Identity()
+
+	var flags string
+	if x.Handler.IsDisabled() {
+		flags = " [disabled]"
+	}
+
+	must.Fprintf(
+		w,
+		"%s by %s %s (%s) in %s%s\n",
+		verb,
+		x.Handler.HandlerType(),
+		id.Name,
+		id.Key,
+		x.Application.Name,
+		flags,
+	)
+}
+
+// add adds the messages used by h to the catalog.
+func (c MessageCatalog[K]) add(
+	app Identity,
+	h Handler,
+	messages EntityMessages[K],
+) {
+	x := ApplicationHandler{app, h}
+
+	for k, em := range messages {
+		u := c[k]
+		u.Kind = em.Kind
+
+		if em.IsProduced {
+			u.Producers = append(u.Producers, x)
+
+			if em.Kind == message.TimeoutKind {
+				u.IsScheduled = true
+			}
+		}
+
+		if em.IsConsumed {
+			u.Consumers = append(u.Consumers, x)
+		}
+
+		if !slices.Contains(u.Applications, app) {
+			u.Applications = append(u.Applications, app)
+		}
+
+		c[k] = u
+	}
+}
+
+// sort sorts the handlers and applications associated with each message.
+func (c MessageCatalog[K]) sort() {
+	for _, u := range c {
+		slices.SortFunc(u.Producers, compareApplicationHandlers)
+		slices.SortFunc(u.Consumers, compareApplicationHandlers)
+		slices.SortFunc(u.Applications, compareIdentities)
+	}
+}
+
+// compareApplicationHandlers orders handlers by handler type, then by name,
+// then by the name of their application.
+func compareApplicationHandlers(a, b ApplicationHandler) int {
+	if c := compareHandlers(a.Handler, b.Handler); c != 0 {
+		return c
+	}
+	return compareIdentities(a.Application, b.Application)
+}
+
+// compareIdentities orders identities by name, then by key.
+func compareIdentities(a, b Identity) int {
+	if c := strings.Compare(a.Name, b.Name); c != 0 {
+		return c
+	}
+	return strings.Compare(a.Key, b.Key)
+}
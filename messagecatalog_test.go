@@ -0,0 +1,187 @@
+package configkit_test
+
+import (
+	"strings"
+
+	. "github.com/dogmatiq/configkit"
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	"github.com/dogmatiq/enginekit/message"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("type MessageCatalog", func() {
+	var (
+		app1, app2                      RichApplication
+		aggregate, process, projection  RichHandler
+		catalog                         MessageCatalog[message.Name]
+		richCatalog                     MessageCatalog[message.Type]
+		commandName, eventName, timeout message.Name
+	)
+
+	BeforeEach(func() {
+		app1 = FromApplication(&ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app-1>", appKey)
+				c.Routes(
+					dogma.ViaAggregate(&AggregateMessageHandlerStub{
+						ConfigureFunc: func(c dogma.AggregateConfigurer) {
+							c.Identity("<aggregate>", aggregateKey)
+							c.Routes(
+								dogma.HandlesCommand[*CommandStub[TypeA]](),
+								dogma.RecordsEvent[*EventStub[TypeA]](),
+							)
+						},
+					}),
+					dogma.ViaProcess(&ProcessMessageHandlerStub{
+						ConfigureFunc: func(c dogma.ProcessConfigurer) {
+							c.Identity("<process>", processKey)
+							c.Routes(
+								dogma.HandlesEvent[*EventStub[TypeA]](),
+								dogma.ExecutesCommand[*CommandStub[TypeA]](),
+								dogma.SchedulesTimeout[*TimeoutStub[TypeA]](),
+							)
+						},
+					}),
+				)
+			},
+		})
+
+		app2 = FromApplication(&ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app-2>", integrationKey)
+				c.Routes(
+					dogma.ViaProjection(&ProjectionMessageHandlerStub{
+						ConfigureFunc: func(c dogma.ProjectionConfigurer) {
+							c.Identity("<projection>", projectionKey)
+							c.Routes(
+								dogma.HandlesEvent[*EventStub[TypeA]](),
+							)
+							c.Disable()
+						},
+					}),
+				)
+			},
+		})
+
+		aggregate, _ = app1.RichHandlers().ByName("<aggregate>")
+		process, _ = app1.RichHandlers().ByName("<process>")
+		projection, _ = app2.RichHandlers().ByName("<projection>")
+
+		commandName = message.NameFor[*CommandStub[TypeA]]()
+		eventName = message.NameFor[*EventStub[TypeA]]()
+		timeout = message.NameFor[*TimeoutStub[TypeA]]()
+
+		catalog = NewMessageCatalog(app1, app2)
+		richCatalog = NewRichMessageCatalog(app1, app2)
+	})
+
+	Describe("func NewMessageCatalog()", func() {
+		It("describes the usage of each message", func() {
+			Expect(catalog).To(HaveLen(3))
+
+			Expect(catalog[commandName]).To(Equal(MessageUsage{
+				Kind:         message.CommandKind,
+				Producers:    []ApplicationHandler{{app1.Identity(), process}},
+				Consumers:    []ApplicationHandler{{app1.Identity(), aggregate}},
+				Applications: []Identity{app1.Identity()},
+			}))
+
+			Expect(catalog[eventName]).To(Equal(MessageUsage{
+				Kind:      message.EventKind,
+				Producers: []ApplicationHandler{{app1.Identity(), aggregate}},
+				Consumers: []ApplicationHandler{
+					{app1.Identity(), process},
+					{app2.Identity(), projection},
+				},
+				Applications: []Identity{app1.Identity(), app2.Identity()},
+			}))
+
+			Expect(catalog[timeout]).To(Equal(MessageUsage{
+				Kind:         message.TimeoutKind,
+				Producers:    []ApplicationHandler{{app1.Identity(), process}},
+				Consumers:    []ApplicationHandler{{app1.Identity(), process}},
+				Applications: []Identity{app1.Identity()},
+				IsScheduled:  true,
+			}))
+		})
+	})
+
+	Describe("func NewRichMessageCatalog()", func() {
+		It("keys the messages by type", func() {
+			Expect(richCatalog).To(HaveLen(3))
+
+			u, ok := richCatalog[message.TypeFor[*EventStub[TypeA]]()]
+			Expect(ok).To(BeTrue())
+			Expect(u).To(Equal(catalog[eventName]))
+		})
+	})
+
+	Describe("type MessageUsage", func() {
+		Describe("func Owner()", func() {
+			It("returns the handler that handles a command", func() {
+				x, ok := catalog[commandName].Owner()
+				Expect(ok).To(BeTrue())
+				Expect(x.Handler).To(Equal(aggregate))
+			})
+
+			It("returns the handler that records an event", func() {
+				x, ok := catalog[eventName].Owner()
+				Expect(ok).To(BeTrue())
+				Expect(x.Handler).To(Equal(aggregate))
+			})
+
+			It("returns the handler that schedules a timeout", func() {
+				x, ok := catalog[timeout].Owner()
+				Expect(ok).To(BeTrue())
+				Expect(x.Handler).To(Equal(process))
+			})
+
+			It("returns false if there is no owner", func() {
+				_, ok := MessageUsage{Kind: message.EventKind}.Owner()
+				Expect(ok).To(BeFalse())
+			})
+		})
+
+		Describe("func IsCrossApplication()", func() {
+			It("returns true if the message is used by more than one application", func() {
+				Expect(catalog[eventName].IsCrossApplication()).To(BeTrue())
+				Expect(catalog[commandName].IsCrossApplication()).To(BeFalse())
+			})
+		})
+	})
+
+	Describe("func Sorted()", func() {
+		It("yields messages ordered by name, with timeouts last", func() {
+			var names []message.Name
+			for n := range catalog.Sorted() {
+				names = append(names, n)
+			}
+
+			Expect(names).To(Equal([]message.Name{commandName, eventName, timeout}))
+		})
+	})
+
+	Describe("func String()", func() {
+		It("returns a human readable string representation", func() {
+			expected := "command *github.com/dogmatiq/enginekit/enginetest/stubs.CommandStub[github.com/dogmatiq/enginekit/enginetest/stubs.TypeA]?\n"
+			expected += "    handled by aggregate <aggregate> (14769f7f-87fe-48dd-916e-5bcab6ba6aca) in <app-1>\n"
+			expected += "    executed by process <process> (bea52cf4-e403-4b18-819d-88ade7836308) in <app-1>\n"
+			expected += "\n"
+			expected += "event *github.com/dogmatiq/enginekit/enginetest/stubs.EventStub[github.com/dogmatiq/enginekit/enginetest/stubs.TypeA]!\n"
+			expected += "    handled by process <process> (bea52cf4-e403-4b18-819d-88ade7836308) in <app-1>\n"
+			expected += "    handled by projection <projection> (70fdf7fa-4b24-448d-bd29-7ecc71d18c56) in <app-2> [disabled]\n"
+			expected += "    recorded by aggregate <aggregate> (14769f7f-87fe-48dd-916e-5bcab6ba6aca) in <app-1>\n"
+			expected += "\n"
+			expected += "timeout *github.com/dogmatiq/enginekit/enginetest/stubs.TimeoutStub[github.com/dogmatiq/enginekit/enginetest/stubs.TypeA]@\n"
+			expected += "    scheduled by process <process> (bea52cf4-e403-4b18-819d-88ade7836308) in <app-1>\n"
+
+			Expect(
+				strings.Split(catalog.String(), "\n"),
+			).To(
+				Equal(strings.Split(expected, "\n")),
+			)
+		})
+	})
+})
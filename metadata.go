@@ -0,0 +1,151 @@
+package configkit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Metadata is a set of descriptive key/value pairs attached to an application
+// or handler, such as the team that owns it.
+//
+// Metadata does not affect the behavior of the entity. It is attached to an
+// entity's configuration using the [WithMetadata] option.
+type Metadata map[string]string
+
+// Well-known metadata keys.
+const (
+	// OwnerMetadataKey is the metadata key for the team that owns the entity.
+	OwnerMetadataKey = "owner"
+
+	// OnCallMetadataKey is the metadata key for the on-call rotation that is
+	// responsible for the entity.
+	OnCallMetadataKey = "on-call"
+
+	// BoundedContextMetadataKey is the metadata key for the bounded context
+	// that the entity belongs to.
+	BoundedContextMetadataKey = "bounded-context"
+
+	// DataClassificationMetadataKey is the metadata key for the classification
+	// of the data that the entity handles, such as "pii".
+	DataClassificationMetadataKey = "data-classification"
+)
+
+// IsEqual returns true if m is equal to n.
+func (m Metadata) IsEqual(n Metadata) bool {
+	return maps.Equal(m, n)
+}
+
+// Clone returns a copy of m that can be modified without affecting m.
+func (m Metadata) Clone() Metadata {
+	return maps.Clone(m)
+}
+
+// String returns a human-readable representation of the metadata, ordered by
+// key.
+func (m Metadata) String() string {
+	var w strings.Builder
+
+	for i, k := range slices.Sorted(maps.Keys(m)) {
+		if i > 0 {
+			w.WriteString(", ")
+		}
+		fmt.Fprintf(&w, "%s=%q", k, m[k])
+	}
+
+	return w.String()
+}
+
+// MetadataSet is a collection of [Metadata] for applications and handlers.
+//
+// It is keyed by identity key, rather than by name, such that metadata remains
+// attached to an entity when the entity is renamed.
+type MetadataSet map[string]Metadata
+
+// Get returns the metadata for the entity with the given identity.
+func (s MetadataSet) Get(id Identity) Metadata {
+	return s[id.Key]
+}
+
+// Add adds the key/value pairs in m to the metadata for the entity with the
+// given identity, replacing any existing values with the same keys.
+func (s MetadataSet) Add(id Identity, m Metadata) {
+	s.add(id.Key, m)
+}
+
+func (s MetadataSet) add(k string, m Metadata) {
+	if len(m) == 0 {
+		return
+	}
+
+	x := s[k]
+	if x == nil {
+		x = Metadata{}
+		s[k] = x
+	}
+
+	maps.Copy(x, m)
+}
+
+// CollectMetadata returns the metadata attached to app and its handlers.
+func CollectMetadata(app Application) MetadataSet {
+	s := MetadataSet{}
+
+	s.add(app.Identity().Key, app.Metadata())
+	for _, h := range app.Handlers() {
+		s.add(h.Identity().Key, h.Metadata())
+	}
+
+	return s
+}
+
+// ReadMetadata reads the JSON representation of a [MetadataSet] from r.
+//
+// The JSON representation is an object that maps each entity's identity key to
+// an object containing its metadata.
+func ReadMetadata(r io.Reader) (MetadataSet, error) {
+	var s MetadataSet
+
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return nil, fmt.Errorf("invalid metadata: %w", err)
+	}
+
+	for k, m := range s {
+		if err := ValidateIdentityKey(k); err != nil {
+			return nil, fmt.Errorf("invalid metadata: %w", err)
+		}
+
+		if _, ok := m[""]; ok {
+			return nil, fmt.Errorf("invalid metadata: entity %s has an empty metadata key", k)
+		}
+	}
+
+	if s == nil {
+		return nil, errors.New("invalid metadata: expected a JSON object")
+	}
+
+	return s, nil
+}
+
+// ReadMetadataFile reads the JSON representation of a [MetadataSet] from the
+// file at the given path, which is typically a "sidecar" file that is
+// distributed alongside the application.
+func ReadMetadataFile(path string) (MetadataSet, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	s, err := ReadMetadata(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return s, nil
+}
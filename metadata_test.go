@@ -0,0 +1,179 @@
+package configkit_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/dogmatiq/configkit"
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("type Metadata", func() {
+	Describe("func IsEqual()", func() {
+		It("returns true if the metadata is equal", func() {
+			a := Metadata{OwnerMetadataKey: "<team>"}
+			b := Metadata{OwnerMetadataKey: "<team>"}
+			Expect(a.IsEqual(b)).To(BeTrue())
+		})
+
+		It("treats nil and empty metadata as equal", func() {
+			Expect(Metadata(nil).IsEqual(Metadata{})).To(BeTrue())
+		})
+
+		It("returns false if the metadata is not equal", func() {
+			a := Metadata{OwnerMetadataKey: "<team>"}
+			b := Metadata{OwnerMetadataKey: "<other team>"}
+			Expect(a.IsEqual(b)).To(BeFalse())
+		})
+	})
+
+	Describe("func Clone()", func() {
+		It("returns a copy of the metadata", func() {
+			m := Metadata{OwnerMetadataKey: "<team>"}
+			c := m.Clone()
+			c[OwnerMetadataKey] = "<other team>"
+
+			Expect(m).To(Equal(Metadata{OwnerMetadataKey: "<team>"}))
+		})
+	})
+
+	Describe("func String()", func() {
+		It("returns the key/value pairs ordered by key", func() {
+			m := Metadata{
+				OwnerMetadataKey:          "<team>",
+				BoundedContextMetadataKey: "<context>",
+			}
+			Expect(m.String()).To(Equal(`bounded-context="<context>", owner="<team>"`))
+		})
+	})
+})
+
+var _ = Describe("type MetadataSet", func() {
+	Describe("func Add()", func() {
+		It("merges the metadata with any existing metadata for the entity", func() {
+			id := MustNewIdentity("<app>", appKey)
+
+			s := MetadataSet{}
+			s.Add(id, Metadata{OwnerMetadataKey: "<team>", OnCallMetadataKey: "<rotation>"})
+			s.Add(id, Metadata{OwnerMetadataKey: "<other team>"})
+
+			Expect(s.Get(id)).To(Equal(Metadata{
+				OwnerMetadataKey:  "<other team>",
+				OnCallMetadataKey: "<rotation>",
+			}))
+		})
+
+		It("does not retain a reference to the metadata", func() {
+			id := MustNewIdentity("<app>", appKey)
+			m := Metadata{OwnerMetadataKey: "<team>"}
+
+			s := MetadataSet{}
+			s.Add(id, m)
+			m[OwnerMetadataKey] = "<other team>"
+
+			Expect(s.Get(id)).To(Equal(Metadata{OwnerMetadataKey: "<team>"}))
+		})
+	})
+
+	Describe("func Get()", func() {
+		It("returns nil if there is no metadata for the entity", func() {
+			Expect(MetadataSet{}.Get(MustNewIdentity("<app>", appKey))).To(BeNil())
+		})
+	})
+})
+
+var _ = Describe("func CollectMetadata()", func() {
+	It("returns the metadata of the application and its handlers", func() {
+		set := MetadataSet{
+			appKey:        {OwnerMetadataKey: "<team>"},
+			projectionKey: {DataClassificationMetadataKey: "pii"},
+		}
+
+		app := FromApplication(
+			&ApplicationStub{
+				ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+					c.Identity("<app>", appKey)
+					c.Routes(
+						dogma.ViaProjection(&ProjectionMessageHandlerStub{
+							ConfigureFunc: func(c dogma.ProjectionConfigurer) {
+								c.Identity("<projection>", projectionKey)
+								c.Routes(
+									dogma.HandlesEvent[*EventStub[TypeA]](),
+								)
+							},
+						}),
+					)
+				},
+			},
+			WithMetadata(set),
+		)
+
+		Expect(CollectMetadata(app)).To(Equal(set))
+	})
+})
+
+var _ = Describe("func ReadMetadata()", func() {
+	It("reads a metadata set from its JSON representation", func() {
+		s, err := ReadMetadata(strings.NewReader(`{
+			"` + appKey + `": { "owner": "<team>" }
+		}`))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(s).To(Equal(MetadataSet{
+			appKey: {OwnerMetadataKey: "<team>"},
+		}))
+	})
+
+	It("returns an error if the JSON is not an object", func() {
+		_, err := ReadMetadata(strings.NewReader(`null`))
+		Expect(err).To(MatchError("invalid metadata: expected a JSON object"))
+	})
+
+	It("returns an error if an identity key is invalid", func() {
+		_, err := ReadMetadata(strings.NewReader(`{"<key>": {}}`))
+		Expect(err).To(MatchError(`invalid metadata: invalid key "<key>", keys must be RFC 4122 UUIDs`))
+	})
+
+	It("returns an error if a metadata key is empty", func() {
+		_, err := ReadMetadata(strings.NewReader(`{"` + appKey + `": {"": "<value>"}}`))
+		Expect(err).To(MatchError("invalid metadata: entity " + appKey + " has an empty metadata key"))
+	})
+})
+
+var _ = Describe("func ReadMetadataFile()", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "configkit-metadata-")
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("reads a metadata set from a file", func() {
+		path := filepath.Join(dir, "metadata.json")
+		err := os.WriteFile(path, []byte(`{"`+appKey+`": {"owner": "<team>"}}`), 0o600)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		s, err := ReadMetadataFile(path)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(s).To(Equal(MetadataSet{
+			appKey: {OwnerMetadataKey: "<team>"},
+		}))
+	})
+
+	It("includes the path in errors", func() {
+		path := filepath.Join(dir, "metadata.json")
+		err := os.WriteFile(path, []byte(`{`), 0o600)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = ReadMetadataFile(path)
+		Expect(err).To(MatchError(HavePrefix(path + ": invalid metadata: ")))
+	})
+})
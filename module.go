@@ -0,0 +1,237 @@
+package configkit
+
+import (
+	"reflect"
+	"slices"
+
+	"github.com/dogmatiq/configkit/internal/validation"
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/enginekit/protobuf/uuidpb"
+)
+
+// Module is a named, reusable bundle of handlers that may be included in
+// several applications.
+//
+// Each handler within a module declares its identity as usual, however the key
+// that it declares is local to the module. When the module is included in an
+// application, the handler's key is replaced with the key returned by
+// [Module.HandlerKey], such that the module's handlers have distinct keys in
+// each application. The declared key does not appear anywhere in the
+// application's configuration, so anything that refers to one of the module's
+// handlers by key, such as the [MetadataSet] passed to [WithMetadata], an
+// [Overlay] or a [KeyRegistry], must use the replaced key.
+//
+// The configurations produced by [FromApplication] record which handlers are
+// part of which module, for use in error messages and the output of
+// [ToString]. Module membership is not part of any serialized form of the
+// configuration, such as that produced by [ToProto], so it is not available
+// from snapshots, the history package or the api package. Those sources treat
+// the module's handlers as ordinary handlers that have the replaced keys.
+//
+// A module is included in an application by passing the result of
+// [Module.Routes] to [dogma.ApplicationConfigurer.Routes]. The application's
+// identity must be configured first.
+type Module struct {
+	name      string
+	namespace string
+	routes    []dogma.HandlerRoute
+}
+
+// NewModule returns a module that contains the handlers in routes.
+//
+// The namespace must be an RFC 4122 UUID that is unique to the module. It is
+// used to derive the keys of the module's handlers within each application.
+//
+// It panics if the name or namespace is invalid, or if any of the handlers is
+// already part of another module. Use Recover() to convert the panic value to
+// an error.
+func NewModule(name, namespace string, routes ...dogma.HandlerRoute) *Module {
+	if err := ValidateIdentityName(name); err != nil {
+		validation.Panicf("module is configured with an invalid name, %s", err)
+	}
+
+	if _, err := uuidpb.Parse(namespace); err != nil {
+		validation.Panicf(
+			"the %q module is configured with an invalid namespace %#v, namespaces must be RFC 4122 UUIDs",
+			name,
+			namespace,
+		)
+	}
+
+	m := &Module{
+		name:      name,
+		namespace: namespace,
+	}
+
+	for _, r := range routes {
+		m.routes = append(m.routes, m.include(r))
+	}
+
+	return m
+}
+
+// Name returns the module's name.
+func (m *Module) Name() string {
+	return m.name
+}
+
+// Namespace returns the module's namespace.
+func (m *Module) Namespace() string {
+	return m.namespace
+}
+
+// Routes returns the routes to the module's handlers, for use within an
+// application's Configure() method.
+func (m *Module) Routes() []dogma.HandlerRoute {
+	return slices.Clone(m.routes)
+}
+
+// HandlerKey returns the identity key that a handler within m has when m is
+// included in the application with the key appKey.
+//
+// k is the key that the handler declares within its Configure() method. It
+// panics if appKey or k is not a valid identity key.
+func (m *Module) HandlerKey(appKey, k string) string {
+	if err := ValidateIdentityKey(appKey); err != nil {
+		panic(err)
+	}
+
+	if err := ValidateIdentityKey(k); err != nil {
+		panic(err)
+	}
+
+	return MustGenerateKey(
+		MustGenerateKey(appKey, m.namespace),
+		k,
+	)
+}
+
+// include returns a route that associates the handler in r with m.
+func (m *Module) include(r dogma.HandlerRoute) dogma.HandlerRoute {
+	switch r := r.(type) {
+	case dogma.AggregateHandlerRoute:
+		m.guardAgainstNesting(r.Handler())
+		return dogma.ViaAggregate(&moduleAggregate{r.Handler(), m})
+	case dogma.ProcessHandlerRoute:
+		m.guardAgainstNesting(r.Handler())
+		return dogma.ViaProcess(&moduleProcess{r.Handler(), m})
+	case dogma.IntegrationHandlerRoute:
+		m.guardAgainstNesting(r.Handler())
+		return dogma.ViaIntegration(&moduleIntegration{r.Handler(), m})
+	case dogma.ProjectionHandlerRoute:
+		m.guardAgainstNesting(r.Handler())
+		return dogma.ViaProjection(&moduleProjection{r.Handler(), m})
+	default:
+		validation.Panicf("unsupported route type: %T", r)
+		return nil // unreachable
+	}
+}
+
+// guardAgainstNesting panics if h is already part of a module.
+func (m *Module) guardAgainstNesting(h any) {
+	if x, ok := h.(moduleHandler); ok {
+		inner, owner := x.unwrapModule()
+		validation.Panicf(
+			"the %q module can not include %s, because it is already part of the %q module",
+			m.name,
+			reflect.TypeOf(inner),
+			owner.name,
+		)
+	}
+}
+
+// unwrapModule returns the handler to configure in place of h, and the options
+// to configure it with.
+//
+// If h is part of a [Module], it returns the handler that the module wraps,
+// and options that resolve its identity within the module.
+func unwrapModule[H any](c *applicationConfigurer, h H) (H, configureOptions) {
+	opts := c.options
+
+	x, ok := any(h).(moduleHandler)
+	if !ok {
+		return h, opts
+	}
+
+	inner, m := x.unwrapModule()
+
+	if c.config.ident.IsZero() {
+		validation.Panicf(
+			"%s includes the %q module before its identity is configured, Identity() must be called before Routes()",
+			c.config.ReflectType(),
+			m.name,
+		)
+	}
+
+	opts.module = m
+	opts.appKey = c.config.ident.Key
+
+	return inner.(H), opts
+}
+
+// moduleHandler is an interface for the handlers that associate a Dogma
+// message handler with the [Module] that contains it.
+type moduleHandler interface {
+	unwrapModule() (any, *Module)
+}
+
+// moduleAggregate associates an aggregate message handler with a module.
+type moduleAggregate struct {
+	dogma.AggregateMessageHandler
+	module *Module
+}
+
+func (h *moduleAggregate) unwrapModule() (any, *Module) {
+	return h.AggregateMessageHandler, h.module
+}
+
+// moduleProcess associates a process message handler with a module.
+type moduleProcess struct {
+	dogma.ProcessMessageHandler
+	module *Module
+}
+
+func (h *moduleProcess) unwrapModule() (any, *Module) {
+	return h.ProcessMessageHandler, h.module
+}
+
+// moduleIntegration associates an integration message handler with a module.
+type moduleIntegration struct {
+	dogma.IntegrationMessageHandler
+	module *Module
+}
+
+func (h *moduleIntegration) unwrapModule() (any, *Module) {
+	return h.IntegrationMessageHandler, h.module
+}
+
+// moduleProjection associates a projection message handler with a module.
+type moduleProjection struct {
+	dogma.ProjectionMessageHandler
+	module *Module
+}
+
+func (h *moduleProjection) unwrapModule() (any, *Module) {
+	return h.ProjectionMessageHandler, h.module
+}
+
+// moduleNameOf returns the name of the [Module] that contains h, or false if h
+// is not part of a module.
+func moduleNameOf(h Handler) (string, bool) {
+	if x, ok := h.(interface {
+		moduleName() string
+	}); ok {
+		n := x.moduleName()
+		return n, n != ""
+	}
+	return "", false
+}
+
+// inModule returns a description of the module that contains h, for use in
+// error messages, or an empty string if h is not part of a module.
+func inModule(h Handler) string {
+	if m, ok := moduleNameOf(h); ok {
+		return ` in the "` + m + `" module`
+	}
+	return ""
+}
@@ -0,0 +1,331 @@
+package configkit_test
+
+import (
+	. "github.com/dogmatiq/configkit"
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+const moduleNamespace = "5ec8e6ee-8f0b-4a57-9b3e-1d7e2d1b7f24"
+
+var _ = Describe("type Module", func() {
+	var (
+		projection *ProjectionMessageHandlerStub
+		module     *Module
+	)
+
+	BeforeEach(func() {
+		projection = &ProjectionMessageHandlerStub{
+			ConfigureFunc: func(c dogma.ProjectionConfigurer) {
+				c.Identity("<audit-log>", projectionKey)
+				c.Routes(
+					dogma.HandlesEvent[*EventStub[TypeA]](),
+				)
+			},
+		}
+
+		module = NewModule(
+			"<auditing>",
+			moduleNamespace,
+			dogma.ViaProjection(projection),
+		)
+	})
+
+	Describe("func NewModule()", func() {
+		DescribeTable(
+			"it panics if the module is invalid",
+			func(msg string, fn func()) {
+				var err error
+				func() {
+					defer Recover(&err)
+					fn()
+				}()
+
+				Expect(err).To(MatchError(msg))
+			},
+			Entry(
+				"when the name is invalid",
+				`module is configured with an invalid name, invalid name "", names must be non-empty, printable UTF-8 strings with no whitespace`,
+				func() {
+					NewModule("", moduleNamespace)
+				},
+			),
+			Entry(
+				"when the namespace is invalid",
+				`the "<auditing>" module is configured with an invalid namespace "<namespace>", namespaces must be RFC 4122 UUIDs`,
+				func() {
+					NewModule("<auditing>", "<namespace>")
+				},
+			),
+			Entry(
+				"when a handler is already part of another module",
+				`the "<outer>" module can not include *stubs.ProjectionMessageHandlerStub, because it is already part of the "<auditing>" module`,
+				func() {
+					NewModule("<outer>", moduleNamespace, module.Routes()...)
+				},
+			),
+		)
+	})
+
+	Describe("func Name()", func() {
+		It("returns the module's name", func() {
+			Expect(module.Name()).To(Equal("<auditing>"))
+		})
+	})
+
+	Describe("func Namespace()", func() {
+		It("returns the module's namespace", func() {
+			Expect(module.Namespace()).To(Equal(moduleNamespace))
+		})
+	})
+
+	Describe("func Routes()", func() {
+		It("returns a route for each handler", func() {
+			Expect(module.Routes()).To(HaveLen(1))
+		})
+
+		It("returns a copy of the routes", func() {
+			routes := module.Routes()
+			routes[0] = nil
+
+			Expect(module.Routes()[0]).NotTo(BeNil())
+		})
+	})
+
+	Describe("func HandlerKey()", func() {
+		It("returns a deterministic key", func() {
+			k := module.HandlerKey(appKey, projectionKey)
+
+			Expect(ValidateIdentityKey(k)).To(Succeed())
+			Expect(k).To(Equal(module.HandlerKey(appKey, projectionKey)))
+		})
+
+		It("returns different keys for different applications", func() {
+			Expect(module.HandlerKey(appKey, projectionKey)).NotTo(Equal(module.HandlerKey(aggregateKey, projectionKey)))
+		})
+
+		It("returns different keys for different namespaces", func() {
+			other := NewModule("<other>", "0b6c5d06-8f5e-4d2f-8c43-7d2a4e8d3b51")
+			Expect(module.HandlerKey(appKey, projectionKey)).NotTo(Equal(other.HandlerKey(appKey, projectionKey)))
+		})
+
+		It("panics if the application key is invalid", func() {
+			Expect(func() {
+				module.HandlerKey("<app-key>", projectionKey)
+			}).To(PanicWith(MatchError(`invalid key "<app-key>", keys must be RFC 4122 UUIDs`)))
+		})
+
+		It("panics if the handler key is invalid", func() {
+			Expect(func() {
+				module.HandlerKey(appKey, "<handler-key>")
+			}).To(PanicWith(MatchError(`invalid key "<handler-key>", keys must be RFC 4122 UUIDs`)))
+		})
+	})
+
+	When("the module is included in an application", func() {
+		var app *ApplicationStub
+
+		BeforeEach(func() {
+			app = &ApplicationStub{
+				ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+					c.Identity("<app>", appKey)
+					c.Routes(module.Routes()...)
+				},
+			}
+		})
+
+		It("configures the handlers within the module", func() {
+			cfg := FromApplication(app)
+
+			h, ok := cfg.RichHandlers().ByName("<audit-log>")
+			Expect(ok).To(BeTrue())
+			Expect(h.TypeName()).To(Equal("*github.com/dogmatiq/enginekit/enginetest/stubs.ProjectionMessageHandlerStub"))
+			Expect(h.(RichProjection).Handler()).To(BeIdenticalTo(projection))
+		})
+
+		It("replaces each handler's key with the key derived from the module's namespace", func() {
+			cfg := FromApplication(app)
+
+			h, ok := cfg.Handlers().ByName("<audit-log>")
+			Expect(ok).To(BeTrue())
+			Expect(h.Identity()).To(Equal(
+				MustNewIdentity("<audit-log>", module.HandlerKey(appKey, projectionKey)),
+			))
+		})
+
+		It("does not add any metadata to the handlers within the module", func() {
+			cfg := FromApplication(app)
+
+			h, ok := cfg.Handlers().ByName("<audit-log>")
+			Expect(ok).To(BeTrue())
+			Expect(h.Metadata()).To(BeEmpty())
+		})
+
+		It("attaches metadata using the derived key", func() {
+			md := MetadataSet{}
+			md.Add(
+				MustNewIdentity("<audit-log>", module.HandlerKey(appKey, projectionKey)),
+				Metadata{OwnerMetadataKey: "<team>"},
+			)
+
+			cfg := FromApplication(app, WithMetadata(md))
+
+			h, ok := cfg.Handlers().ByName("<audit-log>")
+			Expect(ok).To(BeTrue())
+			Expect(h.Metadata()).To(Equal(Metadata{
+				OwnerMetadataKey: "<team>",
+			}))
+		})
+
+		It("allows the module to be included in several applications", func() {
+			other := &ApplicationStub{
+				ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+					c.Identity("<other>", aggregateKey)
+					c.Routes(module.Routes()...)
+				},
+			}
+
+			a, _ := FromApplication(app).Handlers().ByName("<audit-log>")
+			b, _ := FromApplication(other).Handlers().ByName("<audit-log>")
+
+			Expect(a.Identity().Key).NotTo(Equal(b.Identity().Key))
+		})
+
+		DescribeTable(
+			"it panics if the configuration is invalid",
+			func(msg string, fn func(dogma.ApplicationConfigurer)) {
+				app.ConfigureFunc = fn
+
+				var err error
+				func() {
+					defer Recover(&err)
+					FromApplication(app)
+				}()
+
+				Expect(err).To(MatchError(msg))
+			},
+			Entry(
+				"when the module is included before the application's identity is configured",
+				`*stubs.ApplicationStub includes the "<auditing>" module before its identity is configured, Identity() must be called before Routes()`,
+				func(c dogma.ApplicationConfigurer) {
+					c.Routes(module.Routes()...)
+					c.Identity("<app>", appKey)
+				},
+			),
+			Entry(
+				"when a handler in the module is configured with an invalid key",
+				`*stubs.ProjectionMessageHandlerStub in the "<auditing>" module is configured with an invalid identity, invalid key "<key>", keys must be RFC 4122 UUIDs`,
+				func(c dogma.ApplicationConfigurer) {
+					projection.ConfigureFunc = func(c dogma.ProjectionConfigurer) {
+						c.Identity("<audit-log>", "<key>")
+						c.Routes(
+							dogma.HandlesEvent[*EventStub[TypeA]](),
+						)
+					}
+
+					c.Identity("<app>", appKey)
+					c.Routes(module.Routes()...)
+				},
+			),
+			Entry(
+				"when a handler in the module is configured without an identity",
+				`*stubs.ProjectionMessageHandlerStub in the "<auditing>" module is configured without an identity, Identity() must be called exactly once within Configure()`,
+				func(c dogma.ApplicationConfigurer) {
+					projection.ConfigureFunc = func(c dogma.ProjectionConfigurer) {
+						c.Routes(
+							dogma.HandlesEvent[*EventStub[TypeA]](),
+						)
+					}
+
+					c.Identity("<app>", appKey)
+					c.Routes(module.Routes()...)
+				},
+			),
+			Entry(
+				"when the module is included more than once",
+				`*stubs.ProjectionMessageHandlerStub in the "<auditing>" module can not use the handler name "<audit-log>", because it is already used by *stubs.ProjectionMessageHandlerStub in the "<auditing>" module`,
+				func(c dogma.ApplicationConfigurer) {
+					c.Identity("<app>", appKey)
+					c.Routes(module.Routes()...)
+					c.Routes(module.Routes()...)
+				},
+			),
+			Entry(
+				"when a handler in the module has the same name as a handler outside the module",
+				`*stubs.ProjectionMessageHandlerStub in the "<auditing>" module can not use the handler name "<audit-log>", because it is already used by *stubs.AggregateMessageHandlerStub`,
+				func(c dogma.ApplicationConfigurer) {
+					c.Identity("<app>", appKey)
+					c.Routes(
+						dogma.ViaAggregate(&AggregateMessageHandlerStub{
+							ConfigureFunc: func(c dogma.AggregateConfigurer) {
+								c.Identity("<audit-log>", aggregateKey)
+								c.Routes(
+									dogma.HandlesCommand[*CommandStub[TypeA]](),
+									dogma.RecordsEvent[*EventStub[TypeA]](),
+								)
+							},
+						}),
+					)
+					c.Routes(module.Routes()...)
+				},
+			),
+			Entry(
+				"when handlers in different modules have the same name",
+				`*stubs.ProjectionMessageHandlerStub in the "<reporting>" module can not use the handler name "<audit-log>", because it is already used by *stubs.ProjectionMessageHandlerStub in the "<auditing>" module`,
+				func(c dogma.ApplicationConfigurer) {
+					c.Identity("<app>", appKey)
+					c.Routes(module.Routes()...)
+					c.Routes(
+						NewModule(
+							"<reporting>",
+							"0b6c5d06-8f5e-4d2f-8c43-7d2a4e8d3b51",
+							dogma.ViaProjection(&ProjectionMessageHandlerStub{
+								ConfigureFunc: func(c dogma.ProjectionConfigurer) {
+									c.Identity("<audit-log>", projectionKey)
+									c.Routes(
+										dogma.HandlesEvent[*EventStub[TypeA]](),
+									)
+								},
+							}),
+						).Routes()...,
+					)
+				},
+			),
+			Entry(
+				"when a handler in the module handles the same commands as a handler outside the module",
+				`*stubs.IntegrationMessageHandlerStub (<integration>) in the "<commands>" module can not handle *stubs.CommandStub[TypeA] commands because they are already configured to be handled by *stubs.AggregateMessageHandlerStub (<aggregate>)`,
+				func(c dogma.ApplicationConfigurer) {
+					c.Identity("<app>", appKey)
+					c.Routes(
+						dogma.ViaAggregate(&AggregateMessageHandlerStub{
+							ConfigureFunc: func(c dogma.AggregateConfigurer) {
+								c.Identity("<aggregate>", aggregateKey)
+								c.Routes(
+									dogma.HandlesCommand[*CommandStub[TypeA]](),
+									dogma.RecordsEvent[*EventStub[TypeA]](),
+								)
+							},
+						}),
+					)
+					c.Routes(
+						NewModule(
+							"<commands>",
+							moduleNamespace,
+							dogma.ViaIntegration(&IntegrationMessageHandlerStub{
+								ConfigureFunc: func(c dogma.IntegrationConfigurer) {
+									c.Identity("<integration>", integrationKey)
+									c.Routes(
+										dogma.HandlesCommand[*CommandStub[TypeA]](),
+									)
+								},
+							}),
+						).Routes()...,
+					)
+				},
+			),
+		)
+	})
+})
@@ -0,0 +1,398 @@
+package configkit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"reflect"
+	"slices"
+
+	"github.com/dogmatiq/configkit/internal/validation"
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/enginekit/message"
+	"go.yaml.in/yaml/v3"
+)
+
+// Overlay describes changes to an application's configuration that are
+// specific to the environment in which it runs, such as a particular region.
+//
+// Overlays are applied to the output of [FromApplication] using
+// [ApplyOverlays]. They are typically loaded from a YAML or JSON file using
+// [ReadOverlayFile].
+type Overlay struct {
+	// Name identifies the overlay in the changes that it makes.
+	Name string `json:"name"`
+
+	// Handlers is the set of changes to make to each handler, keyed by the
+	// handler's identity key.
+	Handlers map[string]HandlerOverlay `json:"handlers,omitempty"`
+}
+
+// HandlerOverlay describes changes that an [Overlay] makes to a single
+// handler.
+type HandlerOverlay struct {
+	// Name, if non-empty, replaces the name component of the handler's
+	// identity.
+	Name string `json:"name,omitempty"`
+
+	// Disabled, if true, disables the handler.
+	Disabled bool `json:"disabled,omitempty"`
+
+	// Labels is a set of key/value pairs to add to the handler's [Metadata].
+	// They replace any existing metadata with the same keys.
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// ReadOverlay reads the YAML or JSON representation of an [Overlay] from r.
+//
+// As JSON is a subset of YAML, both representations share the same field
+// names. Unknown fields are rejected, as are handler keys that are not valid
+// identity keys and empty label keys.
+func ReadOverlay(r io.Reader) (Overlay, error) {
+	var doc any
+	if err := yaml.NewDecoder(r).Decode(&doc); err != nil && err != io.EOF {
+		return Overlay{}, fmt.Errorf("invalid overlay: %w", err)
+	}
+
+	// Re-encode the document as JSON so that it is decoded according to the
+	// JSON field names, and unknown fields are rejected.
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return Overlay{}, fmt.Errorf("invalid overlay: %w", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	var o Overlay
+	if err := dec.Decode(&o); err != nil {
+		return Overlay{}, fmt.Errorf("invalid overlay: %w", err)
+	}
+
+	if err := o.validate(); err != nil {
+		return Overlay{}, fmt.Errorf("invalid overlay: %w", err)
+	}
+
+	return o, nil
+}
+
+// validate returns an error if o is not a valid overlay.
+func (o Overlay) validate() error {
+	if o.Name == "" {
+		return errors.New("name is not specified")
+	}
+
+	for _, k := range slices.Sorted(maps.Keys(o.Handlers)) {
+		if err := ValidateIdentityKey(k); err != nil {
+			return err
+		}
+
+		if _, ok := o.Handlers[k].Labels[""]; ok {
+			return fmt.Errorf("handler %s has an empty label key", k)
+		}
+	}
+
+	return nil
+}
+
+// ReadOverlayFile reads the YAML or JSON representation of an [Overlay] from
+// the file at the given path.
+func ReadOverlayFile(path string) (Overlay, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Overlay{}, err
+	}
+	defer f.Close()
+
+	o, err := ReadOverlay(f)
+	if err != nil {
+		return Overlay{}, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return o, nil
+}
+
+// OverlayResult is the result of applying overlays to an application's
+// configuration.
+type OverlayResult struct {
+	// Application is the configuration with the overlays applied.
+	Application RichApplication
+
+	// Changes is the list of changes made by the overlays, in the order they
+	// were applied.
+	Changes []OverlayChange
+}
+
+// OverlayChange describes a single change made by an [Overlay].
+type OverlayChange struct {
+	// Overlay is the name of the overlay that made the change.
+	Overlay string
+
+	// Handler is the identity of the handler that was changed, before the
+	// change was made.
+	Handler Identity
+
+	// Field is the part of the handler's configuration that was changed. It
+	// is "name", "disabled", or "labels." followed by the label's key.
+	Field string
+
+	// Before and After are the values before and after the change.
+	Before, After string
+}
+
+// String returns a human-readable description of the change.
+func (c OverlayChange) String() string {
+	return fmt.Sprintf(
+		"%s: %s %s: %q -> %q",
+		c.Overlay,
+		c.Handler,
+		c.Field,
+		c.Before,
+		c.After,
+	)
+}
+
+// ApplyOverlays returns the configuration of app with the given overlays
+// applied, in order.
+//
+// The result is validated in the same way as the output of [FromApplication],
+// such that it returns an error if an overlay gives a handler an invalid name,
+// or a name that is already in use. It also returns an error if an overlay is
+// invalid, as per [ReadOverlay], or refers to a handler that is not in the
+// application.
+func ApplyOverlays(app RichApplication, overlays ...Overlay) (_ OverlayResult, err error) {
+	handlers := map[string]*overlaidHandler{}
+	for id, h := range app.RichHandlers() {
+		handlers[id.Key] = &overlaidHandler{h, id, h.IsDisabled(), h.Metadata().Clone()}
+	}
+
+	var result OverlayResult
+
+	for _, o := range overlays {
+		if err := o.validate(); err != nil {
+			return OverlayResult{}, fmt.Errorf("invalid overlay: %w", err)
+		}
+
+		for _, k := range slices.Sorted(maps.Keys(o.Handlers)) {
+			h, ok := handlers[k]
+			if !ok {
+				return OverlayResult{}, fmt.Errorf(
+					"the %q overlay refers to a handler with key %q, which is not in the %s application",
+					o.Name,
+					k,
+					app.Identity(),
+				)
+			}
+
+			result.Changes = append(
+				result.Changes,
+				h.apply(o.Name, o.Handlers[k])...,
+			)
+		}
+	}
+
+	defer Recover(&err)
+
+	cfg := &richApplication{
+		ident: app.Identity(),
+		app:   app.Application(),
+	}
+	c := &applicationConfigurer{config: cfg}
+
+	for _, k := range slices.Sorted(maps.Keys(handlers)) {
+		h := handlers[k].rich()
+
+		if err := ValidateIdentityName(h.Identity().Name); err != nil {
+			validation.Panicf(
+				"%s is overlaid with an invalid identity, %s",
+				h.ReflectType(),
+				err,
+			)
+		}
+
+		c.guardAgainstConflictingIdentities(h)
+		c.guardAgainstConflictingRoutes(h)
+		cfg.handlers.add(h)
+	}
+
+	result.Application = &overlaidApplication{app, cfg.handlers}
+
+	return result, nil
+}
+
+// overlaidHandler is the state of a handler while overlays are being applied.
+type overlaidHandler struct {
+	config     RichHandler
+	ident      Identity
+	isDisabled bool
+	metadata   Metadata
+}
+
+// apply applies the changes in o to h, and returns a description of those
+// changes.
+func (h *overlaidHandler) apply(overlay string, o HandlerOverlay) []OverlayChange {
+	var changes []OverlayChange
+
+	if o.Name != "" && o.Name != h.ident.Name {
+		changes = append(changes, OverlayChange{overlay, h.ident, "name", h.ident.Name, o.Name})
+		h.ident.Name = o.Name
+	}
+
+	if o.Disabled && !h.isDisabled {
+		changes = append(changes, OverlayChange{overlay, h.ident, "disabled", "false", "true"})
+		h.isDisabled = true
+	}
+
+	for _, k := range slices.Sorted(maps.Keys(o.Labels)) {
+		v := o.Labels[k]
+
+		if x, ok := h.metadata[k]; !ok || x != v {
+			changes = append(changes, OverlayChange{overlay, h.ident, "labels." + k, x, v})
+
+			if h.metadata == nil {
+				h.metadata = Metadata{}
+			}
+			h.metadata[k] = v
+		}
+	}
+
+	return changes
+}
+
+// rich returns the overlaid configuration of the handler.
+func (h *overlaidHandler) rich() RichHandler {
+	if h.ident == h.config.Identity() &&
+		h.isDisabled == h.config.IsDisabled() &&
+		h.metadata.IsEqual(h.config.Metadata()) {
+		return h.config
+	}
+
+	switch x := h.config.(type) {
+	case RichAggregate:
+		return &overlaidRichHandler[dogma.AggregateMessageHandler]{x, h.ident, h.isDisabled, h.metadata}
+	case RichProcess:
+		return &overlaidRichHandler[dogma.ProcessMessageHandler]{x, h.ident, h.isDisabled, h.metadata}
+	case RichIntegration:
+		return &overlaidRichHandler[dogma.IntegrationMessageHandler]{x, h.ident, h.isDisabled, h.metadata}
+	default:
+		return &overlaidRichHandler[dogma.ProjectionMessageHandler]{x.(RichProjection), h.ident, h.isDisabled, h.metadata}
+	}
+}
+
+// overlaidApplication is an implementation of [RichApplication] that has had
+// overlays applied to its handlers.
+type overlaidApplication struct {
+	RichApplication
+	handlers richHandlerIndex
+}
+
+func (a *overlaidApplication) AcceptVisitor(ctx context.Context, v Visitor) error {
+	return v.VisitApplication(ctx, a)
+}
+
+func (a *overlaidApplication) AcceptRichVisitor(ctx context.Context, v RichVisitor) error {
+	return v.VisitRichApplication(ctx, a)
+}
+
+func (a *overlaidApplication) Handlers() HandlerSet {
+	return HandlerSet(a.handlers.plain.handlers)
+}
+
+func (a *overlaidApplication) RichHandlers() RichHandlerSet {
+	return RichHandlerSet(a.handlers.rich.handlers)
+}
+
+func (a *overlaidApplication) handlerIndex() *HandlerIndex[Handler] {
+	return &a.handlers.plain
+}
+
+func (a *overlaidApplication) richHandlerIndex() *HandlerIndex[RichHandler] {
+	return &a.handlers.rich
+}
+
+// overlaidRichHandler is an implementation of [RichHandler] that has had
+// overlays applied to its identity, disabled state or metadata.
+//
+// T is the Dogma interface that is implemented by handlers of this type, such
+// as [dogma.AggregateMessageHandler].
+type overlaidRichHandler[T any] struct {
+	config interface {
+		RichHandler
+		Handler() T
+	}
+	ident      Identity
+	isDisabled bool
+	metadata   Metadata
+}
+
+func (h *overlaidRichHandler[T]) Identity() Identity {
+	return h.ident
+}
+
+func (h *overlaidRichHandler[T]) MessageNames() EntityMessages[message.Name] {
+	return h.config.MessageNames()
+}
+
+func (h *overlaidRichHandler[T]) MessageTypes() EntityMessages[message.Type] {
+	return h.config.MessageTypes()
+}
+
+func (h *overlaidRichHandler[T]) Metadata() Metadata {
+	return h.metadata
+}
+
+func (h *overlaidRichHandler[T]) moduleName() string {
+	name, _ := moduleNameOf(h.config)
+	return name
+}
+
+func (h *overlaidRichHandler[T]) TypeName() string {
+	return h.config.TypeName()
+}
+
+func (h *overlaidRichHandler[T]) ReflectType() reflect.Type {
+	return h.config.ReflectType()
+}
+
+func (h *overlaidRichHandler[T]) HandlerType() HandlerType {
+	return h.config.HandlerType()
+}
+
+func (h *overlaidRichHandler[T]) IsDisabled() bool {
+	return h.isDisabled
+}
+
+func (h *overlaidRichHandler[T]) Handler() T {
+	return h.config.Handler()
+}
+
+func (h *overlaidRichHandler[T]) AcceptVisitor(ctx context.Context, v Visitor) error {
+	switch h.HandlerType() {
+	case AggregateHandlerType:
+		return v.VisitAggregate(ctx, h)
+	case ProcessHandlerType:
+		return v.VisitProcess(ctx, h)
+	case IntegrationHandlerType:
+		return v.VisitIntegration(ctx, h)
+	default: // ProjectionHandlerType
+		return v.VisitProjection(ctx, h)
+	}
+}
+
+func (h *overlaidRichHandler[T]) AcceptRichVisitor(ctx context.Context, v RichVisitor) error {
+	switch x := any(h).(type) {
+	case RichAggregate:
+		return v.VisitRichAggregate(ctx, x)
+	case RichProcess:
+		return v.VisitRichProcess(ctx, x)
+	case RichIntegration:
+		return v.VisitRichIntegration(ctx, x)
+	default:
+		return v.VisitRichProjection(ctx, x.(RichProjection))
+	}
+}
@@ -0,0 +1,409 @@
+package configkit_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/dogmatiq/configkit"
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("func ReadOverlay()", func() {
+	It("reads an overlay from its JSON representation", func() {
+		o, err := ReadOverlay(strings.NewReader(`{
+			"name": "<overlay>",
+			"handlers": {
+				"` + aggregateKey + `": {
+					"name": "<renamed>",
+					"disabled": true,
+					"labels": { "region": "eu" }
+				}
+			}
+		}`))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(o).To(Equal(Overlay{
+			Name: "<overlay>",
+			Handlers: map[string]HandlerOverlay{
+				aggregateKey: {
+					Name:     "<renamed>",
+					Disabled: true,
+					Labels:   map[string]string{"region": "eu"},
+				},
+			},
+		}))
+	})
+
+	It("reads an overlay from its YAML representation", func() {
+		o, err := ReadOverlay(strings.NewReader(
+			"name: <overlay>\n" +
+				"handlers:\n" +
+				"  " + aggregateKey + ":\n" +
+				"    name: <renamed>\n" +
+				"    disabled: true\n" +
+				"    labels:\n" +
+				"      region: eu\n",
+		))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(o).To(Equal(Overlay{
+			Name: "<overlay>",
+			Handlers: map[string]HandlerOverlay{
+				aggregateKey: {
+					Name:     "<renamed>",
+					Disabled: true,
+					Labels:   map[string]string{"region": "eu"},
+				},
+			},
+		}))
+	})
+
+	It("returns an error if the overlay does not have a name", func() {
+		_, err := ReadOverlay(strings.NewReader(`{}`))
+		Expect(err).To(MatchError("invalid overlay: name is not specified"))
+	})
+
+	It("returns an error if a handler key is not a valid identity key", func() {
+		_, err := ReadOverlay(strings.NewReader(`{
+			"name": "<overlay>",
+			"handlers": {
+				"<aggregate>": { "disabled": true }
+			}
+		}`))
+		Expect(err).To(MatchError(`invalid overlay: invalid key "<aggregate>", keys must be RFC 4122 UUIDs`))
+	})
+
+	It("returns an error if a label key is empty", func() {
+		_, err := ReadOverlay(strings.NewReader(`{
+			"name": "<overlay>",
+			"handlers": {
+				"` + aggregateKey + `": { "labels": { "": "<value>" } }
+			}
+		}`))
+		Expect(err).To(MatchError("invalid overlay: handler " + aggregateKey + " has an empty label key"))
+	})
+
+	It("returns an error if the overlay contains unknown fields", func() {
+		_, err := ReadOverlay(strings.NewReader(`{"name": "<overlay>", "enabled": true}`))
+		Expect(err).To(MatchError(ContainSubstring(`unknown field "enabled"`)))
+	})
+
+	It("returns an error if the overlay is malformed", func() {
+		_, err := ReadOverlay(strings.NewReader(`{`))
+		Expect(err).To(MatchError(HavePrefix("invalid overlay: ")))
+	})
+})
+
+var _ = Describe("func ReadOverlayFile()", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "configkit-overlay-")
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("reads an overlay from a file", func() {
+		path := filepath.Join(dir, "overlay.json")
+		err := os.WriteFile(path, []byte(`{"name": "<overlay>"}`), 0o600)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		o, err := ReadOverlayFile(path)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(o.Name).To(Equal("<overlay>"))
+	})
+
+	It("reads an overlay from a YAML file", func() {
+		path := filepath.Join(dir, "overlay.yaml")
+		err := os.WriteFile(path, []byte("name: <overlay>\n"), 0o600)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		o, err := ReadOverlayFile(path)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(o.Name).To(Equal("<overlay>"))
+	})
+
+	It("includes the path in errors", func() {
+		path := filepath.Join(dir, "overlay.json")
+		err := os.WriteFile(path, []byte(`{`), 0o600)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = ReadOverlayFile(path)
+		Expect(err).To(MatchError(HavePrefix(path + ": invalid overlay: ")))
+	})
+
+	It("returns an error if the file does not exist", func() {
+		_, err := ReadOverlayFile(filepath.Join(dir, "missing.json"))
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+})
+
+var _ = Describe("func ApplyOverlays()", func() {
+	var app RichApplication
+
+	BeforeEach(func() {
+		app = FromApplication(&ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app>", appKey)
+				c.Routes(
+					dogma.ViaAggregate(&AggregateMessageHandlerStub{
+						ConfigureFunc: func(c dogma.AggregateConfigurer) {
+							c.Identity("<aggregate>", aggregateKey)
+							c.Routes(
+								dogma.HandlesCommand[*CommandStub[TypeA]](),
+								dogma.RecordsEvent[*EventStub[TypeA]](),
+							)
+						},
+					}),
+					dogma.ViaProjection(&ProjectionMessageHandlerStub{
+						ConfigureFunc: func(c dogma.ProjectionConfigurer) {
+							c.Identity("<projection>", projectionKey)
+							c.Routes(
+								dogma.HandlesEvent[*EventStub[TypeA]](),
+							)
+						},
+					}),
+				)
+			},
+		})
+	})
+
+	It("returns the application unchanged if there are no overlays", func() {
+		res, err := ApplyOverlays(app)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(res.Changes).To(BeEmpty())
+		Expect(res.Application.Identity()).To(Equal(app.Identity()))
+		Expect(res.Application.RichHandlers()).To(Equal(app.RichHandlers()))
+	})
+
+	It("renames handlers", func() {
+		res, err := ApplyOverlays(app, Overlay{
+			Name: "<overlay>",
+			Handlers: map[string]HandlerOverlay{
+				aggregateKey: {Name: "<renamed>"},
+			},
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+
+		_, ok := res.Application.RichHandlers().ByName("<aggregate>")
+		Expect(ok).To(BeFalse())
+
+		h, ok := res.Application.RichHandlers().ByName("<renamed>")
+		Expect(ok).To(BeTrue())
+		Expect(h.Identity()).To(Equal(MustNewIdentity("<renamed>", aggregateKey)))
+		Expect(h.HandlerType()).To(Equal(AggregateHandlerType))
+		Expect(h.MessageNames()).To(Equal(handlerByKey(app, aggregateKey).MessageNames()))
+
+		_, ok = h.(RichAggregate)
+		Expect(ok).To(BeTrue())
+	})
+
+	It("disables handlers", func() {
+		res, err := ApplyOverlays(app, Overlay{
+			Name: "<overlay>",
+			Handlers: map[string]HandlerOverlay{
+				projectionKey: {Disabled: true},
+			},
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+
+		h := handlerByKey(res.Application, projectionKey)
+		Expect(h.IsDisabled()).To(BeTrue())
+		Expect(handlerByKey(app, projectionKey).IsDisabled()).To(BeFalse())
+	})
+
+	It("adds labels to the metadata of handlers", func() {
+		res, err := ApplyOverlays(
+			app,
+			Overlay{
+				Name: "<base>",
+				Handlers: map[string]HandlerOverlay{
+					aggregateKey: {
+						Labels: map[string]string{
+							"region": "us",
+							"tier":   "gold",
+						},
+					},
+				},
+			},
+			Overlay{
+				Name: "<region>",
+				Handlers: map[string]HandlerOverlay{
+					aggregateKey: {
+						Labels: map[string]string{"region": "eu"},
+					},
+				},
+			},
+		)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(handlerByKey(res.Application, aggregateKey).Metadata()).To(Equal(Metadata{
+			"region": "eu",
+			"tier":   "gold",
+		}))
+		Expect(handlerByKey(app, aggregateKey).Metadata()).To(BeEmpty())
+	})
+
+	It("records the metadata that is replaced by labels", func() {
+		app = FromApplication(
+			app.Application(),
+			WithMetadata(MetadataSet{
+				aggregateKey: {"region": "us"},
+			}),
+		)
+
+		res, err := ApplyOverlays(app, Overlay{
+			Name: "<overlay>",
+			Handlers: map[string]HandlerOverlay{
+				aggregateKey: {
+					Labels: map[string]string{"region": "eu"},
+				},
+			},
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(res.Changes).To(Equal([]OverlayChange{
+			{
+				Overlay: "<overlay>",
+				Handler: MustNewIdentity("<aggregate>", aggregateKey),
+				Field:   "labels.region",
+				Before:  "us",
+				After:   "eu",
+			},
+		}))
+		Expect(handlerByKey(app, aggregateKey).Metadata()).To(Equal(Metadata{"region": "us"}))
+	})
+
+	It("records the changes made by each overlay", func() {
+		ident := MustNewIdentity("<aggregate>", aggregateKey)
+
+		res, err := ApplyOverlays(
+			app,
+			Overlay{
+				Name: "<base>",
+				Handlers: map[string]HandlerOverlay{
+					aggregateKey: {
+						Name:   "<renamed>",
+						Labels: map[string]string{"region": "us"},
+					},
+				},
+			},
+			Overlay{
+				Name: "<region>",
+				Handlers: map[string]HandlerOverlay{
+					aggregateKey: {
+						Name:     "<renamed>",
+						Disabled: true,
+						Labels:   map[string]string{"region": "eu"},
+					},
+				},
+			},
+		)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		renamed := MustNewIdentity("<renamed>", aggregateKey)
+		Expect(res.Changes).To(Equal([]OverlayChange{
+			{Overlay: "<base>", Handler: ident, Field: "name", Before: "<aggregate>", After: "<renamed>"},
+			{Overlay: "<base>", Handler: renamed, Field: "labels.region", Before: "", After: "us"},
+			{Overlay: "<region>", Handler: renamed, Field: "disabled", Before: "false", After: "true"},
+			{Overlay: "<region>", Handler: renamed, Field: "labels.region", Before: "us", After: "eu"},
+		}))
+
+		Expect(res.Changes[0].String()).To(Equal(
+			`<base>: <aggregate>/` + aggregateKey + ` name: "<aggregate>" -> "<renamed>"`,
+		))
+	})
+
+	It("returns an application that can be visited", func() {
+		res, err := ApplyOverlays(app, Overlay{
+			Name: "<overlay>",
+			Handlers: map[string]HandlerOverlay{
+				aggregateKey: {Name: "<renamed>"},
+			},
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+
+		var names []string
+		for h := range res.Application.RichHandlers().Sorted() {
+			names = append(names, h.Identity().Name)
+		}
+		Expect(names).To(Equal([]string{"<renamed>", "<projection>"}))
+
+		Expect(ToString(res.Application)).To(ContainSubstring("<renamed>"))
+		Expect(res.Application.MessageNames()).To(Equal(app.MessageNames()))
+
+		h := handlerByKey(res.Application, aggregateKey)
+		err = h.AcceptRichVisitor(context.Background(), &aggregateVisitorStub{})
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	It("returns an error if an overlay refers to an unknown handler", func() {
+		_, err := ApplyOverlays(app, Overlay{
+			Name: "<overlay>",
+			Handlers: map[string]HandlerOverlay{
+				integrationKey: {Disabled: true},
+			},
+		})
+		Expect(err).To(MatchError(
+			`the "<overlay>" overlay refers to a handler with key "` + integrationKey + `", which is not in the <app>/` + appKey + ` application`,
+		))
+	})
+
+	It("returns an error if an overlay is invalid", func() {
+		_, err := ApplyOverlays(app, Overlay{
+			Name: "<overlay>",
+			Handlers: map[string]HandlerOverlay{
+				aggregateKey: {
+					Labels: map[string]string{"": "<value>"},
+				},
+			},
+		})
+		Expect(err).To(MatchError("invalid overlay: handler " + aggregateKey + " has an empty label key"))
+	})
+
+	It("returns an error if an overlay gives a handler an invalid name", func() {
+		_, err := ApplyOverlays(app, Overlay{
+			Name: "<overlay>",
+			Handlers: map[string]HandlerOverlay{
+				aggregateKey: {Name: "<invalid name>"},
+			},
+		})
+		Expect(err).To(MatchError(
+			`*stubs.AggregateMessageHandlerStub is overlaid with an invalid identity, invalid name "<invalid name>", names must be non-empty, printable UTF-8 strings with no whitespace`,
+		))
+	})
+
+	It("returns an error if an overlay gives a handler a name that is already in use", func() {
+		_, err := ApplyOverlays(app, Overlay{
+			Name: "<overlay>",
+			Handlers: map[string]HandlerOverlay{
+				projectionKey: {Name: "<aggregate>"},
+			},
+		})
+		Expect(err).To(MatchError(
+			`*stubs.ProjectionMessageHandlerStub can not use the handler name "<aggregate>", because it is already used by *stubs.AggregateMessageHandlerStub`,
+		))
+	})
+})
+
+// aggregateVisitorStub is a [RichVisitor] that fails unless it visits an
+// aggregate.
+type aggregateVisitorStub struct {
+	RichVisitor
+}
+
+func (aggregateVisitorStub) VisitRichAggregate(context.Context, RichAggregate) error {
+	return nil
+}
+
+// handlerByKey returns the handler in app with the given key.
+func handlerByKey(app RichApplication, k string) RichHandler {
+	h, ok := app.RichHandlers().ByKey(k)
+	Expect(ok).To(BeTrue())
+	return h
+}
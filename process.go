@@ -4,7 +4,7 @@ import (
 	"context"
 	"reflect"
 
-	"github.com/dogmatiq/configkit/internal/typename/goreflect"
+	"github.com/dogmatiq/configkit/typename"
 	"github.com/dogmatiq/dogma"
 	"github.com/dogmatiq/enginekit/message"
 )
@@ -28,15 +28,33 @@ type RichProcess interface {
 //
 // It panics if the handler is configured incorrectly. Use Recover() to convert
 // configuration related panic values to errors.
-func FromProcess(h dogma.ProcessMessageHandler) RichProcess {
-	cfg := fromProcessUnvalidated(h)
+func FromProcess(h dogma.ProcessMessageHandler, options ...ConfigureOption) RichProcess {
+	cfg := fromProcessUnvalidated(h, newConfigureOptions(options))
 	cfg.mustValidate()
 	return cfg
 }
 
-func fromProcessUnvalidated(h dogma.ProcessMessageHandler) *richProcess {
-	cfg := &richProcess{handler: h}
-	h.Configure(&processConfigurer{cfg})
+func fromProcessUnvalidated(
+	h dogma.ProcessMessageHandler,
+	opts configureOptions,
+) *richProcess {
+	cfg := &richProcess{handler: h, module: opts.moduleName()}
+
+	opts.configure(
+		cfg.ReflectType(),
+		func() PanicEntity {
+			return PanicEntity{ProcessHandlerType, cfg.ident, cfg.TypeName()}
+		},
+		func() {
+			c := &processConfigurer{config: cfg}
+			defer c.end()
+			h.Configure(c)
+		},
+	)
+
+	cfg.names = asMessageNames(cfg.types)
+	cfg.ident, cfg.metadata = opts.resolve(cfg.ident)
+
 	return cfg
 }
 
@@ -44,6 +62,9 @@ func fromProcessUnvalidated(h dogma.ProcessMessageHandler) *richProcess {
 type richProcess struct {
 	ident      Identity
 	types      EntityMessages[message.Type]
+	names      EntityMessages[message.Name]
+	metadata   Metadata
+	module     string
 	isDisabled bool
 	handler    dogma.ProcessMessageHandler
 }
@@ -53,15 +74,23 @@ func (h *richProcess) Identity() Identity {
 }
 
 func (h *richProcess) MessageNames() EntityMessages[message.Name] {
-	return asMessageNames(h.types)
+	return h.names
 }
 
 func (h *richProcess) MessageTypes() EntityMessages[message.Type] {
 	return h.types
 }
 
+func (h *richProcess) Metadata() Metadata {
+	return h.metadata
+}
+
+func (h *richProcess) moduleName() string {
+	return h.module
+}
+
 func (h *richProcess) TypeName() string {
-	return goreflect.NameOf(h.ReflectType())
+	return typename.FromReflect(h.ReflectType())
 }
 
 func (h *richProcess) ReflectType() reflect.Type {
@@ -93,24 +122,28 @@ func (h *richProcess) isConfigured() bool {
 }
 
 func (h *richProcess) mustValidate() {
-	mustHaveValidIdentity(h.Identity(), h.ReflectType())
+	mustHaveValidIdentity(h.Identity(), h.ReflectType(), inModule(h))
 	mustHaveConsumerRoute(&h.types, message.EventKind, h.Identity(), h.ReflectType())
 	mustHaveProducerRoute(&h.types, message.CommandKind, h.Identity(), h.ReflectType())
 }
 
 // processConfigurer is the default implementation of [dogma.ProcessConfigurer].
 type processConfigurer struct {
+	configurerLifecycle
 	config *richProcess
 }
 
 func (c *processConfigurer) Identity(name, key string) {
-	configureIdentity(&c.config.ident, name, key, c.config.ReflectType())
+	c.guard(c.config.ReflectType(), "Identity")
+	configureIdentity(&c.config.ident, name, key, c.config.ReflectType(), inModule(c.config))
 }
 
 func (c *processConfigurer) Routes(routes ...dogma.ProcessRoute) {
+	c.guard(c.config.ReflectType(), "Routes")
 	configureRoutes(&c.config.types, routes, c.config.ident, c.config.ReflectType())
 }
 
 func (c *processConfigurer) Disable(...dogma.DisableOption) {
+	c.guard(c.config.ReflectType(), "Disable")
 	c.config.isDisabled = true
 }
@@ -4,7 +4,7 @@ import (
 	"context"
 	"reflect"
 
-	"github.com/dogmatiq/configkit/internal/typename/goreflect"
+	"github.com/dogmatiq/configkit/typename"
 	"github.com/dogmatiq/dogma"
 	"github.com/dogmatiq/enginekit/message"
 )
@@ -28,15 +28,33 @@ type RichProjection interface {
 //
 // It panics if the handler is configured incorrectly. Use Recover() to convert
 // configuration related panic values to errors.
-func FromProjection(h dogma.ProjectionMessageHandler) RichProjection {
-	cfg := fromProjectionUnvalidated(h)
+func FromProjection(h dogma.ProjectionMessageHandler, options ...ConfigureOption) RichProjection {
+	cfg := fromProjectionUnvalidated(h, newConfigureOptions(options))
 	cfg.mustValidate()
 	return cfg
 }
 
-func fromProjectionUnvalidated(h dogma.ProjectionMessageHandler) *richProjection {
-	cfg := &richProjection{handler: h}
-	h.Configure(&projectionConfigurer{cfg})
+func fromProjectionUnvalidated(
+	h dogma.ProjectionMessageHandler,
+	opts configureOptions,
+) *richProjection {
+	cfg := &richProjection{handler: h, module: opts.moduleName()}
+
+	opts.configure(
+		cfg.ReflectType(),
+		func() PanicEntity {
+			return PanicEntity{ProjectionHandlerType, cfg.ident, cfg.TypeName()}
+		},
+		func() {
+			c := &projectionConfigurer{config: cfg}
+			defer c.end()
+			h.Configure(c)
+		},
+	)
+
+	cfg.names = asMessageNames(cfg.types)
+	cfg.ident, cfg.metadata = opts.resolve(cfg.ident)
+
 	return cfg
 }
 
@@ -44,6 +62,9 @@ func fromProjectionUnvalidated(h dogma.ProjectionMessageHandler) *richProjection
 type richProjection struct {
 	ident      Identity
 	types      EntityMessages[message.Type]
+	names      EntityMessages[message.Name]
+	metadata   Metadata
+	module     string
 	isDisabled bool
 	handler    dogma.ProjectionMessageHandler
 }
@@ -53,15 +74,23 @@ func (h *richProjection) Identity() Identity {
 }
 
 func (h *richProjection) MessageNames() EntityMessages[message.Name] {
-	return asMessageNames(h.types)
+	return h.names
 }
 
 func (h *richProjection) MessageTypes() EntityMessages[message.Type] {
 	return h.types
 }
 
+func (h *richProjection) Metadata() Metadata {
+	return h.metadata
+}
+
+func (h *richProjection) moduleName() string {
+	return h.module
+}
+
 func (h *richProjection) TypeName() string {
-	return goreflect.NameOf(h.ReflectType())
+	return typename.FromReflect(h.ReflectType())
 }
 
 func (h *richProjection) ReflectType() reflect.Type {
@@ -93,24 +122,28 @@ func (h *richProjection) isConfigured() bool {
 }
 
 func (h *richProjection) mustValidate() {
-	mustHaveValidIdentity(h.Identity(), h.ReflectType())
+	mustHaveValidIdentity(h.Identity(), h.ReflectType(), inModule(h))
 	mustHaveConsumerRoute(&h.types, message.EventKind, h.Identity(), h.ReflectType())
 }
 
 // projectionConfigurer is the default implementation of
 // [dogma.ProjectionConfigurer].
 type projectionConfigurer struct {
+	configurerLifecycle
 	config *richProjection
 }
 
 func (c *projectionConfigurer) Identity(name, key string) {
-	configureIdentity(&c.config.ident, name, key, c.config.ReflectType())
+	c.guard(c.config.ReflectType(), "Identity")
+	configureIdentity(&c.config.ident, name, key, c.config.ReflectType(), inModule(c.config))
 }
 
 func (c *projectionConfigurer) Routes(routes ...dogma.ProjectionRoute) {
+	c.guard(c.config.ReflectType(), "Routes")
 	configureRoutes(&c.config.types, routes, c.config.ident, c.config.ReflectType())
 }
 
 func (c *projectionConfigurer) Disable(...dogma.DisableOption) {
+	c.guard(c.config.ReflectType(), "Disable")
 	c.config.isDisabled = true
 }
@@ -0,0 +1,254 @@
+package configkit
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/dogmatiq/configkit/typename"
+)
+
+// RenderOption is an option that changes how type names are rendered within
+// human-readable representations of configuration.
+//
+// See [ToString] and [TypeNameRenderer].
+type RenderOption func(*renderOptions)
+
+// renderOptions is the set of options applied by a [TypeNameRenderer].
+type renderOptions struct {
+	modulePrefix  string
+	packageNames  bool
+	shortTypeArgs bool
+}
+
+// WithModulePrefix returns an option that removes the given prefix from the
+// package paths of types within that module.
+//
+// For example, with a prefix of "github.com/acme", the type
+// "github.com/acme/billing/domain.InvoiceIssued" is rendered as
+// "billing/domain.InvoiceIssued".
+func WithModulePrefix(prefix string) RenderOption {
+	return func(o *renderOptions) {
+		o.modulePrefix = strings.TrimSuffix(prefix, "/")
+	}
+}
+
+// WithPackageNames returns an option that qualifies types by the name of their
+// package instead of its full path.
+//
+// The package name is inferred from the last element of the package path,
+// ignoring any major version suffix such as "/v2". If two packages have the same
+// name, they are qualified by as many trailing elements of their paths as are
+// required to tell them apart.
+func WithPackageNames() RenderOption {
+	return func(o *renderOptions) {
+		o.packageNames = true
+	}
+}
+
+// WithShortTypeArgs returns an option that omits the package qualifier of
+// type arguments that are in the same package as the generic type itself.
+//
+// For example, "stubs.CommandStub[stubs.TypeA]" is rendered as
+// "stubs.CommandStub[TypeA]", as per [message.Type.String].
+func WithShortTypeArgs() RenderOption {
+	return func(o *renderOptions) {
+		o.shortTypeArgs = true
+	}
+}
+
+// TypeNameRenderer abbreviates fully-qualified type names for display.
+//
+// The abbreviated names are unambiguous with respect to the names that are
+// added to the renderer. Names within a package that has not been added are
+// qualified by the full package path.
+type TypeNameRenderer struct {
+	options    renderOptions
+	packages   map[string]struct{}
+	qualifiers map[string]string
+}
+
+// NewTypeNameRenderer returns a new renderer that uses the given options.
+//
+// If no options are given, names are rendered as-is.
+func NewTypeNameRenderer(options ...RenderOption) *TypeNameRenderer {
+	r := &TypeNameRenderer{
+		packages: map[string]struct{}{},
+	}
+
+	for _, opt := range options {
+		opt(&r.options)
+	}
+
+	return r
+}
+
+// Add adds the given fully-qualified type names to the renderer, such that
+// they are considered when disambiguating abbreviated names.
+func (r *TypeNameRenderer) Add(names ...string) {
+	for _, n := range names {
+		if name, err := typename.Parse(n); err == nil {
+			r.addPackages(name)
+		}
+	}
+}
+
+// AddEntity adds the names of the types used by e to the renderer, including
+// those of the messages it uses and, if e is an application, its handlers.
+func (r *TypeNameRenderer) AddEntity(e Entity) {
+	r.Add(e.TypeName())
+
+	for n := range e.MessageNames() {
+		r.Add(string(n))
+	}
+
+	if app, ok := e.(Application); ok {
+		for _, h := range app.Handlers() {
+			r.Add(h.TypeName())
+		}
+	}
+}
+
+// Render returns the abbreviated form of the fully-qualified type name n.
+func (r *TypeNameRenderer) Render(n string) string {
+	if r.options == (renderOptions{}) {
+		return n
+	}
+
+	name, err := typename.Parse(n)
+	if err != nil {
+		return n
+	}
+
+	if r.qualifiers == nil {
+		r.resolve()
+	}
+
+	var w strings.Builder
+	r.render(&w, name, name.Package, false)
+	return w.String()
+}
+
+// render writes the abbreviated form of name to w.
+//
+// outer is the package of the outermost type within the name being rendered.
+func (r *TypeNameRenderer) render(
+	w *strings.Builder,
+	name typename.Name,
+	outer string,
+	isTypeArg bool,
+) {
+	w.WriteString(name.Prefix)
+
+	if name.Package != "" && !(isTypeArg && r.options.shortTypeArgs && name.Package == outer) {
+		q, ok := r.qualifiers[name.Package]
+		if !ok {
+			q = name.Package
+		}
+
+		w.WriteString(q)
+		w.WriteByte('.')
+	}
+
+	w.WriteString(name.Base)
+
+	if len(name.TypeArgs) > 0 {
+		w.WriteByte('[')
+
+		for i, arg := range name.TypeArgs {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			r.render(w, arg, outer, true)
+		}
+
+		w.WriteByte(']')
+	}
+}
+
+// addPackages records the packages referenced by name.
+func (r *TypeNameRenderer) addPackages(name typename.Name) {
+	if name.Package != "" {
+		if _, ok := r.packages[name.Package]; !ok {
+			r.packages[name.Package] = struct{}{}
+			r.qualifiers = nil
+		}
+	}
+
+	for _, arg := range name.TypeArgs {
+		r.addPackages(arg)
+	}
+}
+
+// resolve computes an unambiguous qualifier for each package.
+//
+// Each package starts with the shortest qualifier permitted by the options.
+// Packages that share a qualifier are given progressively longer qualifiers
+// until every qualifier is unique. The full package path is always unique, so
+// this process terminates.
+func (r *TypeNameRenderer) resolve() {
+	depth := map[string]int{}
+	for p := range r.packages {
+		depth[p] = 1
+	}
+
+	for {
+		r.qualifiers = map[string]string{}
+		owners := map[string][]string{}
+
+		for p := range r.packages {
+			q := r.qualifier(p, depth[p])
+			r.qualifiers[p] = q
+			owners[q] = append(owners[q], p)
+		}
+
+		done := true
+		for _, packages := range owners {
+			if len(packages) > 1 {
+				done = false
+				for _, p := range packages {
+					depth[p]++
+				}
+			}
+		}
+
+		if done {
+			return
+		}
+	}
+}
+
+// majorVersion matches the major version suffix of a Go module path.
+var majorVersion = regexp.MustCompile(`^v[0-9]+$`)
+
+// qualifier returns the qualifier to use for the package path p, using depth
+// trailing path elements if package names are in use.
+func (r *TypeNameRenderer) qualifier(p string, depth int) string {
+	rel := p
+
+	if m := r.options.modulePrefix; m != "" {
+		if p == m {
+			rel = m[strings.LastIndexByte(m, '/')+1:]
+		} else if strings.HasPrefix(p, m+"/") {
+			rel = p[len(m)+1:]
+		}
+	}
+
+	if !r.options.packageNames {
+		if depth > 1 {
+			return p
+		}
+		return rel
+	}
+
+	elems := strings.Split(rel, "/")
+	n := len(elems)
+
+	switch {
+	case depth > n:
+		return p
+	case depth == 1 && n > 1 && majorVersion.MatchString(elems[n-1]):
+		return elems[n-2]
+	default:
+		return strings.Join(elems[n-depth:], "/")
+	}
+}
@@ -0,0 +1,127 @@
+package configkit_test
+
+import (
+	. "github.com/dogmatiq/configkit"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("type TypeNameRenderer", func() {
+	names := []string{
+		"*github.com/acme/billing/domain.InvoiceIssued",
+		"github.com/acme/billing/domain.Generic[github.com/acme/billing/domain.InvoiceIssued,github.com/acme/shipping/domain.Shipped]",
+		"github.com/acme/shipping/domain.Shipped",
+		"github.com/acme/shipping/v2.Shipped",
+		"github.com/acme.Root",
+		"github.com/other/billing.Invoice",
+		"time.Duration",
+		"int",
+	}
+
+	DescribeTable(
+		"func Render()",
+		func(n string, expect string, options ...RenderOption) {
+			r := NewTypeNameRenderer(options...)
+			r.Add(names...)
+
+			Expect(r.Render(n)).To(Equal(expect))
+		},
+		Entry(
+			"no options",
+			"*github.com/acme/billing/domain.InvoiceIssued",
+			"*github.com/acme/billing/domain.InvoiceIssued",
+		),
+		Entry(
+			"module prefix",
+			"*github.com/acme/billing/domain.InvoiceIssued",
+			"*billing/domain.InvoiceIssued",
+			WithModulePrefix("github.com/acme"),
+		),
+		Entry(
+			"module prefix with trailing slash",
+			"*github.com/acme/billing/domain.InvoiceIssued",
+			"*billing/domain.InvoiceIssued",
+			WithModulePrefix("github.com/acme/"),
+		),
+		Entry(
+			"module prefix, type in the module's root package",
+			"github.com/acme.Root",
+			"acme.Root",
+			WithModulePrefix("github.com/acme"),
+		),
+		Entry(
+			"module prefix, type outside the module",
+			"time.Duration",
+			"time.Duration",
+			WithModulePrefix("github.com/acme"),
+		),
+		Entry(
+			"package names, unique name",
+			"time.Duration",
+			"time.Duration",
+			WithPackageNames(),
+		),
+		Entry(
+			"package names, colliding names",
+			"*github.com/acme/billing/domain.InvoiceIssued",
+			"*billing/domain.InvoiceIssued",
+			WithPackageNames(),
+		),
+		Entry(
+			"package names, major version suffix",
+			"github.com/acme/shipping/v2.Shipped",
+			"shipping.Shipped",
+			WithPackageNames(),
+		),
+		Entry(
+			"package names, name that matches an element of another path",
+			"github.com/other/billing.Invoice",
+			"billing.Invoice",
+			WithPackageNames(),
+		),
+		Entry(
+			"package names, predeclared type",
+			"int",
+			"int",
+			WithPackageNames(),
+		),
+		Entry(
+			"short type arguments",
+			"github.com/acme/billing/domain.Generic[github.com/acme/billing/domain.InvoiceIssued,github.com/acme/shipping/domain.Shipped]",
+			"github.com/acme/billing/domain.Generic[InvoiceIssued,github.com/acme/shipping/domain.Shipped]",
+			WithShortTypeArgs(),
+		),
+		Entry(
+			"all options",
+			"github.com/acme/billing/domain.Generic[github.com/acme/billing/domain.InvoiceIssued,github.com/acme/shipping/domain.Shipped]",
+			"billing/domain.Generic[InvoiceIssued,shipping/domain.Shipped]",
+			WithModulePrefix("github.com/acme"),
+			WithPackageNames(),
+			WithShortTypeArgs(),
+		),
+		Entry(
+			"package that has not been added",
+			"github.com/unknown/domain.Thing",
+			"github.com/unknown/domain.Thing",
+			WithPackageNames(),
+		),
+		Entry(
+			"invalid name",
+			"github.com/acme",
+			"github.com/acme",
+			WithPackageNames(),
+		),
+	)
+
+	It("disambiguates names that are added after a name has been rendered", func() {
+		r := NewTypeNameRenderer(WithPackageNames())
+		r.Add("github.com/acme/billing/domain.InvoiceIssued")
+
+		Expect(r.Render("github.com/acme/billing/domain.InvoiceIssued")).To(Equal("domain.InvoiceIssued"))
+
+		r.Add("github.com/acme/shipping/domain.Shipped")
+
+		Expect(r.Render("github.com/acme/billing/domain.InvoiceIssued")).To(Equal("billing/domain.InvoiceIssued"))
+	})
+})
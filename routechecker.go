@@ -0,0 +1,169 @@
+package configkit
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/enginekit/message"
+)
+
+// RouteChecker checks that the messages produced by a handler match the
+// routes in its configuration.
+//
+// It is intended for use by engines, which call [RouteChecker.Check] each time
+// a handler produces a message. It is safe for concurrent use.
+type RouteChecker struct {
+	handler RichHandler
+	types   EntityMessages[message.Type]
+}
+
+// NewRouteChecker returns a [RouteChecker] that checks the messages produced
+// by h.
+func NewRouteChecker(h RichHandler) *RouteChecker {
+	return &RouteChecker{
+		handler: h,
+		types:   h.MessageTypes(),
+	}
+}
+
+// MayProduce returns true if the handler is configured to produce messages of
+// type t.
+func (c *RouteChecker) MayProduce(t message.Type) bool {
+	return c.types[t].IsProduced
+}
+
+// Check returns a [RouteViolation] if the handler is not configured to produce
+// messages of type t.
+func (c *RouteChecker) Check(t message.Type) error {
+	if c.MayProduce(t) {
+		return nil
+	}
+
+	return &RouteViolation{
+		Handler:     c.handler.Identity(),
+		HandlerType: c.handler.HandlerType(),
+		MessageType: t,
+	}
+}
+
+// mustCheck panics with a [RouteViolation] if the handler is not configured to
+// produce m.
+func (c *RouteChecker) mustCheck(m dogma.Message) {
+	if err := c.Check(message.TypeOf(m)); err != nil {
+		panic(err)
+	}
+}
+
+// AggregateCommandScope returns a scope that panics with a [RouteViolation]
+// when the handler records an event that it is not configured to record, and
+// otherwise forwards to s.
+//
+// It is intended for use in tests.
+func (c *RouteChecker) AggregateCommandScope(s dogma.AggregateCommandScope) dogma.AggregateCommandScope {
+	return checkedAggregateCommandScope{s, c}
+}
+
+// ProcessEventScope returns a scope that panics with a [RouteViolation] when
+// the handler executes a command or schedules a timeout that it is not
+// configured to produce, and otherwise forwards to s.
+//
+// It is intended for use in tests.
+func (c *RouteChecker) ProcessEventScope(s dogma.ProcessEventScope) dogma.ProcessEventScope {
+	return checkedProcessEventScope{s, c}
+}
+
+// ProcessTimeoutScope returns a scope that panics with a [RouteViolation] when
+// the handler executes a command or schedules a timeout that it is not
+// configured to produce, and otherwise forwards to s.
+//
+// It is intended for use in tests.
+func (c *RouteChecker) ProcessTimeoutScope(s dogma.ProcessTimeoutScope) dogma.ProcessTimeoutScope {
+	return checkedProcessTimeoutScope{s, c}
+}
+
+// IntegrationCommandScope returns a scope that panics with a [RouteViolation]
+// when the handler records an event that it is not configured to record, and
+// otherwise forwards to s.
+//
+// It is intended for use in tests.
+func (c *RouteChecker) IntegrationCommandScope(s dogma.IntegrationCommandScope) dogma.IntegrationCommandScope {
+	return checkedIntegrationCommandScope{s, c}
+}
+
+// RouteViolation is an error that describes a handler producing a message that
+// it is not configured to produce.
+type RouteViolation struct {
+	// Handler is the identity of the handler that produced the message.
+	Handler Identity
+
+	// HandlerType is the type of the handler that produced the message.
+	HandlerType HandlerType
+
+	// MessageType is the type of the message that was produced.
+	MessageType message.Type
+}
+
+func (v *RouteViolation) Error() string {
+	kind := v.MessageType.Kind()
+
+	return fmt.Sprintf(
+		"the %s %s handler is not configured to %s %s %ss (missing %s() route)",
+		v.Handler,
+		v.HandlerType,
+		message.MapByKind(kind, "execute", "record", "schedule"),
+		v.MessageType,
+		kind,
+		message.MapByKind(kind, "ExecutesCommand", "RecordsEvent", "SchedulesTimeout"),
+	)
+}
+
+type checkedAggregateCommandScope struct {
+	dogma.AggregateCommandScope
+	checker *RouteChecker
+}
+
+func (s checkedAggregateCommandScope) RecordEvent(e dogma.Event) {
+	s.checker.mustCheck(e)
+	s.AggregateCommandScope.RecordEvent(e)
+}
+
+type checkedProcessEventScope struct {
+	dogma.ProcessEventScope
+	checker *RouteChecker
+}
+
+func (s checkedProcessEventScope) ExecuteCommand(c dogma.Command) {
+	s.checker.mustCheck(c)
+	s.ProcessEventScope.ExecuteCommand(c)
+}
+
+func (s checkedProcessEventScope) ScheduleTimeout(t dogma.Timeout, at time.Time) {
+	s.checker.mustCheck(t)
+	s.ProcessEventScope.ScheduleTimeout(t, at)
+}
+
+type checkedProcessTimeoutScope struct {
+	dogma.ProcessTimeoutScope
+	checker *RouteChecker
+}
+
+func (s checkedProcessTimeoutScope) ExecuteCommand(c dogma.Command) {
+	s.checker.mustCheck(c)
+	s.ProcessTimeoutScope.ExecuteCommand(c)
+}
+
+func (s checkedProcessTimeoutScope) ScheduleTimeout(t dogma.Timeout, at time.Time) {
+	s.checker.mustCheck(t)
+	s.ProcessTimeoutScope.ScheduleTimeout(t, at)
+}
+
+type checkedIntegrationCommandScope struct {
+	dogma.IntegrationCommandScope
+	checker *RouteChecker
+}
+
+func (s checkedIntegrationCommandScope) RecordEvent(e dogma.Event) {
+	s.checker.mustCheck(e)
+	s.IntegrationCommandScope.RecordEvent(e)
+}
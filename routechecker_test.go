@@ -0,0 +1,199 @@
+package configkit_test
+
+import (
+	"time"
+
+	. "github.com/dogmatiq/configkit"
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	"github.com/dogmatiq/enginekit/message"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("type RouteChecker", func() {
+	var (
+		aggregate   RichAggregate
+		process     RichProcess
+		integration RichIntegration
+	)
+
+	BeforeEach(func() {
+		aggregate = FromAggregate(&AggregateMessageHandlerStub{
+			ConfigureFunc: func(c dogma.AggregateConfigurer) {
+				c.Identity("<aggregate>", aggregateKey)
+				c.Routes(
+					dogma.HandlesCommand[*CommandStub[TypeA]](),
+					dogma.RecordsEvent[*EventStub[TypeA]](),
+				)
+			},
+		})
+
+		process = FromProcess(&ProcessMessageHandlerStub{
+			ConfigureFunc: func(c dogma.ProcessConfigurer) {
+				c.Identity("<process>", processKey)
+				c.Routes(
+					dogma.HandlesEvent[*EventStub[TypeA]](),
+					dogma.ExecutesCommand[*CommandStub[TypeA]](),
+					dogma.SchedulesTimeout[*TimeoutStub[TypeA]](),
+				)
+			},
+		})
+
+		integration = FromIntegration(&IntegrationMessageHandlerStub{
+			ConfigureFunc: func(c dogma.IntegrationConfigurer) {
+				c.Identity("<integration>", integrationKey)
+				c.Routes(
+					dogma.HandlesCommand[*CommandStub[TypeB]](),
+					dogma.RecordsEvent[*EventStub[TypeB]](),
+				)
+			},
+		})
+	})
+
+	Describe("func MayProduce()", func() {
+		It("returns true if the handler is configured to produce the message", func() {
+			c := NewRouteChecker(aggregate)
+			Expect(c.MayProduce(message.TypeOf(EventA1))).To(BeTrue())
+		})
+
+		It("returns false if the handler is not configured to produce the message", func() {
+			c := NewRouteChecker(aggregate)
+			Expect(c.MayProduce(message.TypeOf(EventB1))).To(BeFalse())
+		})
+
+		It("returns false if the handler only consumes the message", func() {
+			c := NewRouteChecker(aggregate)
+			Expect(c.MayProduce(message.TypeOf(CommandA1))).To(BeFalse())
+		})
+	})
+
+	Describe("func Check()", func() {
+		It("returns nil if the handler is configured to produce the message", func() {
+			c := NewRouteChecker(process)
+			Expect(c.Check(message.TypeOf(CommandA1))).To(Succeed())
+			Expect(c.Check(message.TypeOf(TimeoutA1))).To(Succeed())
+		})
+
+		It("returns a violation if the handler is not configured to produce the message", func() {
+			c := NewRouteChecker(process)
+
+			err := c.Check(message.TypeOf(CommandB1))
+			Expect(err).To(Equal(&RouteViolation{
+				Handler:     process.Identity(),
+				HandlerType: ProcessHandlerType,
+				MessageType: message.TypeOf(CommandB1),
+			}))
+			Expect(err).To(MatchError(
+				"the <process>/" + processKey + " process handler is not configured to execute *stubs.CommandStub[TypeB] commands (missing ExecutesCommand() route)",
+			))
+		})
+	})
+
+	Describe("func AggregateCommandScope()", func() {
+		It("forwards events that the handler is configured to record", func() {
+			inner := &producerScopeStub{}
+			s := NewRouteChecker(aggregate).AggregateCommandScope(inner)
+
+			s.RecordEvent(EventA1)
+			Expect(inner.Produced).To(ConsistOf(EventA1))
+		})
+
+		It("panics if the handler is not configured to record the event", func() {
+			inner := &producerScopeStub{}
+			s := NewRouteChecker(aggregate).AggregateCommandScope(inner)
+
+			Expect(func() {
+				s.RecordEvent(EventB1)
+			}).To(PanicWith(BeAssignableToTypeOf(&RouteViolation{})))
+			Expect(inner.Produced).To(BeEmpty())
+		})
+	})
+
+	Describe("func ProcessEventScope()", func() {
+		It("forwards messages that the handler is configured to produce", func() {
+			inner := &producerScopeStub{}
+			s := NewRouteChecker(process).ProcessEventScope(inner)
+
+			s.ExecuteCommand(CommandA1)
+			s.ScheduleTimeout(TimeoutA1, time.Now())
+			Expect(inner.Produced).To(ConsistOf(CommandA1, TimeoutA1))
+		})
+
+		It("panics if the handler is not configured to produce the message", func() {
+			inner := &producerScopeStub{}
+			s := NewRouteChecker(process).ProcessEventScope(inner)
+
+			Expect(func() {
+				s.ExecuteCommand(CommandB1)
+			}).To(PanicWith(BeAssignableToTypeOf(&RouteViolation{})))
+			Expect(func() {
+				s.ScheduleTimeout(TimeoutB1, time.Now())
+			}).To(PanicWith(BeAssignableToTypeOf(&RouteViolation{})))
+			Expect(inner.Produced).To(BeEmpty())
+		})
+	})
+
+	Describe("func ProcessTimeoutScope()", func() {
+		It("forwards messages that the handler is configured to produce", func() {
+			inner := &producerScopeStub{}
+			s := NewRouteChecker(process).ProcessTimeoutScope(inner)
+
+			s.ExecuteCommand(CommandA1)
+			s.ScheduleTimeout(TimeoutA1, time.Now())
+			Expect(inner.Produced).To(ConsistOf(CommandA1, TimeoutA1))
+		})
+
+		It("panics if the handler is not configured to produce the message", func() {
+			inner := &producerScopeStub{}
+			s := NewRouteChecker(process).ProcessTimeoutScope(inner)
+
+			Expect(func() {
+				s.ExecuteCommand(CommandB1)
+			}).To(PanicWith(BeAssignableToTypeOf(&RouteViolation{})))
+			Expect(func() {
+				s.ScheduleTimeout(TimeoutB1, time.Now())
+			}).To(PanicWith(BeAssignableToTypeOf(&RouteViolation{})))
+			Expect(inner.Produced).To(BeEmpty())
+		})
+	})
+
+	Describe("func IntegrationCommandScope()", func() {
+		It("forwards events that the handler is configured to record", func() {
+			inner := &producerScopeStub{}
+			s := NewRouteChecker(integration).IntegrationCommandScope(inner)
+
+			s.RecordEvent(EventB1)
+			Expect(inner.Produced).To(ConsistOf(EventB1))
+		})
+
+		It("panics if the handler is not configured to record the event", func() {
+			inner := &producerScopeStub{}
+			s := NewRouteChecker(integration).IntegrationCommandScope(inner)
+
+			Expect(func() {
+				s.RecordEvent(EventA1)
+			}).To(PanicWith(BeAssignableToTypeOf(&RouteViolation{})))
+			Expect(inner.Produced).To(BeEmpty())
+		})
+	})
+})
+
+// producerScopeStub is a test implementation of the Dogma scope interfaces
+// that allow a handler to produce messages. It records the messages that are
+// produced.
+//
+// It embeds [dogma.ProcessTimeoutScope] so that it implements the methods that
+// are not used by the tests.
+type producerScopeStub struct {
+	dogma.ProcessTimeoutScope
+	Produced []dogma.Message
+}
+
+func (s *producerScopeStub) RecordedAt() time.Time          { return time.Time{} }
+func (s *producerScopeStub) RecordEvent(e dogma.Event)      { s.Produced = append(s.Produced, e) }
+func (s *producerScopeStub) ExecuteCommand(c dogma.Command) { s.Produced = append(s.Produced, c) }
+
+func (s *producerScopeStub) ScheduleTimeout(t dogma.Timeout, _ time.Time) {
+	s.Produced = append(s.Produced, t)
+}
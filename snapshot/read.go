@@ -0,0 +1,192 @@
+package snapshot
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/dogmatiq/configkit"
+	"github.com/dogmatiq/enginekit/protobuf/configpb"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Read reads a snapshot from r.
+//
+// It returns an error if the snapshot uses a schema version that is newer than
+// [Version], or if the fingerprint recorded for any application does not match
+// the fingerprint of its configuration.
+func Read(r io.Reader) (Snapshot, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	var header struct {
+		Version int `json:"schema_version"`
+	}
+
+	if err := json.Unmarshal(data, &header); err != nil {
+		return Snapshot{}, fmt.Errorf("invalid snapshot: %w", err)
+	}
+
+	switch header.Version {
+	case 0:
+		return Snapshot{}, errors.New("invalid snapshot: schema version is not specified")
+	case 1:
+		return readV1(data)
+	case 2:
+		return readV2(data)
+	default:
+		return Snapshot{}, fmt.Errorf(
+			"unsupported snapshot schema version (%d), the latest supported version is %d",
+			header.Version,
+			Version,
+		)
+	}
+}
+
+// ReadFile reads a snapshot from the file at the given path.
+func ReadFile(path string) (Snapshot, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	defer f.Close()
+
+	s, err := Read(f)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return s, nil
+}
+
+// documentV1 is version 1 of the snapshot schema.
+type documentV1 struct {
+	Version      int               `json:"schema_version"`
+	CreatedAt    time.Time         `json:"created_at"`
+	Build        map[string]string `json:"build,omitempty"`
+	Applications []applicationV1   `json:"applications"`
+}
+
+// applicationV1 is an application within version 1 of the snapshot schema.
+type applicationV1 struct {
+	Fingerprint string          `json:"fingerprint"`
+	Config      json.RawMessage `json:"config"`
+}
+
+// documentV2 is version 2 of the snapshot schema. It adds the metadata of each
+// application and its handlers.
+type documentV2 struct {
+	Version      int               `json:"schema_version"`
+	CreatedAt    time.Time         `json:"created_at"`
+	Build        map[string]string `json:"build,omitempty"`
+	Applications []applicationV2   `json:"applications"`
+}
+
+// applicationV2 is an application within version 2 of the snapshot schema.
+type applicationV2 struct {
+	Fingerprint string                `json:"fingerprint"`
+	Config      json.RawMessage       `json:"config"`
+	Metadata    configkit.MetadataSet `json:"metadata,omitempty"`
+}
+
+func readV1(data []byte) (Snapshot, error) {
+	var doc documentV1
+	if err := decode(data, &doc); err != nil {
+		return Snapshot{}, err
+	}
+
+	s := Snapshot{
+		Version:   doc.Version,
+		CreatedAt: doc.CreatedAt,
+		Build:     doc.Build,
+	}
+
+	for i, in := range doc.Applications {
+		app, err := readApplication(i, in.Fingerprint, in.Config)
+		if err != nil {
+			return Snapshot{}, err
+		}
+
+		s.Applications = append(s.Applications, app)
+	}
+
+	return s, nil
+}
+
+func readV2(data []byte) (Snapshot, error) {
+	var doc documentV2
+	if err := decode(data, &doc); err != nil {
+		return Snapshot{}, err
+	}
+
+	s := Snapshot{
+		Version:   doc.Version,
+		CreatedAt: doc.CreatedAt,
+		Build:     doc.Build,
+	}
+
+	for i, in := range doc.Applications {
+		app, err := readApplication(
+			i,
+			in.Fingerprint,
+			in.Config,
+			configkit.WithMetadata(in.Metadata),
+		)
+		if err != nil {
+			return Snapshot{}, err
+		}
+
+		s.Applications = append(s.Applications, app)
+	}
+
+	return s, nil
+}
+
+// decode decodes the JSON document in data into doc, rejecting any fields
+// that are not part of the schema.
+func decode(data []byte, doc any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(doc); err != nil {
+		return fmt.Errorf("invalid snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// readApplication reads the i'th application in a snapshot from the JSON
+// representation of its configuration, and verifies that it matches its
+// fingerprint.
+func readApplication(
+	i int,
+	fingerprint string,
+	config json.RawMessage,
+	options ...configkit.ConfigureOption,
+) (configkit.Application, error) {
+	apb := &configpb.Application{}
+	if err := protojson.Unmarshal(config, apb); err != nil {
+		return nil, fmt.Errorf("invalid snapshot: application #%d: %w", i, err)
+	}
+
+	app, err := configkit.FromProto(apb, options...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid snapshot: application #%d: %w", i, err)
+	}
+
+	if hex.EncodeToString(configkit.Fingerprint(app)) != fingerprint {
+		return nil, fmt.Errorf(
+			"invalid snapshot: application %s does not match its fingerprint",
+			app.Identity(),
+		)
+	}
+
+	return app, nil
+}
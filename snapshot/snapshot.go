@@ -0,0 +1,51 @@
+// Package snapshot reads and writes configuration snapshots, which are
+// self-describing files that archive the configuration of one or more
+// applications.
+//
+// A snapshot is a JSON document that contains the protocol buffers
+// representation of each application, as produced by [configkit.ToProto],
+// along with the version of the snapshot schema, the time at which the
+// snapshot was created, the fingerprint of each application and free-form
+// metadata about the build that produced it.
+package snapshot
+
+import (
+	"time"
+
+	"github.com/dogmatiq/configkit"
+)
+
+// Version is the version of the snapshot schema produced by [Write].
+//
+// [Read] accepts snapshots that use this version of the schema, or any prior
+// version.
+const Version = 2
+
+// Snapshot is an archived copy of the configuration of one or more
+// applications.
+type Snapshot struct {
+	// Version is the version of the schema that the snapshot was encoded
+	// with. It is populated by [Read] and ignored by [Write].
+	Version int
+
+	// CreatedAt is the time at which the snapshot was created. If it is zero,
+	// [Write] uses the current time.
+	CreatedAt time.Time
+
+	// Build is free-form metadata about the build that produced the
+	// applications, such as a version number or commit hash.
+	Build map[string]string
+
+	// Applications is the configuration of each application in the snapshot.
+	Applications []configkit.Application
+}
+
+// New returns a snapshot of the given applications, created at the current
+// time.
+func New(apps ...configkit.Application) Snapshot {
+	return Snapshot{
+		Version:      Version,
+		CreatedAt:    time.Now(),
+		Applications: apps,
+	}
+}
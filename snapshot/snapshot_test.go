@@ -0,0 +1,236 @@
+package snapshot_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dogmatiq/configkit"
+	. "github.com/dogmatiq/configkit/snapshot"
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("type Snapshot", func() {
+	var app1, app2 configkit.Application
+
+	BeforeEach(func() {
+		app1 = configkit.FromApplication(&ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app-1>", "b1101bbf-8a62-436d-9044-e6fd3d0e5385")
+				c.Routes(
+					dogma.ViaAggregate(&AggregateMessageHandlerStub{
+						ConfigureFunc: func(c dogma.AggregateConfigurer) {
+							c.Identity("<aggregate>", "938b829d-e4d7-4780-bf06-ea349453ba8f")
+							c.Routes(
+								dogma.HandlesCommand[*CommandStub[TypeA]](),
+								dogma.RecordsEvent[*EventStub[TypeA]](),
+							)
+						},
+					}),
+				)
+			},
+		})
+
+		app2 = configkit.FromApplication(&ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app-2>", "7d3927ce-d879-40a4-bd67-0fafc79d3c36")
+				c.Routes(
+					dogma.ViaProjection(&ProjectionMessageHandlerStub{
+						ConfigureFunc: func(c dogma.ProjectionConfigurer) {
+							c.Identity("<projection>", "280a58bd-f154-46d7-863b-23ce70e49d2a")
+							c.Routes(
+								dogma.HandlesEvent[*EventStub[TypeA]](),
+							)
+							c.Disable()
+						},
+					}),
+				)
+			},
+		})
+	})
+
+	Describe("func New()", func() {
+		It("returns a snapshot of the applications", func() {
+			before := time.Now()
+			s := New(app1, app2)
+
+			Expect(s.Version).To(Equal(Version))
+			Expect(s.CreatedAt).To(BeTemporally(">=", before))
+			Expect(s.Applications).To(Equal([]configkit.Application{app1, app2}))
+		})
+	})
+
+	Describe("func Write() and Read()", func() {
+		It("round-trips the snapshot", func() {
+			createdAt := time.Date(2025, 10, 6, 12, 30, 0, 0, time.UTC)
+
+			var buf bytes.Buffer
+			err := Write(&buf, Snapshot{
+				CreatedAt: createdAt,
+				Build: map[string]string{
+					"version": "1.2.3",
+					"commit":  "abc123",
+				},
+				Applications: []configkit.Application{app1, app2},
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			s, err := Read(&buf)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Expect(s.Version).To(Equal(Version))
+			Expect(s.CreatedAt).To(BeTemporally("==", createdAt))
+			Expect(s.Build).To(Equal(map[string]string{
+				"version": "1.2.3",
+				"commit":  "abc123",
+			}))
+			Expect(s.Applications).To(HaveLen(2))
+			Expect(configkit.IsApplicationEqual(s.Applications[0], app1)).To(BeTrue())
+			Expect(configkit.IsApplicationEqual(s.Applications[1], app2)).To(BeTrue())
+		})
+
+		It("round-trips the metadata of each application", func() {
+			app := configkit.FromApplication(
+				app1.(configkit.RichApplication).Application(),
+				configkit.WithMetadata(configkit.MetadataSet{
+					"b1101bbf-8a62-436d-9044-e6fd3d0e5385": {configkit.OwnerMetadataKey: "<team>"},
+					"938b829d-e4d7-4780-bf06-ea349453ba8f": {configkit.OnCallMetadataKey: "<rotation>"},
+				}),
+			)
+
+			var buf bytes.Buffer
+			err := Write(&buf, New(app))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			s, err := Read(&buf)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(s.Applications).To(HaveLen(1))
+			Expect(configkit.IsApplicationEqual(s.Applications[0], app)).To(BeTrue())
+			Expect(configkit.CollectMetadata(s.Applications[0])).To(Equal(configkit.CollectMetadata(app)))
+		})
+
+		It("records the schema version and the fingerprint of each application", func() {
+			var buf bytes.Buffer
+			err := Write(&buf, New(app1))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Expect(buf.String()).To(ContainSubstring(`"schema_version": 2`))
+			Expect(buf.String()).To(ContainSubstring(`"fingerprint": "`))
+		})
+
+		It("uses the current time if the creation time is zero", func() {
+			before := time.Now()
+
+			var buf bytes.Buffer
+			err := Write(&buf, Snapshot{})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			s, err := Read(&buf)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(s.CreatedAt).To(BeTemporally(">=", before.Truncate(time.Second)))
+			Expect(s.Applications).To(BeEmpty())
+		})
+	})
+
+	Describe("func Read()", func() {
+		It("returns an error if the schema version is not specified", func() {
+			_, err := Read(strings.NewReader(`{"applications": []}`))
+			Expect(err).To(MatchError("invalid snapshot: schema version is not specified"))
+		})
+
+		It("returns an error if the schema version is not supported", func() {
+			_, err := Read(strings.NewReader(`{"schema_version": 999}`))
+			Expect(err).To(MatchError("unsupported snapshot schema version (999), the latest supported version is 2"))
+		})
+
+		It("reads a version 1 snapshot", func() {
+			s, err := ReadFile("testdata/v1.json")
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Expect(s.Version).To(Equal(1))
+			Expect(s.CreatedAt).To(BeTemporally("==", time.Date(2025, 10, 6, 12, 30, 0, 0, time.UTC)))
+			Expect(s.Build).To(Equal(map[string]string{"version": "1.2.3"}))
+			Expect(s.Applications).To(HaveLen(1))
+			Expect(configkit.IsApplicationEqual(s.Applications[0], app1)).To(BeTrue())
+		})
+
+		It("returns an error if a version 1 snapshot contains metadata", func() {
+			_, err := Read(strings.NewReader(`{
+				"schema_version": 1,
+				"applications": [
+					{"fingerprint": "", "config": {}, "metadata": {}}
+				]
+			}`))
+			Expect(err).To(MatchError(`invalid snapshot: json: unknown field "metadata"`))
+		})
+
+		It("returns an error if the snapshot is not valid JSON", func() {
+			_, err := Read(strings.NewReader(`{`))
+			Expect(err).To(MatchError(HavePrefix("invalid snapshot: ")))
+		})
+
+		It("returns an error if an application does not match its fingerprint", func() {
+			var buf bytes.Buffer
+			err := Write(&buf, New(app1))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			data := strings.Replace(buf.String(), "<aggregate>", "<tampered>", 1)
+
+			_, err = Read(strings.NewReader(data))
+			Expect(err).To(MatchError(
+				"invalid snapshot: application <app-1>/b1101bbf-8a62-436d-9044-e6fd3d0e5385 does not match its fingerprint",
+			))
+		})
+
+		It("returns an error if an application is invalid", func() {
+			_, err := Read(strings.NewReader(`{
+				"schema_version": 1,
+				"applications": [
+					{"fingerprint": "", "config": {"identity": {"name": ""}}}
+				]
+			}`))
+			Expect(err).To(MatchError(HavePrefix("invalid snapshot: application #0: ")))
+		})
+	})
+
+	Describe("func ReadFile()", func() {
+		var path string
+
+		BeforeEach(func() {
+			dir, err := os.MkdirTemp("", "configkit-snapshot-")
+			Expect(err).ShouldNot(HaveOccurred())
+
+			path = filepath.Join(dir, "config.json")
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(filepath.Dir(path))
+		})
+
+		It("reads a snapshot from a file", func() {
+			var buf bytes.Buffer
+			err := Write(&buf, New(app1))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			err = os.WriteFile(path, buf.Bytes(), 0o600)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			s, err := ReadFile(path)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(s.Applications).To(HaveLen(1))
+		})
+
+		It("includes the path in error messages", func() {
+			err := os.WriteFile(path, []byte(`{}`), 0o600)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			_, err = ReadFile(path)
+			Expect(err).To(MatchError(path + ": invalid snapshot: schema version is not specified"))
+		})
+	})
+})
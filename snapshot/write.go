@@ -0,0 +1,60 @@
+package snapshot
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/dogmatiq/configkit"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Write writes s to w using the current version of the snapshot schema.
+func Write(w io.Writer, s Snapshot) error {
+	doc := documentV2{
+		Version:      Version,
+		CreatedAt:    s.CreatedAt.UTC(),
+		Build:        s.Build,
+		Applications: []applicationV2{},
+	}
+
+	if doc.CreatedAt.IsZero() {
+		doc.CreatedAt = time.Now().UTC()
+	}
+
+	for _, app := range s.Applications {
+		apb, err := configkit.ToProto(app)
+		if err != nil {
+			return fmt.Errorf(
+				"unable to marshal application %s: %w",
+				app.Identity(),
+				err,
+			)
+		}
+
+		data, err := protojson.Marshal(apb)
+		if err != nil {
+			return fmt.Errorf(
+				"unable to marshal application %s: %w",
+				app.Identity(),
+				err,
+			)
+		}
+
+		// The JSON representation of the configuration does not include its
+		// metadata, so it is stored separately.
+		doc.Applications = append(doc.Applications, applicationV2{
+			Fingerprint: hex.EncodeToString(configkit.Fingerprint(app)),
+			Config:      data,
+			Metadata:    configkit.CollectMetadata(app),
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+
+	return enc.Encode(doc)
+}
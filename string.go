@@ -3,8 +3,8 @@ package configkit
 import (
 	"context"
 	"io"
-	"iter"
-	"sort"
+	"maps"
+	"slices"
 	"strings"
 
 	"github.com/dogmatiq/enginekit/message"
@@ -13,12 +13,18 @@ import (
 )
 
 // ToString returns a human-readable string representation of the given entity.
-func ToString(e Entity) string {
+//
+// By default, type names are fully-qualified. The options may be used to render
+// abbreviated type names instead.
+func ToString(e Entity, options ...RenderOption) string {
 	var b strings.Builder
 
+	names := NewTypeNameRenderer(options...)
+	names.AddEntity(e)
+
 	if err := e.AcceptVisitor(
 		context.Background(),
-		&stringer{w: &b},
+		&stringer{w: &b, names: names},
 	); err != nil {
 		panic(err)
 	}
@@ -28,7 +34,8 @@ func ToString(e Entity) string {
 
 // stringer is a Visitor that builds string representations of entities.
 type stringer struct {
-	w io.Writer
+	w     io.Writer
+	names *TypeNameRenderer
 }
 
 func (s *stringer) VisitApplication(ctx context.Context, cfg Application) error {
@@ -39,25 +46,58 @@ func (s *stringer) VisitApplication(ctx context.Context, cfg Application) error
 		"application %s (%s) %s\n",
 		id.Name,
 		id.Key,
-		cfg.TypeName(),
+		s.names.Render(cfg.TypeName()),
 	)
 
 	v := &stringer{
-		w: indent.NewIndenter(s.w, nil),
+		w:     indent.NewIndenter(s.w, nil),
+		names: s.names,
 	}
 
-	for _, h := range sortHandlers(cfg.Handlers()) {
-		must.WriteByte(s.w, '\n')
-		must.WriteString(v.w, "- ")
+	// Handlers that are part of a module are rendered after all other
+	// handlers, grouped by module.
+	modules := map[string][]Handler{}
 
-		if err := h.AcceptVisitor(ctx, v); err != nil {
+	for h := range cfg.Handlers().Sorted() {
+		if m, ok := moduleNameOf(h); ok {
+			modules[m] = append(modules[m], h)
+			continue
+		}
+
+		if err := v.visitListItem(ctx, s.w, h); err != nil {
 			return err
 		}
 	}
 
+	for _, m := range slices.Sorted(maps.Keys(modules)) {
+		must.WriteByte(s.w, '\n')
+		must.Fprintf(v.w, "module %s\n", m)
+
+		mv := &stringer{
+			w:     indent.NewIndenter(v.w, nil),
+			names: s.names,
+		}
+
+		for _, h := range modules[m] {
+			if err := mv.visitListItem(ctx, s.w, h); err != nil {
+				return err
+			}
+		}
+	}
+
 	return nil
 }
 
+// visitListItem writes h as an item in a list of handlers.
+//
+// The blank line that separates items is written to w, which is the unindented
+// writer of the list's parent.
+func (s *stringer) visitListItem(ctx context.Context, w io.Writer, h Handler) error {
+	must.WriteByte(w, '\n')
+	must.WriteString(s.w, "- ")
+	return h.AcceptVisitor(ctx, s)
+}
+
 func (s *stringer) visitHandler(cfg Handler) error {
 	id := cfg.Identity()
 
@@ -77,30 +117,28 @@ func (s *stringer) visitHandler(cfg Handler) error {
 		cfg.HandlerType(),
 		id.Name,
 		id.Key,
-		cfg.TypeName(),
+		s.names.Render(cfg.TypeName()),
 		flagString,
 	)
 
 	names := cfg.MessageNames()
 
-	for _, p := range sortNameKinds(names.Consumed()) {
-		if p.Kind != message.TimeoutKind {
-			must.Fprintf(
-				s.w,
-				"    handles %s%s\n",
-				p.Name,
-				p.Kind.Symbol(),
-			)
-		}
+	for n, k := range names.SortedConsumed(message.CommandKind, message.EventKind) {
+		must.Fprintf(
+			s.w,
+			"    handles %s%s\n",
+			s.names.Render(string(n)),
+			k.Symbol(),
+		)
 	}
 
-	for _, p := range sortNameKinds(names.Produced()) {
+	for n, k := range names.SortedProduced() {
 		must.Fprintf(
 			s.w,
 			"    %s %s%s\n",
-			message.MapByKind(p.Kind, "executes", "records", "schedules"),
-			p.Name,
-			p.Kind.Symbol(),
+			message.MapByKind(k, "executes", "records", "schedules"),
+			s.names.Render(string(n)),
+			k.Symbol(),
 		)
 	}
 
@@ -122,50 +160,3 @@ func (s *stringer) VisitIntegration(_ context.Context, cfg Integration) error {
 func (s *stringer) VisitProjection(_ context.Context, cfg Projection) error {
 	return s.visitHandler(cfg)
 }
-
-// sortHandlers returns a set of handlers sorted by their name.
-func sortHandlers(handlers HandlerSet) []Handler {
-	sorted := make([]Handler, 0, len(handlers))
-
-	for _, h := range handlers {
-		sorted = append(sorted, h)
-	}
-
-	sort.Slice(sorted, func(i, j int) bool {
-		return sorted[i].Identity().Name < sorted[j].Identity().Name
-	})
-
-	return sorted
-}
-
-type pair struct {
-	Name message.Name
-	Kind message.Kind
-}
-
-// sortNameKinds returns a list of name/kind pairs, sorted by name.
-// Timeout messages are always sorted towards the end.
-func sortNameKinds(
-	names iter.Seq2[message.Name, message.Kind],
-) []pair {
-	var pairs []pair
-	for n, k := range names {
-		pairs = append(pairs, pair{n, k})
-	}
-
-	sort.Slice(
-		pairs,
-		func(i, j int) bool {
-			pi := pairs[i]
-			pj := pairs[j]
-
-			if pi.Kind == message.TimeoutKind && pj.Kind != message.TimeoutKind {
-				return false
-			}
-
-			return pi.Name < pj.Name
-		},
-	)
-
-	return pairs
-}
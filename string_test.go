@@ -64,22 +64,22 @@ var _ = Describe("func ToString()", func() {
 		cfg = FromApplication(app)
 	})
 
-	It("returns a human readable string representation", func() {
+	It("returns a human readable string representation, with handlers ordered by type then name", func() {
 		expected := "application <app> (59a82a24-a181-41e8-9b93-17a6ce86956e) *github.com/dogmatiq/enginekit/enginetest/stubs.ApplicationStub\n"
 		expected += "\n"
 		expected += "    - aggregate <aggregate> (14769f7f-87fe-48dd-916e-5bcab6ba6aca) *github.com/dogmatiq/enginekit/enginetest/stubs.AggregateMessageHandlerStub\n"
 		expected += "        handles *github.com/dogmatiq/enginekit/enginetest/stubs.CommandStub[github.com/dogmatiq/enginekit/enginetest/stubs.TypeA]?\n"
 		expected += "        records *github.com/dogmatiq/enginekit/enginetest/stubs.EventStub[github.com/dogmatiq/enginekit/enginetest/stubs.TypeA]!\n"
 		expected += "\n"
-		expected += "    - integration <integration> (e28f056e-e5a0-4ee7-aaf1-1d1fe02fb6e3) *github.com/dogmatiq/enginekit/enginetest/stubs.IntegrationMessageHandlerStub\n"
-		expected += "        handles *github.com/dogmatiq/enginekit/enginetest/stubs.CommandStub[github.com/dogmatiq/enginekit/enginetest/stubs.TypeB]?\n"
-		expected += "        records *github.com/dogmatiq/enginekit/enginetest/stubs.EventStub[github.com/dogmatiq/enginekit/enginetest/stubs.TypeB]!\n"
-		expected += "\n"
 		expected += "    - process <process> (bea52cf4-e403-4b18-819d-88ade7836308) *github.com/dogmatiq/enginekit/enginetest/stubs.ProcessMessageHandlerStub\n"
 		expected += "        handles *github.com/dogmatiq/enginekit/enginetest/stubs.EventStub[github.com/dogmatiq/enginekit/enginetest/stubs.TypeA]!\n"
 		expected += "        executes *github.com/dogmatiq/enginekit/enginetest/stubs.CommandStub[github.com/dogmatiq/enginekit/enginetest/stubs.TypeA]?\n"
 		expected += "        schedules *github.com/dogmatiq/enginekit/enginetest/stubs.TimeoutStub[github.com/dogmatiq/enginekit/enginetest/stubs.TypeA]@\n"
 		expected += "\n"
+		expected += "    - integration <integration> (e28f056e-e5a0-4ee7-aaf1-1d1fe02fb6e3) *github.com/dogmatiq/enginekit/enginetest/stubs.IntegrationMessageHandlerStub\n"
+		expected += "        handles *github.com/dogmatiq/enginekit/enginetest/stubs.CommandStub[github.com/dogmatiq/enginekit/enginetest/stubs.TypeB]?\n"
+		expected += "        records *github.com/dogmatiq/enginekit/enginetest/stubs.EventStub[github.com/dogmatiq/enginekit/enginetest/stubs.TypeB]!\n"
+		expected += "\n"
 		expected += "    - projection <projection> (70fdf7fa-4b24-448d-bd29-7ecc71d18c56) *github.com/dogmatiq/enginekit/enginetest/stubs.ProjectionMessageHandlerStub [disabled]\n"
 		expected += "        handles *github.com/dogmatiq/enginekit/enginetest/stubs.EventStub[github.com/dogmatiq/enginekit/enginetest/stubs.TypeA]!\n"
 		expected += "        handles *github.com/dogmatiq/enginekit/enginetest/stubs.EventStub[github.com/dogmatiq/enginekit/enginetest/stubs.TypeB]!\n"
@@ -94,4 +94,187 @@ var _ = Describe("func ToString()", func() {
 			Equal(strings.Split(expected, "\n")),
 		)
 	})
+
+	It("renders abbreviated type names when options are provided", func() {
+		expected := "application <app> (59a82a24-a181-41e8-9b93-17a6ce86956e) *stubs.ApplicationStub\n"
+		expected += "\n"
+		expected += "    - aggregate <aggregate> (14769f7f-87fe-48dd-916e-5bcab6ba6aca) *stubs.AggregateMessageHandlerStub\n"
+		expected += "        handles *stubs.CommandStub[TypeA]?\n"
+		expected += "        records *stubs.EventStub[TypeA]!\n"
+		expected += "\n"
+		expected += "    - process <process> (bea52cf4-e403-4b18-819d-88ade7836308) *stubs.ProcessMessageHandlerStub\n"
+		expected += "        handles *stubs.EventStub[TypeA]!\n"
+		expected += "        executes *stubs.CommandStub[TypeA]?\n"
+		expected += "        schedules *stubs.TimeoutStub[TypeA]@\n"
+		expected += "\n"
+		expected += "    - integration <integration> (e28f056e-e5a0-4ee7-aaf1-1d1fe02fb6e3) *stubs.IntegrationMessageHandlerStub\n"
+		expected += "        handles *stubs.CommandStub[TypeB]?\n"
+		expected += "        records *stubs.EventStub[TypeB]!\n"
+		expected += "\n"
+		expected += "    - projection <projection> (70fdf7fa-4b24-448d-bd29-7ecc71d18c56) *stubs.ProjectionMessageHandlerStub [disabled]\n"
+		expected += "        handles *stubs.EventStub[TypeA]!\n"
+		expected += "        handles *stubs.EventStub[TypeB]!\n"
+
+		s := ToString(
+			cfg,
+			WithPackageNames(),
+			WithShortTypeArgs(),
+		)
+
+		Expect(
+			strings.Split(s, "\n"),
+		).To(
+			Equal(strings.Split(expected, "\n")),
+		)
+	})
+
+	It("groups handlers that are part of a module", func() {
+		module := NewModule(
+			"<auditing>",
+			moduleNamespace,
+			dogma.ViaProjection(&ProjectionMessageHandlerStub{
+				ConfigureFunc: func(c dogma.ProjectionConfigurer) {
+					c.Identity("<audit-log>", projectionKey)
+					c.Routes(
+						dogma.HandlesEvent[*EventStub[TypeA]](),
+					)
+				},
+			}),
+		)
+
+		cfg := FromApplication(&ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("<app>", appKey)
+				c.Routes(module.Routes()...)
+				c.Routes(
+					dogma.ViaAggregate(&AggregateMessageHandlerStub{
+						ConfigureFunc: func(c dogma.AggregateConfigurer) {
+							c.Identity("<aggregate>", aggregateKey)
+							c.Routes(
+								dogma.HandlesCommand[*CommandStub[TypeA]](),
+								dogma.RecordsEvent[*EventStub[TypeA]](),
+							)
+						},
+					}),
+				)
+			},
+		})
+
+		expected := "application <app> (59a82a24-a181-41e8-9b93-17a6ce86956e) *stubs.ApplicationStub\n"
+		expected += "\n"
+		expected += "    - aggregate <aggregate> (14769f7f-87fe-48dd-916e-5bcab6ba6aca) *stubs.AggregateMessageHandlerStub\n"
+		expected += "        handles *stubs.CommandStub[TypeA]?\n"
+		expected += "        records *stubs.EventStub[TypeA]!\n"
+		expected += "\n"
+		expected += "    module <auditing>\n"
+		expected += "\n"
+		expected += "        - projection <audit-log> (" + module.HandlerKey(appKey, projectionKey) + ") *stubs.ProjectionMessageHandlerStub\n"
+		expected += "            handles *stubs.EventStub[TypeA]!\n"
+
+		s := ToString(
+			cfg,
+			WithPackageNames(),
+			WithShortTypeArgs(),
+		)
+
+		Expect(
+			strings.Split(s, "\n"),
+		).To(
+			Equal(strings.Split(expected, "\n")),
+		)
+	})
+
+	It("does not group handlers that have module metadata but are not part of a module", func() {
+		cfg := FromApplication(
+			&ApplicationStub{
+				ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+					c.Identity("<app>", appKey)
+					c.Routes(
+						dogma.ViaAggregate(&AggregateMessageHandlerStub{
+							ConfigureFunc: func(c dogma.AggregateConfigurer) {
+								c.Identity("<aggregate>", aggregateKey)
+								c.Routes(
+									dogma.HandlesCommand[*CommandStub[TypeA]](),
+									dogma.RecordsEvent[*EventStub[TypeA]](),
+								)
+							},
+						}),
+					)
+				},
+			},
+			WithMetadata(MetadataSet{
+				aggregateKey: {"module": "<auditing>"},
+			}),
+		)
+
+		expected := "application <app> (59a82a24-a181-41e8-9b93-17a6ce86956e) *stubs.ApplicationStub\n"
+		expected += "\n"
+		expected += "    - aggregate <aggregate> (14769f7f-87fe-48dd-916e-5bcab6ba6aca) *stubs.AggregateMessageHandlerStub\n"
+		expected += "        handles *stubs.CommandStub[TypeA]?\n"
+		expected += "        records *stubs.EventStub[TypeA]!\n"
+
+		s := ToString(
+			cfg,
+			WithPackageNames(),
+			WithShortTypeArgs(),
+		)
+
+		Expect(
+			strings.Split(s, "\n"),
+		).To(
+			Equal(strings.Split(expected, "\n")),
+		)
+	})
+
+	It("groups overlaid handlers that are part of a module", func() {
+		module := NewModule(
+			"<auditing>",
+			moduleNamespace,
+			dogma.ViaProjection(&ProjectionMessageHandlerStub{
+				ConfigureFunc: func(c dogma.ProjectionConfigurer) {
+					c.Identity("<audit-log>", projectionKey)
+					c.Routes(
+						dogma.HandlesEvent[*EventStub[TypeA]](),
+					)
+				},
+			}),
+		)
+
+		key := module.HandlerKey(appKey, projectionKey)
+
+		res, err := ApplyOverlays(
+			FromApplication(&ApplicationStub{
+				ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+					c.Identity("<app>", appKey)
+					c.Routes(module.Routes()...)
+				},
+			}),
+			Overlay{
+				Name: "<overlay>",
+				Handlers: map[string]HandlerOverlay{
+					key: {Name: "<renamed>"},
+				},
+			},
+		)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		expected := "application <app> (59a82a24-a181-41e8-9b93-17a6ce86956e) *stubs.ApplicationStub\n"
+		expected += "\n"
+		expected += "    module <auditing>\n"
+		expected += "\n"
+		expected += "        - projection <renamed> (" + key + ") *stubs.ProjectionMessageHandlerStub\n"
+		expected += "            handles *stubs.EventStub[TypeA]!\n"
+
+		s := ToString(
+			res.Application,
+			WithPackageNames(),
+			WithShortTypeArgs(),
+		)
+
+		Expect(
+			strings.Split(s, "\n"),
+		).To(
+			Equal(strings.Split(expected, "\n")),
+		)
+	})
 })
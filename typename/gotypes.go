@@ -1,33 +1,38 @@
-package gotypes
+package typename
 
 import (
 	"fmt"
 	"go/types"
 )
 
-// NameOf returns the fully-qualified name of the given type.
-func NameOf(t types.Type) string {
-	switch t := t.(type) {
+// FromGoType returns the fully-qualified name of the given type.
+//
+// It returns the same name as [FromReflect] for the equivalent
+// [reflect.Type]. Type aliases are resolved to the types they denote.
+func FromGoType(t types.Type) string {
+	switch t := types.Unalias(t).(type) {
 	case *types.Basic:
 		return t.String()
 	case *types.Pointer:
-		return t.String()
+		return "*" + FromGoType(t.Elem())
 	case *types.Slice:
-		return t.String()
+		return "[]" + FromGoType(t.Elem())
 	case *types.Array:
-		return t.String()
+		return fmt.Sprintf("[%d]%s", t.Len(), FromGoType(t.Elem()))
 	case *types.Map:
-		return t.String()
+		return fmt.Sprintf("map[%s]%s", FromGoType(t.Key()), FromGoType(t.Elem()))
 	case *types.Chan:
-		return buildChanName(t)
+		return buildGoTypeChanName(t)
 	case *types.Interface:
-		return buildInterfaceName(t)
+		return buildGoTypeInterfaceName(t)
 	case *types.Named:
-		return t.String()
+		return buildGoTypeDefinedName(t)
+	case *types.TypeParam:
+		return t.Obj().Name()
 	case *types.Struct:
-		return buildStructName(t)
+		return buildGoTypeStructName(t)
 	case *types.Signature:
-		return "func" + buildFuncSignature(t)
+		return "func" + buildGoTypeFuncSignature(t)
 	}
 
 	// COVERAGE: This panic is only possible if an additional implementation of
@@ -38,8 +43,36 @@ func NameOf(t types.Type) string {
 	panic(fmt.Sprintf("unknown type %s", t))
 }
 
-func buildChanName(c *types.Chan) string {
-	elem := NameOf(c.Elem())
+func buildGoTypeDefinedName(t *types.Named) string {
+	var name string
+
+	obj := t.Obj()
+	if p := obj.Pkg(); p != nil {
+		name += p.Path()
+		name += "."
+	}
+
+	name += obj.Name()
+
+	if args := t.TypeArgs(); args.Len() > 0 {
+		name += "["
+
+		for i := 0; i < args.Len(); i++ {
+			if i > 0 {
+				name += ","
+			}
+
+			name += FromGoType(args.At(i))
+		}
+
+		name += "]"
+	}
+
+	return name
+}
+
+func buildGoTypeChanName(c *types.Chan) string {
+	elem := FromGoType(c.Elem())
 
 	switch c.Dir() {
 	case types.RecvOnly: // <-chan
@@ -51,7 +84,7 @@ func buildChanName(c *types.Chan) string {
 	}
 }
 
-func buildInterfaceName(t *types.Interface) string {
+func buildGoTypeInterfaceName(t *types.Interface) string {
 	name := "interface {"
 
 	n := t.NumMethods()
@@ -66,7 +99,7 @@ func buildInterfaceName(t *types.Interface) string {
 
 			m := t.Method(i)
 			name += m.Name()
-			name += buildFuncSignature(m.Type().(*types.Signature))
+			name += buildGoTypeFuncSignature(m.Type().(*types.Signature))
 		}
 
 		name += " "
@@ -77,7 +110,7 @@ func buildInterfaceName(t *types.Interface) string {
 	return name
 }
 
-func buildStructName(s *types.Struct) string {
+func buildGoTypeStructName(s *types.Struct) string {
 	name := "struct {"
 
 	n := s.NumFields()
@@ -97,7 +130,7 @@ func buildStructName(s *types.Struct) string {
 				name += " "
 			}
 
-			name += NameOf(f.Type())
+			name += FromGoType(f.Type())
 		}
 
 		name += " "
@@ -108,14 +141,14 @@ func buildStructName(s *types.Struct) string {
 	return name
 }
 
-func buildFuncSignature(s *types.Signature) string {
+func buildGoTypeFuncSignature(s *types.Signature) string {
 	name := "("
 	for i := 0; i < s.Params().Len(); i++ {
 		if i > 0 {
 			name += ", "
 		}
 
-		name += NameOf(s.Params().At(i).Type())
+		name += FromGoType(s.Params().At(i).Type())
 	}
 	name += ")"
 
@@ -131,7 +164,7 @@ func buildFuncSignature(s *types.Signature) string {
 				name += ", "
 			}
 
-			name += NameOf(s.Results().At(i).Type())
+			name += FromGoType(s.Results().At(i).Type())
 		}
 
 		if n > 1 {
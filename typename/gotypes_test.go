@@ -0,0 +1,123 @@
+package typename_test
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+
+	. "github.com/dogmatiq/configkit/typename"
+	. "github.com/dogmatiq/configkit/typename/internal/typenametest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("func FromGoType()", func() {
+	var varmap map[string]types.Type
+
+	BeforeSuite(func() {
+		bb, err := os.ReadFile("internal/typenametest/types.go")
+		Expect(err).ShouldNot(HaveOccurred())
+
+		fset := token.NewFileSet()
+
+		f, err := parser.ParseFile(
+			fset,
+			"types.go",
+			string(bb),
+			parser.ParseComments,
+		)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		conf := types.Config{Importer: importer.Default()}
+
+		pkg, err := conf.Check(
+			"github.com/dogmatiq/configkit/typename/internal/typenametest",
+			fset,
+			[]*ast.File{f},
+			nil,
+		)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		names := pkg.Scope().Names()
+
+		varmap = make(map[string]types.Type, len(names))
+
+		for _, name := range names {
+			if obj := pkg.Scope().Lookup(name); obj != nil {
+				varmap[obj.Name()] = obj.Type()
+			}
+		}
+	})
+
+	Declare(
+		func(file string) string {
+			switch file {
+			case "basic":
+				return FromGoType(varmap["Basic"])
+			case "named":
+				return FromGoType(varmap["Named"])
+			case "pointer":
+				return FromGoType(varmap["Pointer"])
+			case "slice":
+				return FromGoType(varmap["Slice"])
+			case "array":
+				return FromGoType(varmap["Array"])
+			case "map":
+				return FromGoType(varmap["Map"])
+			case "channel":
+				return FromGoType(varmap["Channel"])
+			case "receive-only-channel":
+				return FromGoType(varmap["ReceiveOnlyChannel"])
+			case "send-only-channel":
+				return FromGoType(varmap["SendOnlyChannel"])
+			case "iface":
+				return FromGoType(varmap["Interface"])
+			case "iface-with-single-method":
+				return FromGoType(varmap["InterfaceWithSingleMethod"])
+			case "iface-with-multiple-methods":
+				return FromGoType(varmap["InterfaceWithMultipleMethods"])
+			case "struct":
+				return FromGoType(varmap["Struct"])
+			case "struct-with-single-field":
+				return FromGoType(varmap["StructWithSingleField"])
+			case "struct-with-multiple-fields":
+				return FromGoType(varmap["StructWithMultipleFields"])
+			case "struct-with-embedded-type":
+				return FromGoType(varmap["StructWithEmbeddedType"])
+			case "nullary":
+				return FromGoType(varmap["Nullary"])
+			case "func-with-multiple-input-params":
+				return FromGoType(varmap["FuncWithMultipleInputParams"])
+			case "func-with-single-output-param":
+				return FromGoType(varmap["FuncWithSingleOutputParam"])
+			case "func-with-multiple-output-params":
+				return FromGoType(varmap["FuncWithMultipleOutputParams"])
+			case "generic-with-basic-arg":
+				return FromGoType(varmap["GenericWithBasicArg"])
+			case "generic-with-named-arg":
+				return FromGoType(varmap["GenericWithNamedArg"])
+			case "generic-with-foreign-arg":
+				return FromGoType(varmap["GenericWithForeignArg"])
+			case "generic-with-composite-arg":
+				return FromGoType(varmap["GenericWithCompositeArg"])
+			case "generic-with-multiple-args":
+				return FromGoType(varmap["GenericWithMultipleArgs"])
+			case "nested-generic":
+				return FromGoType(varmap["NestedGeneric"])
+			case "pointer-to-generic":
+				return FromGoType(varmap["PointerToGeneric"])
+			case "alias-of-named":
+				return FromGoType(varmap["AliasOfNamed"])
+			case "alias-of-generic":
+				return FromGoType(varmap["AliasOfGeneric"])
+			case "generic-with-alias-arg":
+				return FromGoType(varmap["GenericWithAliasArg"])
+			}
+
+			Fail("no type is available for testing with '%s' file")
+			return ""
+		})
+})
@@ -11,10 +11,10 @@ import (
 	"github.com/onsi/gomega"
 )
 
-// Declare declares a unit test-suite for functions "Of()" defined in "goreflect"
-// and "gotypes" packages.
+// Declare declares a unit test-suite for the naming functions defined in the
+// "typename" package.
 func Declare(fn func(string) string) {
-	dir := "../testdata"
+	dir := "testdata"
 
 	d, err := os.Open(dir)
 	if err != nil {
@@ -0,0 +1,73 @@
+package typenametest
+
+import "time"
+
+// TestType is the type used in "typename" package tests.
+type TestType struct{}
+
+// Generic is a generic type used in "typename" package tests.
+type Generic[T any] struct{}
+
+// Pair is a generic type with multiple type parameters used in "typename"
+// package tests.
+type Pair[K comparable, V any] struct{}
+
+// Alias is an alias of TestType.
+type Alias = TestType
+
+// GenericAlias is an alias of an instantiation of Generic.
+type GenericAlias = Generic[TestType]
+
+var (
+	Basic   string
+	Named   TestType
+	Pointer *TestType
+	Slice   []TestType
+	Array   [5]TestType
+	Map     map[int]TestType
+)
+
+var (
+	Channel            chan TestType
+	ReceiveOnlyChannel chan<- TestType
+	SendOnlyChannel    <-chan TestType
+)
+
+var (
+	Interface                    interface{}
+	InterfaceWithSingleMethod    interface{ Foo() }
+	InterfaceWithMultipleMethods interface {
+		Foo()
+		Bar()
+	}
+)
+
+var (
+	Struct                   struct{}
+	StructWithSingleField    struct{ F1 TestType }
+	StructWithMultipleFields struct {
+		F1 int
+		F2 TestType
+	}
+	StructWithEmbeddedType struct{ TestType }
+)
+
+var (
+	Nullary                      func()
+	FuncWithMultipleInputParams  func(int, TestType)
+	FuncWithSingleOutputParam    func(TestType) error
+	FuncWithMultipleOutputParams func(TestType) (bool, error)
+)
+
+var (
+	GenericWithBasicArg     Generic[int]
+	GenericWithNamedArg     Generic[TestType]
+	GenericWithForeignArg   Generic[time.Duration]
+	GenericWithCompositeArg Generic[map[string][]*TestType]
+	GenericWithMultipleArgs Pair[string, TestType]
+	NestedGeneric           Generic[*Generic[TestType]]
+	PointerToGeneric        *Generic[TestType]
+	AliasOfNamed            Alias
+	AliasOfGeneric          GenericAlias
+	GenericWithAliasArg     Generic[Alias]
+)
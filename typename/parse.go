@@ -0,0 +1,202 @@
+package typename
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Name is the structured form of a fully-qualified type name.
+type Name struct {
+	// Prefix is the sequence of type operators that precede the named type,
+	// such as "*", "[]", "[5]" or "chan ". It is empty if the type is not a
+	// pointer, slice, array or channel.
+	Prefix string
+
+	// Package is the path of the package that contains the type. It is empty
+	// for predeclared types, such as "int" or "error", and for types that are
+	// not named.
+	Package string
+
+	// Base is the name of the type within its package, without any type
+	// arguments.
+	//
+	// Map, function, struct and interface types are not decomposed. For such
+	// types, Base is the entire name, excluding the prefix.
+	Base string
+
+	// TypeArgs is the list of type arguments of a generic type.
+	TypeArgs []Name
+}
+
+// Parse parses a fully-qualified type name, as produced by [FromReflect] or
+// [FromGoType].
+//
+// Type arguments may be separated by a comma with or without trailing
+// whitespace, so names produced by reflect.Type.String() and
+// go/types.TypeString() are also accepted, provided they are qualified by
+// package path.
+func Parse(n string) (Name, error) {
+	name, err := parse(n)
+	if err != nil {
+		return Name{}, fmt.Errorf("invalid type name %q: %w", n, err)
+	}
+	return name, nil
+}
+
+// String returns the fully-qualified type name.
+func (n Name) String() string {
+	var w strings.Builder
+
+	w.WriteString(n.Prefix)
+	w.WriteString(n.QualifiedBase())
+
+	if len(n.TypeArgs) > 0 {
+		w.WriteByte('[')
+
+		for i, arg := range n.TypeArgs {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			w.WriteString(arg.String())
+		}
+
+		w.WriteByte(']')
+	}
+
+	return w.String()
+}
+
+// QualifiedBase returns the base name qualified by the package path, without
+// any prefix or type arguments.
+func (n Name) QualifiedBase() string {
+	if n.Package == "" {
+		return n.Base
+	}
+	return n.Package + "." + n.Base
+}
+
+// parse parses n without wrapping errors in context.
+func parse(n string) (Name, error) {
+	var name Name
+
+	n, name.Prefix = parsePrefix(n)
+
+	if n == "" {
+		return Name{}, errors.New("type name is empty")
+	}
+
+	for _, lit := range []string{"map[", "func(", "struct {", "interface {"} {
+		if strings.HasPrefix(n, lit) {
+			name.Base = n
+			return name, nil
+		}
+	}
+
+	qualified := n
+	if i := strings.IndexByte(n, '['); i != -1 {
+		if n[len(n)-1] != ']' {
+			return Name{}, errors.New("type argument list is not terminated")
+		}
+
+		args, err := splitTypeArgs(n[i+1 : len(n)-1])
+		if err != nil {
+			return Name{}, err
+		}
+
+		for _, a := range args {
+			arg, err := parse(a)
+			if err != nil {
+				return Name{}, err
+			}
+			name.TypeArgs = append(name.TypeArgs, arg)
+		}
+
+		qualified = n[:i]
+	}
+
+	// The base name follows the last dot, which must be within the final element
+	// of the package path.
+	slash := strings.LastIndexByte(qualified, '/')
+	if dot := strings.LastIndexByte(qualified, '.'); dot > slash {
+		name.Package = qualified[:dot]
+		name.Base = qualified[dot+1:]
+	} else if slash == -1 {
+		name.Base = qualified
+	} else {
+		return Name{}, errors.New("type name is not qualified by its package path")
+	}
+
+	if name.Base == "" {
+		return Name{}, errors.New("base name is empty")
+	}
+
+	if strings.ContainsAny(name.Base, " []") {
+		return Name{}, errors.New("base name contains invalid characters")
+	}
+
+	return name, nil
+}
+
+// parsePrefix splits the type operators from the start of n.
+func parsePrefix(n string) (rest, prefix string) {
+	rest = n
+
+	for {
+		switch {
+		case strings.HasPrefix(rest, "*"):
+			rest = rest[1:]
+		case strings.HasPrefix(rest, "[]"):
+			rest = rest[2:]
+		case strings.HasPrefix(rest, "<-chan "):
+			rest = rest[7:]
+		case strings.HasPrefix(rest, "chan<- "):
+			rest = rest[7:]
+		case strings.HasPrefix(rest, "chan "):
+			rest = rest[5:]
+		case strings.HasPrefix(rest, "["):
+			i := strings.IndexByte(rest, ']')
+			if i < 2 || strings.Trim(rest[1:i], "0123456789") != "" {
+				return rest, n[:len(n)-len(rest)]
+			}
+			rest = rest[i+1:]
+		default:
+			return rest, n[:len(n)-len(rest)]
+		}
+	}
+}
+
+// splitTypeArgs splits a comma-separated list of type arguments, ignoring any
+// commas that are nested within brackets, parentheses or braces.
+func splitTypeArgs(list string) ([]string, error) {
+	var (
+		args  []string
+		depth int
+		start int
+	)
+
+	for i, r := range list {
+		switch r {
+		case '[', '(', '{':
+			depth++
+		case ']', ')', '}':
+			depth--
+			if depth < 0 {
+				return nil, errors.New("unbalanced brackets in type argument list")
+			}
+		case ',':
+			if depth == 0 {
+				args = append(args, strings.TrimSpace(list[start:i]))
+				start = i + 1
+			}
+		}
+	}
+
+	if depth != 0 {
+		return nil, errors.New("unbalanced brackets in type argument list")
+	}
+
+	args = append(args, strings.TrimSpace(list[start:]))
+
+	return args, nil
+}
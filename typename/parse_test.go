@@ -0,0 +1,212 @@
+package typename_test
+
+import (
+	"reflect"
+
+	. "github.com/dogmatiq/configkit/typename"
+	"github.com/dogmatiq/configkit/typename/internal/typenametest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+const testPkg = "github.com/dogmatiq/configkit/typename/internal/typenametest"
+
+var _ = Describe("func Parse()", func() {
+	DescribeTable(
+		"it returns the structured form of the name",
+		func(n string, expect Name) {
+			name, err := Parse(n)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(name).To(Equal(expect))
+		},
+		Entry(
+			"predeclared type",
+			"int",
+			Name{Base: "int"},
+		),
+		Entry(
+			"type in a package with a single path element",
+			"time.Duration",
+			Name{Package: "time", Base: "Duration"},
+		),
+		Entry(
+			"type in a package with dots in its path",
+			testPkg+".TestType",
+			Name{Package: testPkg, Base: "TestType"},
+		),
+		Entry(
+			"pointer",
+			"*"+testPkg+".TestType",
+			Name{Prefix: "*", Package: testPkg, Base: "TestType"},
+		),
+		Entry(
+			"multiple type operators",
+			"[]*[5]<-chan "+testPkg+".TestType",
+			Name{Prefix: "[]*[5]<-chan ", Package: testPkg, Base: "TestType"},
+		),
+		Entry(
+			"generic type",
+			testPkg+".Generic[time.Duration]",
+			Name{
+				Package:  testPkg,
+				Base:     "Generic",
+				TypeArgs: []Name{{Package: "time", Base: "Duration"}},
+			},
+		),
+		Entry(
+			"generic type with multiple type arguments",
+			testPkg+".Pair[string,"+testPkg+".TestType]",
+			Name{
+				Package: testPkg,
+				Base:    "Pair",
+				TypeArgs: []Name{
+					{Base: "string"},
+					{Package: testPkg, Base: "TestType"},
+				},
+			},
+		),
+		Entry(
+			"generic type with whitespace between type arguments",
+			testPkg+".Pair[string, int]",
+			Name{
+				Package:  testPkg,
+				Base:     "Pair",
+				TypeArgs: []Name{{Base: "string"}, {Base: "int"}},
+			},
+		),
+		Entry(
+			"nested generic type",
+			"*"+testPkg+".Generic[*"+testPkg+".Pair[int,[]string]]",
+			Name{
+				Prefix:  "*",
+				Package: testPkg,
+				Base:    "Generic",
+				TypeArgs: []Name{
+					{
+						Prefix:  "*",
+						Package: testPkg,
+						Base:    "Pair",
+						TypeArgs: []Name{
+							{Base: "int"},
+							{Prefix: "[]", Base: "string"},
+						},
+					},
+				},
+			},
+		),
+		Entry(
+			"generic type with a map type argument",
+			testPkg+".Pair[map[string]int,int]",
+			Name{
+				Package: testPkg,
+				Base:    "Pair",
+				TypeArgs: []Name{
+					{Base: "map[string]int"},
+					{Base: "int"},
+				},
+			},
+		),
+		Entry(
+			"map",
+			"map[int]"+testPkg+".TestType",
+			Name{Base: "map[int]" + testPkg + ".TestType"},
+		),
+		Entry(
+			"function",
+			"*func(int) error",
+			Name{Prefix: "*", Base: "func(int) error"},
+		),
+	)
+
+	DescribeTable(
+		"it returns an error if the name is invalid",
+		func(n, expect string) {
+			_, err := Parse(n)
+			Expect(err).To(MatchError(expect))
+		},
+		Entry(
+			"empty",
+			"",
+			`invalid type name "": type name is empty`,
+		),
+		Entry(
+			"only type operators",
+			"*[]",
+			`invalid type name "*[]": type name is empty`,
+		),
+		Entry(
+			"unqualified path",
+			"github.com/dogmatiq/configkit",
+			`invalid type name "github.com/dogmatiq/configkit": type name is not qualified by its package path`,
+		),
+		Entry(
+			"empty base name",
+			"time.",
+			`invalid type name "time.": base name is empty`,
+		),
+		Entry(
+			"unterminated type arguments",
+			"pkg.Generic[int",
+			`invalid type name "pkg.Generic[int": type argument list is not terminated`,
+		),
+		Entry(
+			"unbalanced type arguments",
+			"pkg.Generic[int]]",
+			`invalid type name "pkg.Generic[int]]": unbalanced brackets in type argument list`,
+		),
+		Entry(
+			"invalid type argument",
+			"pkg.Generic[int,]",
+			`invalid type name "pkg.Generic[int,]": type name is empty`,
+		),
+	)
+
+	It("round-trips names produced by FromReflect()", func() {
+		types := []reflect.Type{
+			reflect.TypeOf(typenametest.Named),
+			reflect.TypeOf(typenametest.Slice),
+			reflect.TypeOf(typenametest.Map),
+			reflect.TypeOf(typenametest.GenericWithForeignArg),
+			reflect.TypeOf(typenametest.GenericWithCompositeArg),
+			reflect.TypeOf(typenametest.GenericWithMultipleArgs),
+			reflect.TypeOf(typenametest.NestedGeneric),
+			reflect.TypeOf(typenametest.PointerToGeneric),
+		}
+
+		for _, rt := range types {
+			n := FromReflect(rt)
+
+			name, err := Parse(n)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(name.String()).To(Equal(n))
+		}
+	})
+})
+
+var _ = Describe("type Name", func() {
+	Describe("func QualifiedBase()", func() {
+		It("returns the base name qualified by the package path", func() {
+			name := Name{
+				Prefix:   "*",
+				Package:  testPkg,
+				Base:     "Generic",
+				TypeArgs: []Name{{Base: "int"}},
+			}
+
+			Expect(name.QualifiedBase()).To(Equal(testPkg + ".Generic"))
+		})
+
+		It("returns the base name of predeclared types", func() {
+			Expect(Name{Base: "int"}.QualifiedBase()).To(Equal("int"))
+		})
+	})
+})
+
+var _ = Describe("func Of()", func() {
+	It("returns the fully-qualified name of the type parameter", func() {
+		Expect(Of[*typenametest.Generic[typenametest.TestType]]()).To(
+			Equal("*" + testPkg + ".Generic[" + testPkg + ".TestType]"),
+		)
+	})
+})
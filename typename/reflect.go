@@ -1,41 +1,41 @@
-package goreflect
+package typename
 
 import (
 	"fmt"
 	"reflect"
 )
 
-// NameOf returns the fully-qualified name of the given type.
+// FromReflect returns the fully-qualified name of the given type.
 //
-// The return value is similar to the string returned by ReflectType.String()
-// but shows fully-qualified package paths, not just package names.
-func NameOf(rt reflect.Type) string {
+// The names of generic types include their type arguments, which are also
+// fully-qualified, separated by commas without any whitespace.
+func FromReflect(rt reflect.Type) string {
 	if rt.Name() != "" {
-		return buildDefinedName(rt)
+		return buildReflectDefinedName(rt)
 	}
 
 	// only the "composite" types can be unnabled:
 	switch rt.Kind() {
 	case reflect.Ptr:
-		return fmt.Sprintf("*%s", NameOf(rt.Elem()))
+		return fmt.Sprintf("*%s", FromReflect(rt.Elem()))
 	case reflect.Slice:
-		return fmt.Sprintf("[]%s", NameOf(rt.Elem()))
+		return fmt.Sprintf("[]%s", FromReflect(rt.Elem()))
 	case reflect.Array:
-		return fmt.Sprintf("[%d]%s", rt.Len(), NameOf(rt.Elem()))
+		return fmt.Sprintf("[%d]%s", rt.Len(), FromReflect(rt.Elem()))
 	case reflect.Map:
-		return fmt.Sprintf("map[%s]%s", NameOf(rt.Key()), NameOf(rt.Elem()))
+		return fmt.Sprintf("map[%s]%s", FromReflect(rt.Key()), FromReflect(rt.Elem()))
 	case reflect.Chan:
-		return buildChanName(rt)
+		return buildReflectChanName(rt)
 	case reflect.Interface:
-		return buildInterfaceName(rt)
+		return buildReflectInterfaceName(rt)
 	case reflect.Struct:
-		return buildStructName(rt)
+		return buildReflectStructName(rt)
 	default: // reflect.Func
-		return "func" + buildFuncSignature(rt)
+		return "func" + buildReflectFuncSignature(rt)
 	}
 }
 
-func buildDefinedName(rt reflect.Type) string {
+func buildReflectDefinedName(rt reflect.Type) string {
 	var name string
 
 	if p := rt.PkgPath(); p != "" {
@@ -46,8 +46,8 @@ func buildDefinedName(rt reflect.Type) string {
 	return name + rt.Name()
 }
 
-func buildChanName(rt reflect.Type) string {
-	elem := NameOf(rt.Elem())
+func buildReflectChanName(rt reflect.Type) string {
+	elem := FromReflect(rt.Elem())
 
 	switch rt.ChanDir() {
 	case reflect.RecvDir: // <-chan
@@ -59,7 +59,7 @@ func buildChanName(rt reflect.Type) string {
 	}
 }
 
-func buildInterfaceName(rt reflect.Type) string {
+func buildReflectInterfaceName(rt reflect.Type) string {
 	name := "interface {"
 
 	n := rt.NumMethod()
@@ -75,7 +75,7 @@ func buildInterfaceName(rt reflect.Type) string {
 			m := rt.Method(i)
 
 			name += m.Name
-			name += buildFuncSignature(m.Type)
+			name += buildReflectFuncSignature(m.Type)
 		}
 
 		name += " "
@@ -86,7 +86,7 @@ func buildInterfaceName(rt reflect.Type) string {
 	return name
 }
 
-func buildStructName(rt reflect.Type) string {
+func buildReflectStructName(rt reflect.Type) string {
 	name := "struct {"
 
 	n := rt.NumField()
@@ -106,7 +106,7 @@ func buildStructName(rt reflect.Type) string {
 				name += " "
 			}
 
-			name += NameOf(f.Type)
+			name += FromReflect(f.Type)
 		}
 
 		name += " "
@@ -117,14 +117,14 @@ func buildStructName(rt reflect.Type) string {
 	return name
 }
 
-func buildFuncSignature(rt reflect.Type) string {
+func buildReflectFuncSignature(rt reflect.Type) string {
 	name := "("
 	for i := 0; i < rt.NumIn(); i++ {
 		if i > 0 {
 			name += ", "
 		}
 
-		name += NameOf(rt.In(i))
+		name += FromReflect(rt.In(i))
 	}
 	name += ")"
 
@@ -140,7 +140,7 @@ func buildFuncSignature(rt reflect.Type) string {
 				name += ", "
 			}
 
-			name += NameOf(rt.Out(i))
+			name += FromReflect(rt.Out(i))
 		}
 
 		if n > 1 {
@@ -0,0 +1,80 @@
+package typename_test
+
+import (
+	"reflect"
+
+	. "github.com/dogmatiq/configkit/typename"
+	. "github.com/dogmatiq/configkit/typename/internal/typenametest"
+	. "github.com/onsi/ginkgo"
+)
+
+var _ = Describe("func FromReflect()", func() {
+	Declare(
+		func(file string) string {
+			switch file {
+			case "basic":
+				return FromReflect(reflect.TypeOf(Basic))
+			case "named":
+				return FromReflect(reflect.TypeOf(Named))
+			case "pointer":
+				return FromReflect(reflect.TypeOf(Pointer))
+			case "slice":
+				return FromReflect(reflect.TypeOf(Slice))
+			case "array":
+				return FromReflect(reflect.TypeOf(Array))
+			case "map":
+				return FromReflect(reflect.TypeOf(Map))
+			case "channel":
+				return FromReflect(reflect.TypeOf(Channel))
+			case "receive-only-channel":
+				return FromReflect(reflect.TypeOf(ReceiveOnlyChannel))
+			case "send-only-channel":
+				return FromReflect(reflect.TypeOf(SendOnlyChannel))
+			case "iface":
+				return FromReflect(reflect.TypeOf(&Interface).Elem())
+			case "iface-with-single-method":
+				return FromReflect(reflect.TypeOf(&InterfaceWithSingleMethod).Elem())
+			case "iface-with-multiple-methods":
+				return FromReflect(reflect.TypeOf(&InterfaceWithMultipleMethods).Elem())
+			case "struct":
+				return FromReflect(reflect.TypeOf(Struct))
+			case "struct-with-single-field":
+				return FromReflect(reflect.TypeOf(StructWithSingleField))
+			case "struct-with-multiple-fields":
+				return FromReflect(reflect.TypeOf(StructWithMultipleFields))
+			case "struct-with-embedded-type":
+				return FromReflect(reflect.TypeOf(StructWithEmbeddedType))
+			case "nullary":
+				return FromReflect(reflect.TypeOf(Nullary))
+			case "func-with-multiple-input-params":
+				return FromReflect(reflect.TypeOf(FuncWithMultipleInputParams))
+			case "func-with-single-output-param":
+				return FromReflect(reflect.TypeOf(FuncWithSingleOutputParam))
+			case "func-with-multiple-output-params":
+				return FromReflect(reflect.TypeOf(FuncWithMultipleOutputParams))
+			case "generic-with-basic-arg":
+				return FromReflect(reflect.TypeOf(GenericWithBasicArg))
+			case "generic-with-named-arg":
+				return FromReflect(reflect.TypeOf(GenericWithNamedArg))
+			case "generic-with-foreign-arg":
+				return FromReflect(reflect.TypeOf(GenericWithForeignArg))
+			case "generic-with-composite-arg":
+				return FromReflect(reflect.TypeOf(GenericWithCompositeArg))
+			case "generic-with-multiple-args":
+				return FromReflect(reflect.TypeOf(GenericWithMultipleArgs))
+			case "nested-generic":
+				return FromReflect(reflect.TypeOf(NestedGeneric))
+			case "pointer-to-generic":
+				return FromReflect(reflect.TypeOf(PointerToGeneric))
+			case "alias-of-named":
+				return FromReflect(reflect.TypeOf(AliasOfNamed))
+			case "alias-of-generic":
+				return FromReflect(reflect.TypeOf(AliasOfGeneric))
+			case "generic-with-alias-arg":
+				return FromReflect(reflect.TypeOf(GenericWithAliasArg))
+			}
+
+			Fail("no type is available for testing with '%s' file")
+			return ""
+		})
+})
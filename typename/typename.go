@@ -0,0 +1,17 @@
+// Package typename produces fully-qualified names of Go types.
+//
+// The names produced from [reflect.Type] values and from [go/types.Type]
+// values are identical, including those of generic types, type aliases and
+// types nested within composite types. This allows names obtained at runtime
+// to be compared with names obtained by static analysis.
+//
+// Names are similar to those returned by reflect.Type.String(), except that
+// they use fully-qualified package paths rather than package names.
+package typename
+
+import "reflect"
+
+// Of returns the fully-qualified name of T.
+func Of[T any]() string {
+	return FromReflect(reflect.TypeFor[T]())
+}
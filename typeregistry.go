@@ -0,0 +1,101 @@
+package configkit
+
+import (
+	"reflect"
+
+	"github.com/dogmatiq/configkit/typename"
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/enginekit/message"
+)
+
+// TypeRegistry maps the fully-qualified names of Go types to the types
+// themselves.
+//
+// It is used by [Bind] to resolve the type names within a configuration that
+// was produced by [FromProto], such as one obtained from an API client.
+//
+// The zero-value is an empty registry, ready to use.
+type TypeRegistry struct {
+	types    map[string]reflect.Type
+	messages map[message.Name]message.Type
+}
+
+// Add adds the given types to the registry.
+//
+// Types that implement [dogma.Command], [dogma.Event] or [dogma.Timeout] are
+// also made available as message types. Nil types are ignored.
+func (r *TypeRegistry) Add(types ...reflect.Type) {
+	if r.types == nil {
+		r.types = map[string]reflect.Type{}
+		r.messages = map[message.Name]message.Type{}
+	}
+
+	for _, t := range types {
+		if t == nil {
+			continue
+		}
+
+		r.types[typename.FromReflect(t)] = t
+
+		if isMessageType(t) {
+			mt := message.TypeFromReflect(t)
+			r.messages[mt.Name()] = mt
+		}
+	}
+}
+
+// AddMessageTypes adds the Go types of the given message types to the
+// registry.
+func (r *TypeRegistry) AddMessageTypes(types ...dogma.RegisteredMessageType) {
+	for _, t := range types {
+		r.Add(t.GoType())
+	}
+}
+
+// AddRegisteredMessageTypes adds the Go types of all of the message types that
+// are registered with Dogma's message type registry.
+func (r *TypeRegistry) AddRegisteredMessageTypes() {
+	for t := range dogma.RegisteredMessageTypes() {
+		r.AddMessageTypes(t)
+	}
+}
+
+// AddApplication adds the Go types used to implement app, its handlers and
+// its messages to the registry.
+func (r *TypeRegistry) AddApplication(app RichApplication) {
+	r.Add(app.ReflectType())
+
+	for _, h := range app.RichHandlers() {
+		r.Add(h.ReflectType())
+	}
+
+	for mt := range app.MessageTypes() {
+		r.Add(mt.ReflectType())
+	}
+}
+
+// Lookup returns the Go type with the given fully-qualified name.
+func (r *TypeRegistry) Lookup(name string) (reflect.Type, bool) {
+	t, ok := r.types[name]
+	return t, ok
+}
+
+// LookupMessage returns the message type with the given name.
+func (r *TypeRegistry) LookupMessage(n message.Name) (message.Type, bool) {
+	t, ok := r.messages[n]
+	return t, ok
+}
+
+var (
+	commandInterface = reflect.TypeFor[dogma.Command]()
+	eventInterface   = reflect.TypeFor[dogma.Event]()
+	timeoutInterface = reflect.TypeFor[dogma.Timeout]()
+)
+
+// isMessageType returns true if t implements one of the Dogma message
+// interfaces.
+func isMessageType(t reflect.Type) bool {
+	return t.Implements(commandInterface) ||
+		t.Implements(eventInterface) ||
+		t.Implements(timeoutInterface)
+}
@@ -0,0 +1,121 @@
+package configkit_test
+
+import (
+	"reflect"
+
+	. "github.com/dogmatiq/configkit"
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	"github.com/dogmatiq/enginekit/message"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("type TypeRegistry", func() {
+	var registry *TypeRegistry
+
+	BeforeEach(func() {
+		registry = &TypeRegistry{}
+	})
+
+	Describe("func Add()", func() {
+		It("adds the types to the registry", func() {
+			t := reflect.TypeFor[*AggregateMessageHandlerStub]()
+			registry.Add(t)
+
+			n := "*github.com/dogmatiq/enginekit/enginetest/stubs.AggregateMessageHandlerStub"
+
+			x, ok := registry.Lookup(n)
+			Expect(ok).To(BeTrue())
+			Expect(x).To(Equal(t))
+
+			_, ok = registry.LookupMessage(message.Name(n))
+			Expect(ok).To(BeFalse())
+		})
+
+		It("adds message types as messages", func() {
+			registry.Add(reflect.TypeFor[*EventStub[TypeA]]())
+
+			x, ok := registry.LookupMessage(message.NameOf(EventA1))
+			Expect(ok).To(BeTrue())
+			Expect(x).To(Equal(message.TypeOf(EventA1)))
+		})
+
+		It("ignores nil types", func() {
+			Expect(func() {
+				registry.Add(nil)
+			}).NotTo(Panic())
+		})
+	})
+
+	Describe("func AddMessageTypes()", func() {
+		It("adds the Go types of the message types", func() {
+			t, ok := dogma.RegisteredMessageTypeFor[*CommandStub[TypeA]]()
+			Expect(ok).To(BeTrue())
+
+			registry.AddMessageTypes(t)
+
+			x, ok := registry.LookupMessage(message.NameOf(CommandA1))
+			Expect(ok).To(BeTrue())
+			Expect(x).To(Equal(message.TypeOf(CommandA1)))
+		})
+	})
+
+	Describe("func AddRegisteredMessageTypes()", func() {
+		It("adds the Go types of all registered message types", func() {
+			registry.AddRegisteredMessageTypes()
+
+			for _, m := range []dogma.Message{CommandA1, EventB1, TimeoutC1} {
+				_, ok := registry.LookupMessage(message.NameOf(m))
+				Expect(ok).To(BeTrue())
+			}
+		})
+	})
+
+	Describe("func AddApplication()", func() {
+		It("adds the types used by the application", func() {
+			app := FromApplication(&ApplicationStub{
+				ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+					c.Identity("<app>", appKey)
+					c.Routes(
+						dogma.ViaIntegration(&IntegrationMessageHandlerStub{
+							ConfigureFunc: func(c dogma.IntegrationConfigurer) {
+								c.Identity("<integration>", integrationKey)
+								c.Routes(
+									dogma.HandlesCommand[*CommandStub[TypeA]](),
+								)
+							},
+						}),
+					)
+				},
+			})
+
+			registry.AddApplication(app)
+
+			_, ok := registry.Lookup(app.TypeName())
+			Expect(ok).To(BeTrue())
+
+			for _, h := range app.Handlers() {
+				_, ok := registry.Lookup(h.TypeName())
+				Expect(ok).To(BeTrue())
+			}
+
+			_, ok = registry.LookupMessage(message.NameOf(CommandA1))
+			Expect(ok).To(BeTrue())
+		})
+	})
+
+	Describe("func Lookup()", func() {
+		It("returns false if the type is not in the registry", func() {
+			_, ok := registry.Lookup("<unknown>")
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Describe("func LookupMessage()", func() {
+		It("returns false if the message type is not in the registry", func() {
+			_, ok := registry.LookupMessage("<unknown>")
+			Expect(ok).To(BeFalse())
+		})
+	})
+})